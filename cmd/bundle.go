@@ -0,0 +1,410 @@
+package cmd
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"helm.sh/helm/v4/pkg/cli"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/huangxiaofeng10047/go-cli-example/cmd/i18n"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content/oci"
+	"oras.land/oras-go/v2/registry/remote"
+	"oras.land/oras-go/v2/registry/remote/auth"
+	"oras.land/oras-go/v2/registry/remote/credentials"
+	"oras.land/oras-go/v2/registry/remote/retry"
+
+	"helm.sh/helm/v4/cmd/helm/require"
+	"helm.sh/helm/v4/pkg/action"
+	chartutil "helm.sh/helm/v4/pkg/chart/v2/util"
+	"helm.sh/helm/v4/pkg/cli/values"
+)
+
+// Paths inside a bundle.
+const (
+	bundleManifestFile = "bundle.json"
+	bundleChartDir     = "chart"
+	bundleImagesDir    = "images"
+)
+
+// bundleManifest describes the content of a bundle.
+type bundleManifest struct {
+	Chart          string        `json:"chart"`
+	ChartName      string        `json:"chartName"`
+	ChartVersion   string        `json:"chartVersion"`
+	RegistryPrefix string        `json:"registryPrefix,omitempty"`
+	Images         []bundleImage `json:"images"`
+	Created        time.Time     `json:"created"`
+}
+
+// bundleImage is an image stored in the OCI layout of a bundle, tagged with
+// its source reference.
+type bundleImage struct {
+	Source string `json:"source"`
+	Target string `json:"target,omitempty"`
+}
+
+// registryOptions are the connection options for image registries.
+type registryOptions struct {
+	plainHTTP bool
+	platform  string
+}
+
+func (o *registryOptions) addFlags(f *pflag.FlagSet) {
+	f.BoolVar(&o.plainHTTP, "plain-http-images", false, i18n.T("bundle.flagPlainHTTP"))
+}
+
+var (
+	// fromBundle is set by install --from-bundle.
+	fromBundle string
+	// bundleRegistryPrefix overrides the registry prefix of the bundle.
+	bundleRegistryPrefix string
+	// pushImages pushes the images of the bundle before installing.
+	pushImages bool
+	// bundleRegistry holds the registry options used to push bundle images.
+	bundleRegistry registryOptions
+)
+
+func addBundleInstallFlags(f *pflag.FlagSet) {
+	f.StringVar(&fromBundle, "from-bundle", "", i18n.T("bundle.flagFromBundle"))
+	f.StringVar(&bundleRegistryPrefix, "registry-prefix", "", i18n.T("bundle.flagInstallRegistryPrefix"))
+	f.BoolVar(&pushImages, "push-images", false, i18n.T("bundle.flagPushImages"))
+	bundleRegistry.addFlags(f)
+}
+
+func newImagesBundleCmd(settings *cli.EnvSettings, out io.Writer, debug action.DebugLog) *cobra.Command {
+	valueOpts := &values.Options{}
+	chartOpts := &action.ChartPathOptions{}
+	regOpts := &registryOptions{}
+	var prefix, file string
+
+	cmd := &cobra.Command{
+		Use:   "bundle CHART",
+		Short: i18n.T("bundle.short"),
+		Long:  i18n.T("bundle.long"),
+		Args:  require.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			ch, vals, err := loadChartWithValues(settings, chartOpts, valueOpts, args[0], debug)
+			if err != nil {
+				return err
+			}
+			manifests, err := renderChartOffline(ch, vals, offlineRender{releaseName: ch.Name(), namespace: settings.Namespace()}, debug)
+			if err != nil {
+				return err
+			}
+			objs, err := parseRenderedManifests(manifests)
+			if err != nil {
+				return err
+			}
+
+			dir, err := os.MkdirTemp("", "bundle-")
+			if err != nil {
+				return err
+			}
+			defer os.RemoveAll(dir)
+
+			m := &bundleManifest{
+				ChartName:      ch.Name(),
+				ChartVersion:   ch.Metadata.Version,
+				RegistryPrefix: prefix,
+				Created:        time.Now().UTC(),
+			}
+			if err := os.MkdirAll(filepath.Join(dir, bundleChartDir), 0755); err != nil {
+				return err
+			}
+			chartFile, err := chartutil.Save(ch, filepath.Join(dir, bundleChartDir))
+			if err != nil {
+				return err
+			}
+			m.Chart = filepath.ToSlash(filepath.Join(bundleChartDir, filepath.Base(chartFile)))
+
+			store, err := oci.New(filepath.Join(dir, bundleImagesDir))
+			if err != nil {
+				return err
+			}
+			ctx := context.Background()
+			for _, image := range collectImages(objs) {
+				fmt.Fprintln(out, i18n.T("bundle.pulling", image.Image))
+				if err := copyImage(ctx, settings, regOpts, image.Image, store, image.Image, true); err != nil {
+					return errors.Wrap(err, i18n.T("bundle.pull", image.Image))
+				}
+				bi := bundleImage{Source: image.Image}
+				if prefix != "" {
+					bi.Target = retargetImage(image.Image, prefix)
+				}
+				m.Images = append(m.Images, bi)
+			}
+
+			b, err := json.MarshalIndent(m, "", "  ")
+			if err != nil {
+				return err
+			}
+			if err := os.WriteFile(filepath.Join(dir, bundleManifestFile), b, 0644); err != nil {
+				return err
+			}
+
+			if file == "" {
+				file = fmt.Sprintf("%s-%s-bundle.tgz", ch.Name(), ch.Metadata.Version)
+			}
+			if err := tarDirectory(dir, file); err != nil {
+				return err
+			}
+			fmt.Fprintln(out, i18n.T("bundle.written", len(m.Images), file))
+			return nil
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVar(&prefix, "registry-prefix", "", i18n.T("bundle.flagRegistryPrefix"))
+	f.StringVar(&file, "file", "", i18n.T("bundle.flagFile"))
+	f.StringVar(&regOpts.platform, "platform", "", i18n.T("bundle.flagPlatform"))
+	regOpts.addFlags(f)
+	addValueOptionsFlags(f, valueOpts)
+	addChartPathOptionsFlags(f, chartOpts)
+
+	return cmd
+}
+
+// openBundle extracts a bundle into a temporary directory. The caller removes
+// the directory when done.
+func openBundle(file string) (string, *bundleManifest, error) {
+	dir, err := os.MkdirTemp("", "bundle-")
+	if err != nil {
+		return "", nil, err
+	}
+	if err := untarFile(file, dir); err != nil {
+		os.RemoveAll(dir)
+		return "", nil, errors.Wrap(err, i18n.T("bundle.extract", file))
+	}
+	b, err := os.ReadFile(filepath.Join(dir, bundleManifestFile))
+	if err != nil {
+		os.RemoveAll(dir)
+		return "", nil, errors.Wrap(err, i18n.T("bundle.notBundle", file))
+	}
+	m := &bundleManifest{}
+	if err := json.Unmarshal(b, m); err != nil {
+		os.RemoveAll(dir)
+		return "", nil, errors.Wrap(err, i18n.T("bundle.parse", bundleManifestFile, file))
+	}
+	return dir, m, nil
+}
+
+// prepareBundleInstall extracts --from-bundle and returns the path of its
+// chart. Images are pushed when --push-images is set and the rendered
+// manifests are retargeted to the registry prefix.
+func prepareBundleInstall(settings *cli.EnvSettings, client *action.Install, out io.Writer) (string, func(), error) {
+	dir, m, err := openBundle(fromBundle)
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup := func() { os.RemoveAll(dir) }
+
+	prefix := bundleRegistryPrefix
+	if prefix == "" {
+		prefix = m.RegistryPrefix
+	}
+	if prefix == "" {
+		return filepath.Join(dir, m.Chart), cleanup, nil
+	}
+
+	targets := map[string]string{}
+	for _, image := range m.Images {
+		targets[image.Source] = retargetImage(image.Source, prefix)
+	}
+
+	if pushImages && !client.ClientOnly {
+		store, err := oci.New(filepath.Join(dir, bundleImagesDir))
+		if err != nil {
+			cleanup()
+			return "", nil, err
+		}
+		ctx := context.Background()
+		for _, image := range m.Images {
+			target := targets[image.Source]
+			fmt.Fprintln(out, i18n.T("bundle.pushing", target))
+			if err := copyImage(ctx, settings, &bundleRegistry, target, store, image.Source, false); err != nil {
+				cleanup()
+				return "", nil, errors.Wrap(err, i18n.T("bundle.push", target))
+			}
+		}
+	}
+
+	client.PostRenderer = &imageRetargeter{images: targets, next: client.PostRenderer}
+	return filepath.Join(dir, m.Chart), cleanup, nil
+}
+
+// copyImage copies an image between a registry and an OCI layout, in the
+// direction given by pull. storeRef is the tag of the image in the layout.
+func copyImage(ctx context.Context, settings *cli.EnvSettings, opts *registryOptions, image string, store *oci.Store, storeRef string, pull bool) error {
+	registry, repository, ref := splitImage(image)
+	host := registry
+	if host == dockerHub {
+		host = "registry-1.docker.io"
+	}
+	repo, err := newRemoteRepository(settings, host+"/"+repository, opts.plainHTTP)
+	if err != nil {
+		return err
+	}
+
+	copyOpts := oras.DefaultCopyOptions
+	if opts.platform != "" {
+		p, err := parsePlatform(opts.platform)
+		if err != nil {
+			return err
+		}
+		copyOpts.WithTargetPlatform(p)
+	}
+	if pull {
+		_, err = oras.Copy(ctx, repo, ref, store, storeRef, copyOpts)
+	} else {
+		_, err = oras.Copy(ctx, store, storeRef, repo, ref, copyOpts)
+	}
+	return err
+}
+
+// newRemoteRepository returns a client for a registry repository, given as
+// HOST/PATH, that authenticates like 'helm registry login' does.
+func newRemoteRepository(settings *cli.EnvSettings, reference string, plainHTTP bool) (*remote.Repository, error) {
+	repo, err := remote.NewRepository(reference)
+	if err != nil {
+		return nil, err
+	}
+	repo.PlainHTTP = plainHTTP
+
+	// Credentials from 'registry login' first, then from the Docker config.
+	helmCreds, err := credentials.NewStore(settings.RegistryConfig, credentials.StoreOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var creds credentials.Store = helmCreds
+	if docker, err := credentials.NewStoreFromDocker(credentials.StoreOptions{}); err == nil {
+		creds = credentials.NewStoreWithFallbacks(helmCreds, docker)
+	}
+	repo.Client = &auth.Client{
+		Client:     retry.DefaultClient,
+		Cache:      auth.NewCache(),
+		Credential: credentials.Credential(creds),
+	}
+	return repo, nil
+}
+
+// parsePlatform parses OS/ARCH[/VARIANT].
+func parsePlatform(s string) (*ocispec.Platform, error) {
+	parts := strings.Split(s, "/")
+	if len(parts) < 2 || len(parts) > 3 {
+		return nil, errors.New(i18n.T("bundle.badPlatform", s))
+	}
+	p := &ocispec.Platform{OS: parts[0], Architecture: parts[1]}
+	if len(parts) == 3 {
+		p.Variant = parts[2]
+	}
+	return p, nil
+}
+
+// tarDirectory writes the content of dir to a gzipped tarball.
+func tarDirectory(dir, file string) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+
+	err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || path == dir {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		hdr, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(rel)
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		src, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+		_, err = io.Copy(tw, src)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// untarFile extracts a gzipped tarball into dir.
+func untarFile(file, dir string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return err
+	}
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dir, filepath.FromSlash(hdr.Name))
+		if target == filepath.Clean(dir) {
+			continue
+		}
+		if !strings.HasPrefix(target, filepath.Clean(dir)+string(filepath.Separator)) {
+			return errors.New(i18n.T("bundle.illegalPath", hdr.Name))
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+			if err != nil {
+				return err
+			}
+			if _, err := io.Copy(out, tr); err != nil {
+				out.Close()
+				return err
+			}
+			if err := out.Close(); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,292 @@
+package cmd
+
+import (
+	"fmt"
+	"helm.sh/helm/v4/pkg/cli"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/gosuri/uitable"
+	"github.com/huangxiaofeng10047/go-cli-example/cmd/i18n"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"helm.sh/helm/v4/cmd/helm/require"
+	"helm.sh/helm/v4/pkg/action"
+	chart "helm.sh/helm/v4/pkg/chart/v2"
+	chartutil "helm.sh/helm/v4/pkg/chart/v2/util"
+	"helm.sh/helm/v4/pkg/cli/output"
+	"helm.sh/helm/v4/pkg/cli/values"
+	"helm.sh/helm/v4/pkg/postrender"
+)
+
+// apiLifecycle describes when an API version of a kind stops being served.
+type apiLifecycle struct {
+	apiVersion   string
+	kind         string
+	deprecatedIn string
+	removedIn    string
+	replacement  string
+}
+
+// deprecatedAPIs is based on the Kubernetes deprecated API migration guide.
+var deprecatedAPIs = []apiLifecycle{
+	{"extensions/v1beta1", "Deployment", "v1.9", "v1.16", "apps/v1"},
+	{"extensions/v1beta1", "DaemonSet", "v1.9", "v1.16", "apps/v1"},
+	{"extensions/v1beta1", "ReplicaSet", "v1.9", "v1.16", "apps/v1"},
+	{"extensions/v1beta1", "NetworkPolicy", "v1.9", "v1.16", "networking.k8s.io/v1"},
+	{"extensions/v1beta1", "PodSecurityPolicy", "v1.10", "v1.16", "policy/v1beta1"},
+	{"apps/v1beta1", "Deployment", "v1.9", "v1.16", "apps/v1"},
+	{"apps/v1beta1", "StatefulSet", "v1.9", "v1.16", "apps/v1"},
+	{"apps/v1beta2", "Deployment", "v1.9", "v1.16", "apps/v1"},
+	{"apps/v1beta2", "StatefulSet", "v1.9", "v1.16", "apps/v1"},
+	{"apps/v1beta2", "DaemonSet", "v1.9", "v1.16", "apps/v1"},
+	{"apps/v1beta2", "ReplicaSet", "v1.9", "v1.16", "apps/v1"},
+	{"extensions/v1beta1", "Ingress", "v1.14", "v1.22", "networking.k8s.io/v1"},
+	{"networking.k8s.io/v1beta1", "Ingress", "v1.19", "v1.22", "networking.k8s.io/v1"},
+	{"networking.k8s.io/v1beta1", "IngressClass", "v1.19", "v1.22", "networking.k8s.io/v1"},
+	{"rbac.authorization.k8s.io/v1beta1", "Role", "v1.17", "v1.22", "rbac.authorization.k8s.io/v1"},
+	{"rbac.authorization.k8s.io/v1beta1", "RoleBinding", "v1.17", "v1.22", "rbac.authorization.k8s.io/v1"},
+	{"rbac.authorization.k8s.io/v1beta1", "ClusterRole", "v1.17", "v1.22", "rbac.authorization.k8s.io/v1"},
+	{"rbac.authorization.k8s.io/v1beta1", "ClusterRoleBinding", "v1.17", "v1.22", "rbac.authorization.k8s.io/v1"},
+	{"apiextensions.k8s.io/v1beta1", "CustomResourceDefinition", "v1.16", "v1.22", "apiextensions.k8s.io/v1"},
+	{"apiregistration.k8s.io/v1beta1", "APIService", "v1.19", "v1.22", "apiregistration.k8s.io/v1"},
+	{"admissionregistration.k8s.io/v1beta1", "MutatingWebhookConfiguration", "v1.16", "v1.22", "admissionregistration.k8s.io/v1"},
+	{"admissionregistration.k8s.io/v1beta1", "ValidatingWebhookConfiguration", "v1.16", "v1.22", "admissionregistration.k8s.io/v1"},
+	{"scheduling.k8s.io/v1beta1", "PriorityClass", "v1.14", "v1.22", "scheduling.k8s.io/v1"},
+	{"certificates.k8s.io/v1beta1", "CertificateSigningRequest", "v1.19", "v1.22", "certificates.k8s.io/v1"},
+	{"coordination.k8s.io/v1beta1", "Lease", "v1.14", "v1.22", "coordination.k8s.io/v1"},
+	{"storage.k8s.io/v1beta1", "CSIDriver", "v1.19", "v1.22", "storage.k8s.io/v1"},
+	{"storage.k8s.io/v1beta1", "CSINode", "v1.17", "v1.22", "storage.k8s.io/v1"},
+	{"storage.k8s.io/v1beta1", "StorageClass", "v1.6", "v1.22", "storage.k8s.io/v1"},
+	{"storage.k8s.io/v1beta1", "VolumeAttachment", "v1.13", "v1.22", "storage.k8s.io/v1"},
+	{"batch/v1beta1", "CronJob", "v1.21", "v1.25", "batch/v1"},
+	{"discovery.k8s.io/v1beta1", "EndpointSlice", "v1.21", "v1.25", "discovery.k8s.io/v1"},
+	{"events.k8s.io/v1beta1", "Event", "v1.19", "v1.25", "events.k8s.io/v1"},
+	{"autoscaling/v2beta1", "HorizontalPodAutoscaler", "v1.22", "v1.25", "autoscaling/v2"},
+	{"policy/v1beta1", "PodDisruptionBudget", "v1.21", "v1.25", "policy/v1"},
+	{"policy/v1beta1", "PodSecurityPolicy", "v1.21", "v1.25", "none, use Pod Security Admission"},
+	{"node.k8s.io/v1beta1", "RuntimeClass", "v1.20", "v1.25", "node.k8s.io/v1"},
+	{"autoscaling/v2beta2", "HorizontalPodAutoscaler", "v1.23", "v1.26", "autoscaling/v2"},
+	{"flowcontrol.apiserver.k8s.io/v1beta1", "FlowSchema", "v1.23", "v1.26", "flowcontrol.apiserver.k8s.io/v1"},
+	{"flowcontrol.apiserver.k8s.io/v1beta1", "PriorityLevelConfiguration", "v1.23", "v1.26", "flowcontrol.apiserver.k8s.io/v1"},
+	{"storage.k8s.io/v1beta1", "CSIStorageCapacity", "v1.24", "v1.27", "storage.k8s.io/v1"},
+	{"flowcontrol.apiserver.k8s.io/v1beta2", "FlowSchema", "v1.26", "v1.29", "flowcontrol.apiserver.k8s.io/v1"},
+	{"flowcontrol.apiserver.k8s.io/v1beta2", "PriorityLevelConfiguration", "v1.26", "v1.29", "flowcontrol.apiserver.k8s.io/v1"},
+	{"flowcontrol.apiserver.k8s.io/v1beta3", "FlowSchema", "v1.29", "v1.32", "flowcontrol.apiserver.k8s.io/v1"},
+	{"flowcontrol.apiserver.k8s.io/v1beta3", "PriorityLevelConfiguration", "v1.29", "v1.32", "flowcontrol.apiserver.k8s.io/v1"},
+}
+
+// Status of an API version in the target Kubernetes version.
+const (
+	apiDeprecated = "deprecated"
+	apiRemoved    = "removed"
+)
+
+// apiFinding is an object that uses a deprecated or removed API version.
+type apiFinding struct {
+	Kind         string `json:"kind"`
+	Name         string `json:"name"`
+	Source       string `json:"source,omitempty"`
+	APIVersion   string `json:"apiVersion"`
+	Status       string `json:"status"`
+	DeprecatedIn string `json:"deprecatedIn"`
+	RemovedIn    string `json:"removedIn"`
+	Replacement  string `json:"replacement"`
+}
+
+func newCheckAPIsCmd(settings *cli.EnvSettings, cfg *action.Configuration, out io.Writer, debug action.DebugLog) *cobra.Command {
+	valueOpts := &values.Options{}
+	chartOpts := &action.ChartPathOptions{}
+	var outfmt output.Format
+	var kubeVersion string
+	var extraAPIs []string
+
+	cmd := &cobra.Command{
+		Use:   "check-apis RELEASE|CHART",
+		Short: i18n.T("checkAPIs.short"),
+		Long:  i18n.T("checkAPIs.long"),
+		Args:  require.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			if kubeVersion == "" {
+				return errors.New(i18n.T("checkAPIs.noKubeVersion"))
+			}
+			target, err := chartutil.ParseKubeVersion(kubeVersion)
+			if err != nil {
+				return fmt.Errorf("%s: %s", i18n.T("error.badKubeVersion", kubeVersion), err)
+			}
+			manifests, err := releaseOrChartManifests(settings, cfg, chartOpts, valueOpts, args[0], offlineRender{
+				kubeVersion: kubeVersion,
+				apiVersions: extraAPIs,
+			}, debug)
+			if err != nil {
+				return err
+			}
+			findings, err := checkAPIs(manifests, target.Version)
+			if err != nil {
+				return err
+			}
+			if err := outfmt.Write(out, &apiFindingWriter{findings}); err != nil {
+				return err
+			}
+			return removedAPIsError(findings, target.Version)
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVar(&kubeVersion, "kube-version", "", i18n.T("checkAPIs.flagKubeVersion"))
+	f.StringSliceVarP(&extraAPIs, "api-versions", "a", []string{}, i18n.T("checkAPIs.flagAPIVersions"))
+	addValueOptionsFlags(f, valueOpts)
+	addChartPathOptionsFlags(f, chartOpts)
+	bindOutputFlag(cmd, &outfmt)
+
+	return cmd
+}
+
+// checkAPIs returns the objects in manifests that use an API version that is
+// deprecated or removed in kubeVersion.
+func checkAPIs(manifests []string, kubeVersion string) ([]apiFinding, error) {
+	v, err := semver.NewVersion(kubeVersion)
+	if err != nil {
+		return nil, err
+	}
+	// APIs are removed in minor releases. Provider suffixes such as
+	// v1.25.0-eks-4096722 are prereleases to semver and would sort before
+	// v1.25, so only the major and minor version are compared.
+	target := semver.New(v.Major(), v.Minor(), 0, "", "")
+	objs, err := parseRenderedManifests(manifests)
+	if err != nil {
+		return nil, err
+	}
+
+	findings := []apiFinding{}
+	for _, o := range objs {
+		for _, api := range deprecatedAPIs {
+			if api.apiVersion != o.APIVersion || api.kind != o.Kind {
+				continue
+			}
+			status := ""
+			switch {
+			case !target.LessThan(semver.MustParse(api.removedIn)):
+				status = apiRemoved
+			case !target.LessThan(semver.MustParse(api.deprecatedIn)):
+				status = apiDeprecated
+			default:
+				continue
+			}
+			findings = append(findings, apiFinding{
+				Kind:         o.Kind,
+				Name:         o.Metadata.Name,
+				Source:       o.source,
+				APIVersion:   o.APIVersion,
+				Status:       status,
+				DeprecatedIn: api.deprecatedIn,
+				RemovedIn:    api.removedIn,
+				Replacement:  api.replacement,
+			})
+		}
+	}
+	return findings, nil
+}
+
+// removedAPIsError returns an error when a finding is removed in the target
+// version.
+func removedAPIsError(findings []apiFinding, kubeVersion string) error {
+	var removed []string
+	for _, f := range findings {
+		if f.Status == apiRemoved {
+			removed = append(removed, fmt.Sprintf("%s/%s (%s)", f.Kind, f.Name, f.APIVersion))
+		}
+	}
+	if len(removed) > 0 {
+		return errors.New(i18n.T("checkAPIs.removed", len(removed), kubeVersion, strings.Join(removed, ", ")))
+	}
+	return nil
+}
+
+type apiFindingWriter struct {
+	findings []apiFinding
+}
+
+func (w *apiFindingWriter) WriteTable(out io.Writer) error {
+	if len(w.findings) == 0 {
+		_, err := fmt.Fprintln(out, i18n.T("checkAPIs.none"))
+		return err
+	}
+	table := uitable.New()
+	table.AddRow("OBJECT", "API VERSION", "STATUS", "DEPRECATED IN", "REMOVED IN", "REPLACEMENT", "SOURCE")
+	for _, f := range w.findings {
+		table.AddRow(f.Kind+"/"+f.Name, f.APIVersion, f.Status, f.DeprecatedIn, f.RemovedIn, f.Replacement, f.Source)
+	}
+	return output.EncodeTable(out, table)
+}
+
+func (w *apiFindingWriter) WriteJSON(out io.Writer) error {
+	return output.EncodeJSON(out, w.findings)
+}
+
+func (w *apiFindingWriter) WriteYAML(out io.Writer) error {
+	return output.EncodeYAML(out, w.findings)
+}
+
+// preflightAPIs checks the chart that is about to be deployed, and the
+// currently deployed revision, for APIs removed in kubeVersion. When
+// kubeVersion is empty the version of the cluster is used. Findings are
+// written to stderr.
+func preflightAPIs(cfg *action.Configuration, ch *chart.Chart, vals map[string]interface{}, releaseName, namespace, kubeVersion string, pr postrender.PostRenderer, debug action.DebugLog) error {
+	if kubeVersion == "" {
+		clientset, err := cfg.KubernetesClientSet()
+		if err != nil {
+			return err
+		}
+		info, err := clientset.Discovery().ServerVersion()
+		if err != nil {
+			return errors.Wrap(err, i18n.T("checkAPIs.serverVersion"))
+		}
+		kubeVersion = info.GitVersion
+	}
+	target, err := chartutil.ParseKubeVersion(kubeVersion)
+	if err != nil {
+		return fmt.Errorf("%s: %s", i18n.T("error.badKubeVersion", kubeVersion), err)
+	}
+	debug("checking APIs against Kubernetes %s", target.Version)
+
+	manifests, err := renderChartOffline(ch, vals, offlineRender{
+		releaseName:  releaseName,
+		namespace:    namespace,
+		isUpgrade:    true,
+		kubeVersion:  target.Version,
+		postRenderer: pr,
+	}, debug)
+	if err != nil {
+		return errors.Wrap(err, i18n.T("checkAPIs.render"))
+	}
+	findings, err := checkAPIs(manifests, target.Version)
+	if err != nil {
+		return err
+	}
+
+	// Helm has to read the deployed manifest to compute the upgrade, which
+	// fails when it contains kinds the cluster no longer serves.
+	var current []apiFinding
+	if rel, err := action.NewGet(cfg).Run(releaseName); err == nil {
+		if current, err = checkAPIs(releaseManifests(rel), target.Version); err != nil {
+			return err
+		}
+	}
+
+	if len(findings)+len(current) > 0 {
+		if err := output.Table.Write(os.Stderr, &apiFindingWriter{append(findings, current...)}); err != nil {
+			return err
+		}
+	}
+	if err := removedAPIsError(findings, target.Version); err != nil {
+		return err
+	}
+	if err := removedAPIsError(current, target.Version); err != nil {
+		return errors.Wrap(err, i18n.T("checkAPIs.deployedRemoved"))
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+func TestCheckAPIsKubeVersion(t *testing.T) {
+	manifests := []string{
+		"# Source: mini/templates/psp.yaml\napiVersion: policy/v1beta1\nkind: PodSecurityPolicy\nmetadata:\n  name: restricted\n",
+	}
+
+	tests := []struct {
+		kubeVersion string
+		want        string
+	}{
+		{"v1.20.15", ""},
+		{"v1.21.0", apiDeprecated},
+		{"v1.24.17-gke.100", apiDeprecated},
+		{"v1.25.0", apiRemoved},
+		{"v1.25.0-eks-4096722", apiRemoved},
+		{"v1.25.0-rc.1", apiRemoved},
+		{"1.30", apiRemoved},
+	}
+
+	for _, tt := range tests {
+		findings, err := checkAPIs(manifests, tt.kubeVersion)
+		if err != nil {
+			t.Fatalf("%s: %s", tt.kubeVersion, err)
+		}
+		got := ""
+		if len(findings) > 0 {
+			got = findings[0].Status
+		}
+		if got != tt.want {
+			t.Errorf("%s: expected status %q, got %q", tt.kubeVersion, tt.want, got)
+		}
+	}
+}
+
+func TestDeprecatedAPIsTable(t *testing.T) {
+	seen := map[string]bool{}
+	for _, api := range deprecatedAPIs {
+		key := api.apiVersion + " " + api.kind
+		if seen[key] {
+			t.Errorf("expected one entry for %s, got several", key)
+		}
+		seen[key] = true
+		deprecated, err := semver.NewVersion(api.deprecatedIn)
+		if err != nil {
+			t.Fatalf("%s: %s", key, err)
+		}
+		removed, err := semver.NewVersion(api.removedIn)
+		if err != nil {
+			t.Fatalf("%s: %s", key, err)
+		}
+		if !deprecated.LessThan(removed) {
+			t.Errorf("%s: expected deprecation (%s) before removal (%s)", key, api.deprecatedIn, api.removedIn)
+		}
+		if api.replacement == "" {
+			t.Errorf("%s: expected a replacement", key)
+		}
+	}
+}
+
+func TestCheckAPIsDeprecationTable(t *testing.T) {
+	manifest := func(apiVersion, kind string) string {
+		return "apiVersion: " + apiVersion + "\nkind: " + kind + "\nmetadata:\n  name: pulsar\n"
+	}
+
+	tests := []struct {
+		apiVersion  string
+		kind        string
+		kubeVersion string
+		want        string
+	}{
+		{"apps/v1", "StatefulSet", "v1.33.0", ""},
+		{"extensions/v1beta1", "Deployment", "v1.15.12", apiDeprecated},
+		{"extensions/v1beta1", "Deployment", "v1.16.0", apiRemoved},
+		{"networking.k8s.io/v1beta1", "Ingress", "v1.21.14", apiDeprecated},
+		{"networking.k8s.io/v1beta1", "Ingress", "v1.22.0", apiRemoved},
+		{"batch/v1beta1", "CronJob", "v1.20.15", ""},
+		{"batch/v1beta1", "CronJob", "v1.24.0", apiDeprecated},
+		{"batch/v1beta1", "CronJob", "v1.25.3-eks-4096722", apiRemoved},
+		{"autoscaling/v2beta2", "HorizontalPodAutoscaler", "v1.25.0", apiDeprecated},
+		{"autoscaling/v2beta2", "HorizontalPodAutoscaler", "v1.26.0", apiRemoved},
+		{"flowcontrol.apiserver.k8s.io/v1beta3", "FlowSchema", "v1.31.4", apiDeprecated},
+		{"flowcontrol.apiserver.k8s.io/v1beta3", "FlowSchema", "v1.32.0", apiRemoved},
+		// The API version is only deprecated for the listed kinds.
+		{"policy/v1beta1", "Eviction", "v1.30.0", ""},
+	}
+
+	for _, tt := range tests {
+		findings, err := checkAPIs([]string{manifest(tt.apiVersion, tt.kind)}, tt.kubeVersion)
+		if err != nil {
+			t.Fatalf("%s %s: %s", tt.apiVersion, tt.kind, err)
+		}
+		got := ""
+		if len(findings) > 0 {
+			got = findings[0].Status
+		}
+		if got != tt.want {
+			t.Errorf("%s %s in %s: expected status %q, got %q", tt.apiVersion, tt.kind, tt.kubeVersion, tt.want, got)
+		}
+		if err := removedAPIsError(findings, tt.kubeVersion); (err != nil) != (tt.want == apiRemoved) {
+			t.Errorf("%s %s in %s: expected error %t, got '%v'", tt.apiVersion, tt.kind, tt.kubeVersion, tt.want == apiRemoved, err)
+		}
+	}
+}
@@ -71,7 +71,7 @@ This will produce an error if the chart cannot be loaded.
 
 func newDependencyCmd(settings *cli.EnvSettings, cfg *action.Configuration, out io.Writer) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:     "dependency update|build|list",
+		Use:     "dependency update|build|list|verify",
 		Aliases: []string{"dep", "dependencies"},
 		Short:   "manage a chart's dependencies",
 		Long:    dependencyDesc,
@@ -81,6 +81,7 @@ func newDependencyCmd(settings *cli.EnvSettings, cfg *action.Configuration, out
 	cmd.AddCommand(newDependencyListCmd(out))
 	cmd.AddCommand(newDependencyUpdateCmd(settings, cfg, out))
 	cmd.AddCommand(newDependencyBuildCmd(settings, out))
+	cmd.AddCommand(newDependencyVerifyCmd(settings, out))
 
 	return cmd
 }
@@ -0,0 +1,653 @@
+package cmd
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"helm.sh/helm/v4/pkg/cli"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/gosuri/uitable"
+	"github.com/huangxiaofeng10047/go-cli-example/cmd/i18n"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+
+	"helm.sh/helm/v4/cmd/helm/require"
+	chart "helm.sh/helm/v4/pkg/chart/v2"
+	"helm.sh/helm/v4/pkg/chart/v2/loader"
+	chartutil "helm.sh/helm/v4/pkg/chart/v2/util"
+	"helm.sh/helm/v4/pkg/cli/output"
+	"helm.sh/helm/v4/pkg/downloader"
+	"helm.sh/helm/v4/pkg/getter"
+	"helm.sh/helm/v4/pkg/helmpath"
+	"helm.sh/helm/v4/pkg/provenance"
+	"helm.sh/helm/v4/pkg/registry"
+	"helm.sh/helm/v4/pkg/repo"
+)
+
+// Dependency states reported by 'dependency verify'.
+const (
+	depOK             = "ok"
+	depMissing        = "missing"
+	depExtra          = "extra"
+	depVersionDrift   = "version-drift"
+	depDigestMismatch = "digest-mismatch"
+	depContentDrift   = "content-mismatch"
+	depUnverified     = "unverified"
+	depUnlocked       = "not-locked"
+	depConstraint     = "constraint-mismatch"
+)
+
+// dependencyStatus is the verification result of one dependency.
+type dependencyStatus struct {
+	Name       string   `json:"name"`
+	Repository string   `json:"repository,omitempty"`
+	Constraint string   `json:"constraint,omitempty"`
+	Locked     string   `json:"locked,omitempty"`
+	Vendored   string   `json:"vendored,omitempty"`
+	Path       string   `json:"path,omitempty"`
+	Status     string   `json:"status"`
+	Message    string   `json:"message,omitempty"`
+	Newer      []string `json:"newer,omitempty"`
+}
+
+// drifted reports whether the dependency differs from Chart.lock. An
+// unverified dependency counts unless allowUnverified is set.
+func (s dependencyStatus) drifted(allowUnverified bool) bool {
+	return s.Status != depOK && (s.Status != depUnverified || !allowUnverified)
+}
+
+// dependencyReport is the result of 'dependency verify' for one chart.
+type dependencyReport struct {
+	Chart        string             `json:"chart"`
+	LockInSync   bool               `json:"lockInSync"`
+	Dependencies []dependencyStatus `json:"dependencies"`
+}
+
+// vendoredChart is a chart archive or directory in charts/.
+type vendoredChart struct {
+	path    string
+	dir     bool
+	name    string
+	version string
+	digest  string
+}
+
+func newDependencyVerifyCmd(settings *cli.EnvSettings, out io.Writer) *cobra.Command {
+	var outfmt output.Format
+	var allowUnverified bool
+
+	cmd := &cobra.Command{
+		Use:   "verify [CHART]",
+		Short: i18n.T("depVerify.short"),
+		Long:  i18n.T("depVerify.long"),
+		Args:  require.MaximumNArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			chartpath := "."
+			if len(args) > 0 {
+				chartpath = filepath.Clean(args[0])
+			}
+			report, err := verifyDependencies(chartpath, settings, allowUnverified)
+			if err != nil {
+				return err
+			}
+			if err := outfmt.Write(out, report); err != nil {
+				return err
+			}
+
+			drift := 0
+			for _, d := range report.Dependencies {
+				if d.drifted(allowUnverified) {
+					drift++
+				}
+			}
+			if !report.LockInSync {
+				return errors.New(i18n.T("depVerify.lockOutOfSync", chartpath))
+			}
+			if drift > 0 {
+				return errors.New(i18n.T("depVerify.drifted", drift, chartpath))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&allowUnverified, "allow-unverified", false, i18n.T("depVerify.flagAllowUnverified"))
+	bindOutputFlag(cmd, &outfmt)
+	return cmd
+}
+
+// verifyDependencies compares the dependencies of the chart directory with
+// its lock file and vendored charts. A vendored directory whose locked archive
+// cannot be fetched is an error, or unverified with allowUnverified.
+func verifyDependencies(chartpath string, settings *cli.EnvSettings, allowUnverified bool) (*dependencyReport, error) {
+	md, err := chartutil.LoadChartfile(filepath.Join(chartpath, chartutil.ChartfileName))
+	if err != nil {
+		return nil, errors.Wrap(err, i18n.T("depVerify.notChart", chartpath))
+	}
+	lock, err := loadChartLock(chartpath)
+	if err != nil {
+		return nil, err
+	}
+	vendored, err := loadVendoredCharts(filepath.Join(chartpath, "charts"))
+	if err != nil {
+		return nil, err
+	}
+	indexes := newCachedIndexes(settings)
+
+	report := &dependencyReport{Chart: md.Name, LockInSync: true, Dependencies: []dependencyStatus{}}
+	if lock == nil {
+		if len(md.Dependencies) > 0 {
+			report.LockInSync = false
+		}
+		lock = &chart.Lock{}
+	} else if digest, err := hashRequirements(md.Dependencies, lock.Dependencies); err != nil {
+		return nil, err
+	} else if digest != lock.Digest {
+		report.LockInSync = false
+	}
+
+	used := map[*vendoredChart]bool{}
+	for _, req := range md.Dependencies {
+		s := dependencyStatus{
+			Name:       req.Name,
+			Repository: req.Repository,
+			Constraint: req.Version,
+			Status:     depOK,
+		}
+		locked := lockedDependency(lock, req)
+		if locked == nil {
+			s.Status, s.Message = depUnlocked, i18n.T("depVerify.notLocked")
+			report.Dependencies = append(report.Dependencies, s)
+			continue
+		}
+		s.Locked = locked.Version
+		if ok, err := versionSatisfies(locked.Version, req.Version); err != nil {
+			return nil, errors.Wrap(err, i18n.T("depVerify.dependency", req.Name))
+		} else if !ok {
+			s.Status = depConstraint
+			s.Message = i18n.T("depVerify.constraint", locked.Version, req.Version)
+		}
+
+		v := findVendored(vendored, locked.Name, locked.Version)
+		if v != nil {
+			used[v] = true
+			s.Vendored, s.Path = v.version, v.path
+		}
+		switch {
+		case s.Status != depOK:
+		case v == nil:
+			s.Status, s.Message = depMissing, i18n.T("depVerify.missing", locked.Name, locked.Version)
+		case v.version != locked.Version:
+			s.Status = depVersionDrift
+			s.Message = i18n.T("depVerify.versionDrift", v.version, locked.Version)
+		case !v.dir:
+			if published, ok := indexes.digest(locked.Repository, locked.Name, locked.Version); ok && published != v.digest {
+				s.Status = depDigestMismatch
+				s.Message = i18n.T("depVerify.digestMismatch", v.digest, published)
+			}
+		default:
+			s.Status, s.Message, err = verifyVendoredDir(filepath.Join(chartpath, v.path), locked, indexes)
+			var fetchErr *archiveFetchError
+			if errors.As(err, &fetchErr) && allowUnverified {
+				s.Status, s.Message, err = depUnverified, i18n.T("depVerify.noArchive", fetchErr.archive, fetchErr.err), nil
+			}
+			if err != nil {
+				return nil, errors.Wrap(err, i18n.T("depVerify.dependency", req.Name))
+			}
+		}
+
+		newer, err := indexes.newerVersions(req.Repository, req.Name, req.Version, locked.Version)
+		if err != nil {
+			return nil, errors.Wrap(err, i18n.T("depVerify.dependency", req.Name))
+		}
+		s.Newer = newer
+		report.Dependencies = append(report.Dependencies, s)
+	}
+
+	for _, v := range vendored {
+		if used[v] {
+			continue
+		}
+		report.Dependencies = append(report.Dependencies, dependencyStatus{
+			Name:     v.name,
+			Vendored: v.version,
+			Path:     v.path,
+			Status:   depExtra,
+			Message:  i18n.T("depVerify.notLocked"),
+		})
+	}
+	return report, nil
+}
+
+// loadChartLock reads Chart.lock. A missing lock file is not an error.
+func loadChartLock(chartpath string) (*chart.Lock, error) {
+	b, err := os.ReadFile(filepath.Join(chartpath, "Chart.lock"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	lock := &chart.Lock{}
+	if err := yaml.Unmarshal(b, lock); err != nil {
+		return nil, errors.Wrap(err, i18n.T("depVerify.parseLock"))
+	}
+	return lock, nil
+}
+
+// hashRequirements computes the lock file digest the way 'helm dependency
+// update' does.
+func hashRequirements(req, lock []*chart.Dependency) (string, error) {
+	data, err := json.Marshal([2][]*chart.Dependency{req, lock})
+	if err != nil {
+		return "", err
+	}
+	s, err := provenance.Digest(bytes.NewBuffer(data))
+	return "sha256:" + s, err
+}
+
+// loadVendoredCharts reads the metadata of the archives and directories in
+// dir. Other files are ignored.
+func loadVendoredCharts(dir string) ([]*vendoredChart, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var charts []*vendoredChart
+	for _, e := range entries {
+		p := filepath.Join(dir, e.Name())
+		v := &vendoredChart{path: filepath.Join("charts", e.Name()), dir: e.IsDir()}
+		switch {
+		case e.IsDir():
+			md, err := chartutil.LoadChartfile(filepath.Join(p, chartutil.ChartfileName))
+			if os.IsNotExist(errors.Cause(err)) {
+				continue
+			}
+			if err != nil {
+				return nil, errors.Wrap(err, i18n.T("depVerify.load", v.path))
+			}
+			v.name, v.version = md.Name, md.Version
+		case strings.HasSuffix(e.Name(), ".tgz"):
+			ch, err := loader.LoadFile(p)
+			if err != nil {
+				return nil, errors.Wrap(err, i18n.T("depVerify.load", v.path))
+			}
+			v.name, v.version = ch.Name(), ch.Metadata.Version
+			if v.digest, err = fileDigest(p); err != nil {
+				return nil, err
+			}
+		default:
+			continue
+		}
+		charts = append(charts, v)
+	}
+	return charts, nil
+}
+
+func fileDigest(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// archiveFetchError is returned when the locked archive of a dependency can
+// be neither found in the repository cache nor downloaded.
+type archiveFetchError struct {
+	archive string
+	err     error
+}
+
+func (e *archiveFetchError) Error() string {
+	return fmt.Sprintf("%s: %s", i18n.T("depVerify.fetch", e.archive), e.err)
+}
+
+func (e *archiveFetchError) Unwrap() error { return e.err }
+
+// verifyVendoredDir compares the files of a vendored chart directory with the
+// locked archive and returns the status and message of the dependency. An
+// archive that does not match the digest of the repository index is reported
+// as a digest mismatch.
+func verifyVendoredDir(dir string, locked *chart.Dependency, indexes *cachedIndexes) (string, string, error) {
+	archive, cleanup, err := indexes.fetchArchive(locked)
+	if err != nil {
+		return "", "", err
+	}
+	defer cleanup()
+	name := fmt.Sprintf("%s-%s.tgz", locked.Name, locked.Version)
+	digest, err := fileDigest(archive)
+	if err != nil {
+		return "", "", err
+	}
+	if published, ok := indexes.digest(locked.Repository, locked.Name, locked.Version); ok && published != digest {
+		return depDigestMismatch, i18n.T("depVerify.archiveMismatch", name, digest, published), nil
+	}
+
+	f, err := os.Open(archive)
+	if err != nil {
+		return "", "", err
+	}
+	defer f.Close()
+	files, err := loader.LoadArchiveFiles(f)
+	if err != nil {
+		return "", "", errors.Wrap(err, i18n.T("depVerify.readArchive", archive))
+	}
+	want := make(map[string]string, len(files))
+	for _, file := range files {
+		want[file.Name] = dataDigest(file.Data)
+	}
+	got, err := dirDigests(dir)
+	if err != nil {
+		return "", "", err
+	}
+
+	var changed, added, removed []string
+	for name, d := range got {
+		switch w, ok := want[name]; {
+		case !ok:
+			added = append(added, name)
+		case w != d:
+			changed = append(changed, name)
+		}
+	}
+	for name := range want {
+		if _, ok := got[name]; !ok {
+			removed = append(removed, name)
+		}
+	}
+	if len(changed)+len(added)+len(removed) == 0 {
+		return depOK, "", nil
+	}
+	var msg []string
+	for _, diff := range []struct {
+		kind  string
+		files []string
+	}{{i18n.T("depVerify.changed"), changed}, {i18n.T("depVerify.added"), added}, {i18n.T("depVerify.removed"), removed}} {
+		if len(diff.files) > 0 {
+			sort.Strings(diff.files)
+			msg = append(msg, fmt.Sprintf("%s: %s", diff.kind, summarizeFiles(diff.files)))
+		}
+	}
+	return depContentDrift, i18n.T("depVerify.contentDrift", name, strings.Join(msg, "; ")), nil
+}
+
+// dirDigests returns the digests of the regular files under dir, keyed by
+// their slash-separated path relative to dir. A leading UTF-8 BOM is ignored,
+// as it is when an archive is loaded.
+func dirDigests(dir string) (map[string]string, error) {
+	digests := map[string]string{}
+	err := filepath.WalkDir(dir, func(p string, d os.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+		b, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		digests[filepath.ToSlash(rel)] = dataDigest(bytes.TrimPrefix(b, []byte("\xEF\xBB\xBF")))
+		return nil
+	})
+	return digests, err
+}
+
+func dataDigest(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// summarizeFiles lists the first few files and counts the rest.
+func summarizeFiles(files []string) string {
+	const shown = 3
+	if len(files) <= shown {
+		return strings.Join(files, ", ")
+	}
+	return i18n.T("depVerify.andMore", strings.Join(files[:shown], ", "), len(files)-shown)
+}
+
+// lockedDependency returns the lock entry of a Chart.yaml dependency.
+func lockedDependency(lock *chart.Lock, req *chart.Dependency) *chart.Dependency {
+	for _, d := range lock.Dependencies {
+		if d.Name == req.Name && d.Repository == req.Repository {
+			return d
+		}
+	}
+	return nil
+}
+
+// findVendored returns the vendored chart with the given name, preferring
+// the one with the given version.
+func findVendored(vendored []*vendoredChart, name, version string) *vendoredChart {
+	var found *vendoredChart
+	for _, v := range vendored {
+		if v.name != name {
+			continue
+		}
+		if v.version == version {
+			return v
+		}
+		if found == nil {
+			found = v
+		}
+	}
+	return found
+}
+
+func versionSatisfies(version, constraint string) (bool, error) {
+	if constraint == "" {
+		return true, nil
+	}
+	c, err := semver.NewConstraint(constraint)
+	if err != nil {
+		return false, errors.Wrap(err, i18n.T("depVerify.badConstraint", constraint))
+	}
+	v, err := semver.NewVersion(version)
+	if err != nil {
+		return false, errors.Wrap(err, i18n.T("depVerify.badVersion", version))
+	}
+	return c.Check(v), nil
+}
+
+// cachedIndexes looks up dependency repositories in the local repository
+// cache. Repositories that are not configured or not cached are skipped.
+type cachedIndexes struct {
+	settings *cli.EnvSettings
+	repos    []*repo.Entry
+	loaded   map[string]*repo.IndexFile
+}
+
+func newCachedIndexes(settings *cli.EnvSettings) *cachedIndexes {
+	c := &cachedIndexes{settings: settings, loaded: map[string]*repo.IndexFile{}}
+	if f, err := repo.LoadFile(settings.RepositoryConfig); err == nil {
+		c.repos = f.Repositories
+	}
+	return c
+}
+
+// index returns the cached index of a dependency repository, which is a URL
+// or an '@name' or 'alias:name' reference to a configured repository.
+func (c *cachedIndexes) index(repository string) *repo.IndexFile {
+	var name string
+	switch {
+	case strings.HasPrefix(repository, "@"):
+		name = strings.TrimPrefix(repository, "@")
+	case strings.HasPrefix(repository, "alias:"):
+		name = strings.TrimPrefix(repository, "alias:")
+	default:
+		for _, r := range c.repos {
+			if strings.TrimSuffix(r.URL, "/") == strings.TrimSuffix(repository, "/") {
+				name = r.Name
+				break
+			}
+		}
+	}
+	if name == "" {
+		return nil
+	}
+	if idx, ok := c.loaded[name]; ok {
+		return idx
+	}
+	idx, err := repo.LoadIndexFile(filepath.Join(c.settings.RepositoryCache, helmpath.CacheIndexFile(name)))
+	if err != nil {
+		idx = nil
+	}
+	c.loaded[name] = idx
+	return idx
+}
+
+// fetchArchive returns the path of the locked archive of a dependency. The
+// archive Helm keeps in the repository cache is used when there is one,
+// otherwise it is downloaded into a temporary directory that cleanup removes.
+func (c *cachedIndexes) fetchArchive(locked *chart.Dependency) (string, func(), error) {
+	name := fmt.Sprintf("%s-%s.tgz", locked.Name, locked.Version)
+	cached := filepath.Join(c.settings.RepositoryCache, name)
+	if _, err := os.Stat(cached); err == nil {
+		return cached, func() {}, nil
+	}
+
+	fail := func(err error) (string, func(), error) {
+		return "", nil, &archiveFetchError{archive: name, err: err}
+	}
+	ref, err := c.chartRef(locked)
+	if err != nil {
+		return fail(err)
+	}
+	registryClient, err := newDefaultRegistryClient(c.settings, false, "", "")
+	if err != nil {
+		return fail(err)
+	}
+	dir, err := os.MkdirTemp("", "helm-verify-")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup := func() { os.RemoveAll(dir) }
+	dl := downloader.ChartDownloader{
+		Out:              io.Discard,
+		Verify:           downloader.VerifyNever,
+		Getters:          getter.All(c.settings),
+		RegistryClient:   registryClient,
+		RepositoryConfig: c.settings.RepositoryConfig,
+		RepositoryCache:  c.settings.RepositoryCache,
+	}
+	path, _, err := dl.DownloadTo(ref, locked.Version, dir)
+	if err != nil {
+		cleanup()
+		return fail(err)
+	}
+	return path, cleanup, nil
+}
+
+// chartRef returns the reference the chart downloader resolves a locked
+// dependency with: a configured repository and chart name, an OCI reference,
+// or the archive URL found in the index of an unconfigured repository.
+func (c *cachedIndexes) chartRef(locked *chart.Dependency) (string, error) {
+	switch {
+	case registry.IsOCI(locked.Repository):
+		return strings.TrimSuffix(locked.Repository, "/") + "/" + locked.Name, nil
+	case strings.HasPrefix(locked.Repository, "@"):
+		return strings.TrimPrefix(locked.Repository, "@") + "/" + locked.Name, nil
+	case strings.HasPrefix(locked.Repository, "alias:"):
+		return strings.TrimPrefix(locked.Repository, "alias:") + "/" + locked.Name, nil
+	}
+	for _, r := range c.repos {
+		if strings.TrimSuffix(r.URL, "/") == strings.TrimSuffix(locked.Repository, "/") {
+			return r.Name + "/" + locked.Name, nil
+		}
+	}
+	return repo.FindChartInRepoURL(locked.Repository, locked.Name, getter.All(c.settings), repo.WithChartVersion(locked.Version))
+}
+
+// digest returns the archive digest the repository publishes for a version.
+func (c *cachedIndexes) digest(repository, name, version string) (string, bool) {
+	idx := c.index(repository)
+	if idx == nil {
+		return "", false
+	}
+	cv, err := idx.Get(name, version)
+	if err != nil || cv.Digest == "" {
+		return "", false
+	}
+	return strings.TrimPrefix(cv.Digest, "sha256:"), true
+}
+
+// newerVersions lists the versions of the cached index that are newer than
+// locked and satisfy the constraint, newest first.
+func (c *cachedIndexes) newerVersions(repository, name, constraint, locked string) ([]string, error) {
+	idx := c.index(repository)
+	if idx == nil {
+		return nil, nil
+	}
+	current, err := semver.NewVersion(locked)
+	if err != nil {
+		return nil, errors.Wrap(err, i18n.T("depVerify.badLockedVersion", locked))
+	}
+	var newer []*semver.Version
+	for _, cv := range idx.Entries[name] {
+		v, err := semver.NewVersion(cv.Version)
+		if err != nil || !v.GreaterThan(current) {
+			continue
+		}
+		if ok, err := versionSatisfies(cv.Version, constraint); err != nil {
+			return nil, err
+		} else if ok {
+			newer = append(newer, v)
+		}
+	}
+	sort.Sort(sort.Reverse(semver.Collection(newer)))
+
+	res := make([]string, 0, len(newer))
+	for _, v := range newer {
+		res = append(res, v.Original())
+	}
+	return res, nil
+}
+
+func (r *dependencyReport) WriteTable(out io.Writer) error {
+	if !r.LockInSync {
+		fmt.Fprintln(out, i18n.T("depVerify.lockWarning"))
+	}
+	table := uitable.New()
+	table.MaxColWidth = 80
+	table.AddRow("NAME", "CONSTRAINT", "LOCKED", "VENDORED", "STATUS", "NEWER", "MESSAGE")
+	for _, d := range r.Dependencies {
+		vendored := d.Vendored
+		if d.Path != "" {
+			kind := i18n.T("depVerify.archive")
+			if !strings.HasSuffix(d.Path, ".tgz") {
+				kind = i18n.T("depVerify.directory")
+			}
+			vendored = fmt.Sprintf("%s (%s)", vendored, kind)
+		}
+		table.AddRow(d.Name, orDash(d.Constraint), orDash(d.Locked), orDash(vendored), d.Status, orDash(strings.Join(d.Newer, ", ")), d.Message)
+	}
+	return output.EncodeTable(out, table)
+}
+
+func (r *dependencyReport) WriteJSON(out io.Writer) error {
+	return output.EncodeJSON(out, r)
+}
+
+func (r *dependencyReport) WriteYAML(out io.Writer) error {
+	return output.EncodeYAML(out, r)
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"os"
+	"os/user"
+	"regexp"
+	"strings"
+	"time"
+
+	"helm.sh/helm/v4/pkg/action"
+	"helm.sh/helm/v4/pkg/release"
+)
+
+// Release labels used to record how a revision was deployed. They are stored
+// next to the release record by the storage driver, so 'history' can show
+// them without any extra bookkeeping.
+const (
+	deployedByLabel     = "deployedBy"
+	deployDurationLabel = "deployDuration"
+	testResultLabel     = "testResult"
+)
+
+// testResultError is recorded when the external test run could not complete.
+// Passed and failed results come from installevent.
+const testResultError = "error"
+
+// deployerEnvVars are checked in order to find out who or what is deploying.
+// HELM_DEPLOYED_BY always wins, then well-known CI job identifiers.
+var deployerEnvVars = []string{
+	"HELM_DEPLOYED_BY",
+	"CI_JOB_NAME",     // GitLab CI
+	"GITHUB_WORKFLOW", // GitHub Actions
+	"JOB_NAME",        // Jenkins
+	"BUILD_DEFINITIONNAME",
+}
+
+var invalidLabelChars = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+
+// deployer returns the label recorded as the author of a deployment.
+func deployer() string {
+	for _, env := range deployerEnvVars {
+		if v := os.Getenv(env); v != "" {
+			return v
+		}
+	}
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return ""
+}
+
+// sanitizeLabelValue turns s into a valid Kubernetes label value so it can be
+// stored by the secret and configmap drivers.
+func sanitizeLabelValue(s string) string {
+	s = invalidLabelChars.ReplaceAllString(s, "_")
+	if len(s) > 63 {
+		s = s[:63]
+	}
+	return strings.Trim(s, "_.-")
+}
+
+// recordDeployInfo stores the deployer and the deploy duration on the given
+// release revision. A test result inherited from the previous revision is
+// dropped, since it does not describe this revision.
+func recordDeployInfo(cfg *action.Configuration, rel *release.Release, started time.Time) error {
+	if rel == nil || cfg.Releases == nil {
+		return nil
+	}
+	if rel.Labels == nil {
+		rel.Labels = map[string]string{}
+	}
+	if d := sanitizeLabelValue(deployer()); d != "" {
+		rel.Labels[deployedByLabel] = d
+	}
+	rel.Labels[deployDurationLabel] = time.Since(started).Round(time.Second).String()
+	delete(rel.Labels, testResultLabel)
+	return cfg.Releases.Update(rel)
+}
+
+// recordTestResult stores the external test result on the given release revision.
+func recordTestResult(cfg *action.Configuration, rel *release.Release, result string) error {
+	if rel == nil || cfg.Releases == nil {
+		return nil
+	}
+	if rel.Labels == nil {
+		rel.Labels = map[string]string{}
+	}
+	rel.Labels[testResultLabel] = result
+	return cfg.Releases.Update(rel)
+}
+
+// isDryRunOption reports whether the given --dry-run value skips storing the release.
+func isDryRunOption(opt string) bool {
+	return opt == "client" || opt == "server" || opt == "true"
+}
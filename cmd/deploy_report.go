@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"io"
+
+	installevent "github.com/huangxiaofeng10047/go-cli-example/cmd/event"
+	"helm.sh/helm/v4/pkg/cli/output"
+	"helm.sh/helm/v4/pkg/release"
+)
+
+// deployReport is the output of install, upgrade and scale. A table is
+// written part by part while the command runs: the status of the release
+// once it is deployed, then the results of the post-deploy checks and of the
+// test cases. JSON and YAML are written by flush as a single document, the
+// release with the results next to it, so that stdout can be parsed as a
+// whole.
+type deployReport struct {
+	out    io.Writer
+	outfmt output.Format
+	status *statusPrinter
+	checks []pluginResult
+	tests  *installevent.TestReport
+}
+
+// deployDocument is the JSON and YAML document of a deployReport. The fields
+// of the release are inlined to keep the format of 'status'.
+type deployDocument struct {
+	*release.Release
+	Checks []pluginResult           `json:"checks,omitempty"`
+	Tests  *installevent.TestReport `json:"tests,omitempty"`
+}
+
+func newDeployReport(out io.Writer, outfmt output.Format) *deployReport {
+	return &deployReport{out: out, outfmt: outfmt}
+}
+
+// writeStatus adds the status of the deployed release.
+func (r *deployReport) writeStatus(s *statusPrinter) error {
+	r.status = s
+	if r.outfmt != output.Table {
+		return nil
+	}
+	return s.WriteTable(r.out)
+}
+
+// writeChecks adds the results of the post-deploy checks.
+func (r *deployReport) writeChecks(results []pluginResult) error {
+	r.checks = results
+	if r.outfmt != output.Table || len(results) == 0 {
+		return nil
+	}
+	return writeCheckResults(r.out, results)
+}
+
+// writeTests adds the results of the test cases.
+func (r *deployReport) writeTests(tests *installevent.TestReport) error {
+	r.tests = tests
+	if r.outfmt != output.Table {
+		return nil
+	}
+	return tests.WriteTable(r.out)
+}
+
+// flush writes the JSON or YAML document. Nothing is written before the
+// release is deployed, nor for tables, which are already written.
+func (r *deployReport) flush() error {
+	if r.outfmt == output.Table || r.status == nil {
+		return nil
+	}
+	return r.outfmt.Write(r.out, r)
+}
+
+func (r *deployReport) document() *deployDocument {
+	return &deployDocument{Release: r.status.release, Checks: r.checks, Tests: r.tests}
+}
+
+func (r *deployReport) WriteTable(out io.Writer) error {
+	if err := r.status.WriteTable(out); err != nil {
+		return err
+	}
+	if len(r.checks) > 0 {
+		if err := writeCheckResults(out, r.checks); err != nil {
+			return err
+		}
+	}
+	if r.tests == nil {
+		return nil
+	}
+	return r.tests.WriteTable(out)
+}
+
+func (r *deployReport) WriteJSON(out io.Writer) error {
+	return output.EncodeJSON(out, r.document())
+}
+
+func (r *deployReport) WriteYAML(out io.Writer) error {
+	return output.EncodeYAML(out, r.document())
+}
@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/huangxiaofeng10047/go-cli-example/cmd/i18n"
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/yaml"
+
+	"helm.sh/helm/v4/pkg/action"
+	"helm.sh/helm/v4/pkg/cli"
+	"helm.sh/helm/v4/pkg/cli/values"
+)
+
+// defaultProfileFile is the profile file looked up when --profile-file is not set.
+const defaultProfileFile = "profiles.yaml"
+
+// activeProfile is the environment profile selected with --profile, if any.
+var activeProfile *envProfile
+
+// profileFile is the on-disk format of the environment profile file:
+//
+//	profiles:
+//	  pulsar-test:
+//	    namespace: pulsar-test
+//	    kubeContext: pulsar-test
+//	    values:
+//	      - ./charts/values.yaml
+//	    set:
+//	      - namespace=pulsar-test
+//	      - cluster.name=pulsar-test
+//	    testService:
+//	      schema: http
+//	      host: 10.7.20.26
+//	      port: 38799
+//	      # or, superseding schema, host and port:
+//	      # endpoint: https://qa.example/api/v2
+type profileFile struct {
+	Profiles map[string]*envProfile `json:"profiles"`
+}
+
+// envProfile describes how to deploy to one environment.
+type envProfile struct {
+	name string
+
+	Namespace   string              `json:"namespace,omitempty"`
+	KubeContext string              `json:"kubeContext,omitempty"`
+	Values      []string            `json:"values,omitempty"`
+	Set         []string            `json:"set,omitempty"`
+	SetString   []string            `json:"setString,omitempty"`
+	TestService *profileTestService `json:"testService,omitempty"`
+}
+
+// profileTestService overrides the --test-case-* flags and --test-endpoint.
+type profileTestService struct {
+	Schema   string `json:"schema,omitempty"`
+	Host     string `json:"host,omitempty"`
+	Port     int    `json:"port,omitempty"`
+	Endpoint string `json:"endpoint,omitempty"`
+}
+
+// loadProfile reads the named profile from path. Relative value file paths
+// are resolved against the directory of the profile file.
+func loadProfile(path, name string) (*envProfile, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, i18n.T("error.readProfile", path))
+	}
+	pf := &profileFile{}
+	if err := yaml.Unmarshal(b, pf); err != nil {
+		return nil, errors.Wrap(err, i18n.T("error.parseProfile", path))
+	}
+	p, ok := pf.Profiles[name]
+	if !ok || p == nil {
+		known := make([]string, 0, len(pf.Profiles))
+		for k := range pf.Profiles {
+			known = append(known, k)
+		}
+		sort.Strings(known)
+		return nil, errors.New(i18n.T("error.noProfile", name, path, strings.Join(known, ", ")))
+	}
+	p.name = name
+
+	base := filepath.Dir(path)
+	for i, f := range p.Values {
+		if f == "-" || strings.Contains(f, "://") || filepath.IsAbs(f) {
+			continue
+		}
+		p.Values[i] = filepath.Join(base, f)
+	}
+	return p, nil
+}
+
+// applySettings applies the namespace, kube context and test service of the
+// profile. Anything set explicitly on the command line wins.
+func (p *envProfile) applySettings(settings *cli.EnvSettings, flags *pflag.FlagSet) {
+	if p.Namespace != "" && !flags.Changed("namespace") {
+		settings.SetNamespace(p.Namespace)
+	}
+	if p.KubeContext != "" && !flags.Changed("kube-context") {
+		settings.KubeContext = p.KubeContext
+	}
+	if ts := p.TestService; ts != nil {
+		if ts.Schema != "" && !flags.Changed("test-case-schema") {
+			testConfig.Schema = ts.Schema
+		}
+		if ts.Host != "" && !flags.Changed("test-case-host") {
+			testConfig.Host = ts.Host
+		}
+		if ts.Port != 0 && !flags.Changed("test-case-port") {
+			testConfig.Port = ts.Port
+		}
+		// An address given on the command line in any form wins over the
+		// endpoint of the profile.
+		if ts.Endpoint != "" && !flags.Changed("test-endpoint") && !flags.Changed("test-case-schema") &&
+			!flags.Changed("test-case-host") && !flags.Changed("test-case-port") {
+			testConfig.Endpoint = ts.Endpoint
+		}
+	}
+}
+
+// applyProfileValues layers the values of the active profile underneath the
+// ones given on the command line, so '-f' and '--set' still take precedence.
+func applyProfileValues(valueOpts *values.Options, debug action.DebugLog) {
+	if activeProfile == nil {
+		return
+	}
+	p := activeProfile
+	valueOpts.ValueFiles = append(append([]string{}, p.Values...), valueOpts.ValueFiles...)
+	valueOpts.Values = append(append([]string{}, p.Set...), valueOpts.Values...)
+	valueOpts.StringValues = append(append([]string{}, p.SetString...), valueOpts.StringValues...)
+
+	debug("Profile %q resolved values stack:", p.name)
+	for i, f := range valueOpts.ValueFiles {
+		debug("  values[%d]: %s", i, f)
+	}
+	for i, s := range valueOpts.Values {
+		debug("  set[%d]: %s", i, s)
+	}
+	for i, s := range valueOpts.StringValues {
+		debug("  set-string[%d]: %s", i, s)
+	}
+	debug("  namespace: %s, kube-context: %s, test service: %s",
+		orDash(p.Namespace), orDash(p.KubeContext), testConfig.BaseURL())
+}
@@ -10,7 +10,7 @@ import (
 
 type event interface {
 	FinishInstall(settings *cli.EnvSettings, cfg *action.Configuration, name string) error
-	WaitTestCaseFinish(settings *cli.EnvSettings, ctx context.Context, out io.Writer) error
+	WaitTestCaseFinish(settings *cli.EnvSettings, ctx context.Context, out io.Writer, taskId string) (string, error)
 	CheckUninstall(settings *cli.EnvSettings, name string, cfg *action.Configuration, out io.Writer) error
 	QueryRunningPod(settings *cli.EnvSettings, ctx context.Context, cfg *action.Configuration, out io.Writer)
 }
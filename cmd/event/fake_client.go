@@ -0,0 +1,51 @@
+package installevent
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// FakeTestClient 实现TestClient接口，返回预设的响应，用于场景测试
+type FakeTestClient struct {
+	// TriggerResponse 是 Trigger 返回的响应体
+	TriggerResponse []byte
+	// StatusResponses 按顺序作为 Trigger2 的返回值，最后一个会重复返回
+	StatusResponses []string
+	// Err 不为空时所有调用都返回该错误
+	Err error
+
+	mu sync.Mutex
+	// Calls 记录所有调用，便于与期望输出比较
+	Calls []string
+	next  int
+}
+
+// Trigger 实现TestClient接口的Trigger方法
+func (c *FakeTestClient) Trigger(_ context.Context, ip string, token string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.Calls = append(c.Calls, fmt.Sprintf("Trigger %s %s", ip, token))
+	if c.Err != nil {
+		return []byte(""), c.Err
+	}
+	return c.TriggerResponse, nil
+}
+
+// Trigger2 实现TestClient接口的Trigger2方法
+func (c *FakeTestClient) Trigger2(_ context.Context, taskId string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.Calls = append(c.Calls, fmt.Sprintf("Trigger2 %s", taskId))
+	if c.Err != nil {
+		return "", c.Err
+	}
+	if len(c.StatusResponses) == 0 {
+		return "", fmt.Errorf("no status response for task %s", taskId)
+	}
+	resp := c.StatusResponses[c.next]
+	if c.next < len(c.StatusResponses)-1 {
+		c.next++
+	}
+	return resp, nil
+}
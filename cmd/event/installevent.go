@@ -3,24 +3,60 @@ package installevent
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"github.com/cheggaaa/pb/v3"
 	"helm.sh/helm/v4/pkg/action"
 	"helm.sh/helm/v4/pkg/cli"
+	"log/slog"
+	"sort"
 	"strings"
-	"sync"
 	"time"
 
 	//"helm.sh/helm/v4/pkg/cli"
 	"io"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/huangxiaofeng10047/go-cli-example/cmd/i18n"
+	"github.com/huangxiaofeng10047/go-cli-example/cmd/progress"
 )
 
 type TestConfig struct {
 	Schema string
 	Host   string
 	Port   int
+	// Endpoint 是测试服务的地址，可以带路径前缀，例如 https://qa.example/api/v2，设置后替代 Schema、Host 和 Port
+	Endpoint string
+	// APIVersion 是测试服务的接口版本 v1 或 v2，为空或 auto 时与测试服务协商
+	APIVersion string
+	// TriggerRoute 和 StatusRoute 替换接口版本的默认路由，StatusRoute 中的 {taskId} 替换为任务 ID
+	TriggerRoute string
+	StatusRoute  string
+	// Suites 是要运行的测试套件，需要 v2 接口
+	Suites []string
+
+	// CAFile、CertFile 和 KeyFile 用于校验 https 测试服务的证书，以及 mTLS 的客户端证书
+	CAFile                string
+	CertFile              string
+	KeyFile               string
+	InsecureSkipTLSVerify bool
+
+	// TokenFile 保存 bearer token，未设置时读取 $HELM_TEST_CASE_TOKEN
+	TokenFile string
+	// Username 和 PasswordFile 用于 basic 认证，未设置 PasswordFile 时读取 $HELM_TEST_CASE_PASSWORD
+	Username     string
+	PasswordFile string
+	// SigningKeyFile 保存请求签名的 HMAC 密钥，未设置时读取 $HELM_TEST_CASE_SIGNING_KEY
+	SigningKeyFile string
+
+	// Client 替换 HTTP 测试客户端，例如场景测试中的 FakeTestClient
+	Client TestClient
+	// ClientSet 替换 action.Configuration 创建的 k8s 客户端
+	ClientSet kubernetes.Interface
+	// PollInterval 覆盖等待 Pod 和测试任务时的轮询间隔
+	PollInterval time.Duration
 }
 
 // 定义响应结构体
@@ -45,47 +81,90 @@ type TestDataItem struct {
 	ErrorInfo ErrorInfo `json:"-"` // 用于存储解析后的错误信息
 }
 
-// 状态响应结构体
-type TaskStatusResponse struct {
-	Code    int            `json:"code"`
-	Message string         `json:"message"`
-	Data    []TestDataItem `json:"data"`
+// TestReport 是测试任务的结果。Finished 为 false 表示达到最大重试次数时任务仍未结束
+type TestReport struct {
+	TaskID   string           `json:"taskId"`
+	Result   string           `json:"result"`
+	Finished bool             `json:"finished"`
+	Cases    []TestCaseResult `json:"cases"`
 }
 
-func NewInstallEvent(config *TestConfig) *InstallEvent {
-	var client TestClient = NewHTTPTestClient(config.Schema, config.Host, config.Port)
-	showProgressBar()
-	return &InstallEvent{client: client}
+// TestCaseResult 是一个测试用例的结果，Error 为空表示通过
+type TestCaseResult struct {
+	Name      string `json:"name"`
+	Error     string `json:"error,omitempty"`
+	RequestID int64  `json:"requestId,omitempty"`
+	Remote    string `json:"remote,omitempty"`
+	Local     string `json:"local,omitempty"`
 }
 
-var bar *pb.ProgressBar
+// WriteTable 以文本输出测试结果
+func (r *TestReport) WriteTable(out io.Writer) error {
+	for _, c := range r.Cases {
+		fmt.Fprint(out, i18n.T("test.resultName", c.Name))
+		fmt.Fprint(out, i18n.T("test.resultError", c.Error))
+		fmt.Fprint(out, i18n.T("test.resultRequestID", c.RequestID))
+		fmt.Fprint(out, i18n.T("test.resultRemote", c.Remote))
+		fmt.Fprint(out, i18n.T("test.resultLocal", c.Local))
+		fmt.Fprintln(out, "------------------------")
+	}
+	if !r.Finished {
+		fmt.Fprintln(out, i18n.T("test.maxRetries"))
+	} else {
+		fmt.Fprintln(out, i18n.T("test.finished"))
+	}
+	_, err := fmt.Fprintln(out, i18n.T("test.result", r.Result))
+	return err
+}
 
-func showProgressBar() {
-	if bar != nil {
-		// 避免重复创建
-		return
+// Err 在有测试用例失败时返回错误
+func (r *TestReport) Err() error {
+	if r.Result != TestResultFailed {
+		return nil
 	}
-	tmpl := `{{ red "With funcs:" }} {{ bar . "<" "-" (cycle . "↖" "↗" "↘" "↙" ) "." ">"}} {{speed . | rndcolor }} {{percent .}} {{string . "my_green_string" | green}} {{string . "my_blue_string" | blue}}`
-	// 基于 pb 的模板开启一个进度条
-	bar = pb.ProgressBarTemplate(tmpl).Start64(2)
-	// 为 string 元素设置值
-	bar.Set("my_green_string", "green").
-		Set("my_blue_string", "blue")
-	//defer bar.Finish()
+	failed := 0
+	for _, c := range r.Cases {
+		if c.Error != "" {
+			failed++
+		}
+	}
+	return errors.New(i18n.T("test.casesFailed", failed, len(r.Cases)))
 }
 
-var barMutex sync.Mutex
+// 测试结果
+const (
+	TestResultPassed = "passed"
+	TestResultFailed = "failed"
+)
+
+// 状态响应结构体
+type TaskStatusResponse struct {
+	Code    int            `json:"code"`
+	Message string         `json:"message"`
+	Data    []TestDataItem `json:"data"`
+}
 
-func updateProgress() {
-	barMutex.Lock()
-	defer barMutex.Unlock()
-	if bar != nil {
-		bar.Increment()
-		if bar.Current() >= bar.Total() {
-			bar.Finish()
-			bar = nil
+// NewInstallEvent 创建安装事件处理器，p 显示等待 Pod 和测试的进度，为 nil 时不显示
+func NewInstallEvent(config *TestConfig, p *progress.Progress) (*InstallEvent, error) {
+	client := config.Client
+	if client == nil {
+		c, err := NewHTTPTestClient(config)
+		if err != nil {
+			return nil, err
 		}
+		client = c
+	}
+	e := &InstallEvent{
+		client:         client,
+		clientSet:      config.ClientSet,
+		progress:       p,
+		podInterval:    15 * time.Second,
+		statusInterval: 600 * time.Second,
 	}
+	if config.PollInterval > 0 {
+		e.podInterval, e.statusInterval = config.PollInterval, config.PollInterval
+	}
+	return e, nil
 }
 
 // TestClient 定义了测试用例触发接口
@@ -96,7 +175,28 @@ type TestClient interface {
 
 // installEvent 处理安装事件
 type InstallEvent struct {
-	client TestClient
+	client         TestClient
+	clientSet      kubernetes.Interface
+	podInterval    time.Duration
+	statusInterval time.Duration
+	progress       *progress.Progress
+}
+
+// info 记录等待中的状态，显示进度条时降为 debug 级别，避免打乱进度条
+func (e *InstallEvent) info(msg string, args ...any) {
+	if e.progress != nil {
+		slog.Debug(msg, args...)
+		return
+	}
+	slog.Info(msg, args...)
+}
+
+// kubeClientSet 返回替换的 k8s 客户端，没有则使用 cfg 创建
+func (e *InstallEvent) kubeClientSet(cfg *action.Configuration) (kubernetes.Interface, error) {
+	if e.clientSet != nil {
+		return e.clientSet, nil
+	}
+	return cfg.KubernetesClientSet()
 }
 
 //// NewInstallEvent 创建新的安装事件处理器
@@ -165,50 +265,73 @@ func GetPulsarProxyToken(settings *cli.EnvSettings, ctx context.Context, clients
 	if !ok {
 		return "", fmt.Errorf("token not found in secret %s", secretName)
 	}
-	fmt.Println(string(token))
+	slog.Debug("Read Pulsar proxy token", "namespace", namespace, "name", secretName, "tokenLength", len(token))
 	return string(token), nil
 }
 
-// FinishInstall 完成安装过程
-func (e *InstallEvent) FinishInstall(settings *cli.EnvSettings, cfg *action.Configuration, name string) (string, error) {
-
-	updateProgress()
+// Target 是 release 对外提供服务的 Pulsar 代理地址和管理员 Token
+type Target struct {
+	ProxyIP string
+	Token   string
+}
 
-	clientSet, err := cfg.KubernetesClientSet()
+// ResolveTarget 查找 release 的代理服务地址和管理员 Token
+func (e *InstallEvent) ResolveTarget(settings *cli.EnvSettings, cfg *action.Configuration, name string) (*Target, error) {
+	clientSet, err := e.kubeClientSet(cfg)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	ctx := context.Background()
 	ip, err := GetServiceExternalIp(settings, ctx, clientSet, settings.Namespace(), fmt.Sprintf("%s-proxy", name))
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	token, err := GetPulsarProxyToken(settings, ctx, clientSet, settings.Namespace(), fmt.Sprintf("%s-token-admin", name))
+	if err != nil {
+		return nil, err
+	}
+	return &Target{ProxyIP: ip, Token: token}, nil
+}
+
+// FinishInstall 完成安装过程
+func (e *InstallEvent) FinishInstall(settings *cli.EnvSettings, cfg *action.Configuration, name string) (string, error) {
+	target, err := e.ResolveTarget(settings, cfg, name)
 	if err != nil {
 		return "", err
 	}
 
 	// 触发测试用例
-	responseBody, _ := e.client.Trigger(context.Background(), ip, token)
+	responseBody, err := e.client.Trigger(context.Background(), target.ProxyIP, target.Token)
+	if err != nil {
+		return "", err
+	}
 	var testCaseResponse TestCaseResponse
 	err = json.Unmarshal(responseBody, &testCaseResponse)
 	if err != nil {
-		return "", fmt.Errorf("解析响应体失败: %v", err)
+		return "", fmt.Errorf("%s: %w", i18n.T("test.parseResponse"), err)
 	}
 
 	return testCaseResponse.Data, err
 }
-func (e *InstallEvent) WaitTestCaseFinish(settings *cli.EnvSettings, ctx context.Context, out io.Writer, taskId string) error {
+
+// WaitTestCaseFinish 等待测试任务结束，返回各测试用例的结果，由调用方按输出格式输出
+func (e *InstallEvent) WaitTestCaseFinish(settings *cli.EnvSettings, ctx context.Context, taskId string) (*TestReport, error) {
+	report := &TestReport{TaskID: taskId, Result: TestResultPassed, Cases: []TestCaseResult{}}
+	if e.progress != nil {
+		e.progress.Phase(i18n.T("progress.test"))
+	} else {
+		e.info("Waiting for testcase finish", "taskId", taskId)
+	}
 	// 定义最大重试次数和重试间隔
 	maxRetries := 1000000
-	retryInterval := 600 * time.Second
+	retryInterval := e.statusInterval
 	for i := 0; i < maxRetries; i++ {
 		// 调用 Trigger2 方法检查任务状态
 		resp, err := e.client.Trigger2(context.Background(), taskId)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		var testCaseResponse TestCaseResponse
 
@@ -222,7 +345,12 @@ func (e *InstallEvent) WaitTestCaseFinish(settings *cli.EnvSettings, ctx context
 		var statusResponse TaskStatusResponse
 		err = json.Unmarshal(statusResponseBody, &statusResponse)
 		if err != nil {
-			return fmt.Errorf("解析任务状态响应体失败: %v", err)
+			return nil, fmt.Errorf("%s: %w", i18n.T("test.parseStatus"), err)
+		}
+
+		// 输出测试结果前停止进度条
+		if len(statusResponse.Data) > 0 {
+			e.progress.Stop()
 		}
 
 		// 解析每个数据项中的 message 字段
@@ -230,86 +358,116 @@ func (e *InstallEvent) WaitTestCaseFinish(settings *cli.EnvSettings, ctx context
 			var errorInfo ErrorInfo
 			err := json.Unmarshal([]byte(statusResponse.Data[i].Message), &errorInfo)
 			if err != nil {
-				fmt.Printf("解析 %s 的错误信息失败: %v\n", statusResponse.Data[i].Name, err)
+				slog.Warn("Unable to parse the error of a test case", "name", statusResponse.Data[i].Name, "error", err)
 			} else {
 				statusResponse.Data[i].ErrorInfo = errorInfo
 			}
 		}
 
 		// 这里可以根据解析后的数据进行相应的处理
-		var isDone bool = false
 		for _, item := range statusResponse.Data {
-			fmt.Printf("测试名称: %s\n", item.Name)
-			fmt.Printf("错误信息: %s\n", item.ErrorInfo.ErrorMsg)
-			fmt.Printf("请求 ID: %d\n", item.ErrorInfo.ReqId)
-			fmt.Printf("远程地址: %s\n", item.ErrorInfo.Remote)
-			fmt.Printf("本地地址: %s\n", item.ErrorInfo.Local)
-			fmt.Println("------------------------")
-			isDone = true
+			if item.ErrorInfo.ErrorMsg != "" {
+				report.Result = TestResultFailed
+			}
+			report.Cases = append(report.Cases, TestCaseResult{
+				Name:      item.Name,
+				Error:     item.ErrorInfo.ErrorMsg,
+				RequestID: item.ErrorInfo.ReqId,
+				Remote:    item.ErrorInfo.Remote,
+				Local:     item.ErrorInfo.Local,
+			})
 		}
-		if isDone {
-			fmt.Println("任务已完成（达到最大重试次数）")
+		if len(report.Cases) > 0 {
+			report.Finished = true
 			break
 		}
 		// 假设没有专门的状态字段，可根据实际情况调整循环退出条件
 		// 这里简单模拟任务完成情况
 		// 如果需要根据实际状态判断，可添加相应逻辑
 		if i == maxRetries-1 {
-			fmt.Println("任务已完成（达到最大重试次数）")
+			e.progress.Stop()
 			break
 		}
 
-		fmt.Printf("任务未完成，等待 %v 后重试...\n", retryInterval)
+		e.info("Test task is still running, retrying later", "taskId", taskId, "interval", retryInterval)
 		time.Sleep(retryInterval)
 	}
 
-	return nil
+	return report, nil
 }
 
 func (e *InstallEvent) QueryRunningPod(settings *cli.EnvSettings, ctx context.Context, cfg *action.Configuration, out io.Writer) error {
-	clientSet, err := cfg.KubernetesClientSet()
+	clientSet, err := e.kubeClientSet(cfg)
 	if err != nil {
-		return fmt.Errorf("获取k8s客户端失败: %v\n")
+		return fmt.Errorf("%s: %w", i18n.T("pods.clientSet"), err)
 	}
 
 	namespace := settings.Namespace()
 	timeout := time.After(15 * time.Minute)
-	tick := time.NewTicker(15 * time.Second)
+	tick := time.NewTicker(e.podInterval)
 	defer tick.Stop()
-	updateProgress()
+	e.progress.Phase(i18n.T("progress.pods"))
 	for {
 		select {
 		case <-ctx.Done():
-			return fmt.Errorf("操作被取消")
+			return errors.New(i18n.T("pods.cancelled"))
 		case <-timeout:
-			return fmt.Errorf("等待Pod运行超时")
+			return errors.New(i18n.T("pods.timeout"))
 		case <-tick.C:
 			pods, err := clientSet.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
 			if err != nil {
-				return fmt.Errorf("获取Pod列表失败: %v", err)
+				return fmt.Errorf("%s: %w", i18n.T("pods.list"), err)
 			}
 
 			if len(pods.Items) == 0 {
-				fmt.Fprintf(out, "命名空间 %s 中没有发现Pod\n", namespace)
+				e.info("No pods found in the namespace", "namespace", namespace)
 				continue
 			}
 
 			allRunning := true
 			notRunningPods := []string{}
+			running := map[string]int{}
+			total := map[string]int{}
 
 			for _, pod := range pods.Items {
+				c := podComponent(pod)
+				total[c]++
 				if pod.Status.Phase != "Running" && pod.Status.Phase != "Succeeded" {
 					allRunning = false
 					notRunningPods = append(notRunningPods, fmt.Sprintf("%s(%s)", pod.Name, pod.Status.Phase))
+				} else {
+					running[c]++
 				}
 			}
 
+			components := make([]string, 0, len(total))
+			for c := range total {
+				components = append(components, c)
+			}
+			sort.Strings(components)
+			for _, c := range components {
+				e.progress.Component(c, running[c], total[c])
+			}
+
 			if allRunning {
-				fmt.Fprintf(out, "命名空间 %s 中的所有Pod都已运行\n", namespace)
+				e.info("All pods in the namespace are running", "namespace", namespace)
 				return nil
 			}
 
-			fmt.Fprintf(out, "等待以下Pod运行: %s\n", strings.Join(notRunningPods, ", "))
+			e.info("Waiting for pods to run", "namespace", namespace, "pods", strings.Join(notRunningPods, ", "))
 		}
 	}
 }
+
+// podComponent 返回 Pod 所属的组件，依次取 component 标签、StatefulSet 或 Deployment 等生成的名称前缀
+func podComponent(pod corev1.Pod) string {
+	for _, label := range []string{"app.kubernetes.io/component", "component"} {
+		if c := pod.Labels[label]; c != "" {
+			return c
+		}
+	}
+	if pod.GenerateName != "" {
+		return strings.TrimSuffix(pod.GenerateName, "-")
+	}
+	return pod.Name
+}
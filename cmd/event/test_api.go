@@ -0,0 +1,160 @@
+package installevent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/huangxiaofeng10047/go-cli-example/cmd/i18n"
+)
+
+// 测试服务的接口版本。v1 是 /testpulsar 接口；v2 的请求体带有 apiVersion 和要运行的测试套件，
+// 响应与 v1 相同
+const (
+	APIVersionAuto = "auto"
+	APIVersionV1   = "v1"
+	APIVersionV2   = "v2"
+)
+
+// TaskIDPlaceholder 在状态路由中替换为任务 ID
+const TaskIDPlaceholder = "{taskId}"
+
+// versionsRoute 返回测试服务支持的接口版本，响应为 {"versions": ["v1", "v2"]}。
+// 没有此路由的测试服务只支持 v1
+const versionsRoute = "/versions"
+
+// testAPI 是一个接口版本的路由，路由是相对于测试服务地址的路径
+type testAPI struct {
+	version      string
+	triggerRoute string
+	statusRoute  string
+}
+
+// testAPIs 按优先级从高到低排列，协商时选择测试服务支持的第一个
+var testAPIs = []testAPI{
+	{version: APIVersionV2, triggerRoute: "/v2/runs", statusRoute: "/v2/runs/" + TaskIDPlaceholder},
+	{version: APIVersionV1, triggerRoute: "/testpulsar", statusRoute: "/checkTestPulsarStatus/" + TaskIDPlaceholder},
+}
+
+// findTestAPI 返回 version 对应的接口
+func findTestAPI(version string) (testAPI, bool) {
+	for _, api := range testAPIs {
+		if api.version == version {
+			return api, true
+		}
+	}
+	return testAPI{}, false
+}
+
+// triggerRequest 是触发测试的请求体，v1 没有 apiVersion 和 suites
+type triggerRequest struct {
+	APIVersion           string   `json:"apiVersion,omitempty"`
+	PulsarServiceURL     string   `json:"pulsarServiceUrl"`
+	PulsarHTTPServiceURL string   `json:"pulsarHttpServiceUrl"`
+	AuthToken            string   `json:"authToken"`
+	Suites               []string `json:"suites,omitempty"`
+}
+
+// BaseURL 返回测试服务的地址，不带结尾的 /。设置了 Endpoint 时使用 Endpoint
+func (c *TestConfig) BaseURL() string {
+	if c.Endpoint != "" {
+		return strings.TrimRight(c.Endpoint, "/")
+	}
+	return fmt.Sprintf("%s://%s:%d", c.Schema, c.Host, c.Port)
+}
+
+// parseEndpoint 校验测试服务的地址、接口版本、路由和测试套件
+func parseEndpoint(config *TestConfig) (*url.URL, error) {
+	base := config.BaseURL()
+	u, err := url.Parse(base)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, errors.New(i18n.T("test.badEndpoint", base))
+	}
+	switch config.APIVersion {
+	case "", APIVersionAuto, APIVersionV2:
+	case APIVersionV1:
+		if len(config.Suites) > 0 {
+			return nil, errors.New(i18n.T("test.suitesNeedV2", APIVersionV1))
+		}
+	default:
+		return nil, errors.New(i18n.T("test.badAPIVersion", config.APIVersion))
+	}
+	if config.StatusRoute != "" && !strings.Contains(config.StatusRoute, TaskIDPlaceholder) {
+		return nil, errors.New(i18n.T("test.statusRoute", config.StatusRoute, TaskIDPlaceholder))
+	}
+	return u, nil
+}
+
+// api 返回使用的接口，路由由 --test-trigger-route 和 --test-status-route 覆盖。
+// 接口版本为 auto 时在第一次调用时与测试服务协商，之后复用协商的结果
+func (c *HTTPTestClient) api(ctx context.Context) (testAPI, error) {
+	if c.version == "" || c.version == APIVersionAuto {
+		version, err := c.negotiate(ctx)
+		if err != nil {
+			return testAPI{}, err
+		}
+		if version == APIVersionV1 && len(c.suites) > 0 {
+			return testAPI{}, errors.New(i18n.T("test.suitesNeedV2", version))
+		}
+		slog.Debug("Negotiated test service API version", "version", version)
+		c.version = version
+	}
+	api, _ := findTestAPI(c.version)
+	if c.triggerRoute != "" {
+		api.triggerRoute = c.triggerRoute
+	}
+	if c.statusRoute != "" {
+		api.statusRoute = c.statusRoute
+	}
+	return api, nil
+}
+
+// negotiate 查询测试服务支持的接口版本，返回双方都支持的最高版本
+func (c *HTTPTestClient) negotiate(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.base+versionsRoute, nil)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", i18n.T("test.createRequest"), err)
+	}
+	c.auth.apply(req, nil)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", i18n.T("test.sendRequest"), err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", i18n.T("test.readResponse"), err)
+	}
+
+	logResponse(resp, body)
+
+	// 旧的测试服务没有此路由
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusMethodNotAllowed {
+		return APIVersionV1, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.New(i18n.T("test.badStatus", resp.StatusCode))
+	}
+
+	var versions struct {
+		Versions []string `json:"versions"`
+	}
+	if err := json.Unmarshal(body, &versions); err != nil {
+		return "", fmt.Errorf("%s: %w", i18n.T("test.parseResponse"), err)
+	}
+	for _, api := range testAPIs {
+		if slices.Contains(versions.Versions, api.version) {
+			return api.version, nil
+		}
+	}
+	return "", errors.New(i18n.T("test.noAPIVersion", strings.Join(versions.Versions, ", ")))
+}
@@ -0,0 +1,109 @@
+package installevent
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/huangxiaofeng10047/go-cli-example/cmd/i18n"
+)
+
+// 测试服务认证信息的环境变量，对应的文件参数优先
+const (
+	TokenEnvVar      = "HELM_TEST_CASE_TOKEN"
+	PasswordEnvVar   = "HELM_TEST_CASE_PASSWORD"
+	SigningKeyEnvVar = "HELM_TEST_CASE_SIGNING_KEY"
+)
+
+// 请求签名的请求头
+//
+// 签名是以密钥计算的 HMAC-SHA256，内容为以换行连接的请求方法、路径和查询参数、
+// 时间戳和请求体的 SHA-256，均为十六进制小写：
+//
+//	METHOD\nPATH?QUERY\nTIMESTAMP\nhex(sha256(BODY))
+//
+// 测试服务应拒绝时间戳与当前时间相差过大的请求，以防重放。
+const (
+	SignatureHeader          = "X-Gce-Signature"
+	SignatureTimestampHeader = "X-Gce-Timestamp"
+	signatureVersion         = "v1"
+)
+
+// requestAuth 为测试服务的请求添加认证信息和签名
+type requestAuth struct {
+	bearerToken string
+	username    string
+	password    string
+	signingKey  []byte
+}
+
+// newRequestAuth 从文件或环境变量读取认证信息，bearer token 与 basic 认证只能二选一，
+// basic 认证必须有密码
+func newRequestAuth(config *TestConfig) (*requestAuth, error) {
+	token, err := readSecret(config.TokenFile, TokenEnvVar)
+	if err != nil {
+		return nil, err
+	}
+	a := &requestAuth{bearerToken: token, username: config.Username}
+	if a.username != "" {
+		if a.password, err = readSecret(config.PasswordFile, PasswordEnvVar); err != nil {
+			return nil, err
+		}
+		if a.password == "" {
+			return nil, errors.New(i18n.T("test.noPassword", a.username, PasswordEnvVar))
+		}
+	}
+	if a.bearerToken != "" && a.username != "" {
+		return nil, errors.New(i18n.T("test.authConflict"))
+	}
+	key, err := readSecret(config.SigningKeyFile, SigningKeyEnvVar)
+	if err != nil {
+		return nil, err
+	}
+	if key != "" {
+		a.signingKey = []byte(key)
+	}
+	return a, nil
+}
+
+// readSecret 读取文件中的密钥，未指定文件时读取环境变量，去掉首尾空白
+func readSecret(file, envVar string) (string, error) {
+	if file == "" {
+		return strings.TrimSpace(os.Getenv(envVar)), nil
+	}
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", i18n.T("test.readSecret", file), err)
+	}
+	return strings.TrimSpace(string(b)), nil
+}
+
+// apply 设置认证请求头，并在配置了密钥时对请求签名，body 为请求体
+func (a *requestAuth) apply(req *http.Request, body []byte) {
+	switch {
+	case a.bearerToken != "":
+		req.Header.Set("Authorization", "Bearer "+a.bearerToken)
+	case a.username != "":
+		req.SetBasicAuth(a.username, a.password)
+	}
+	if len(a.signingKey) > 0 {
+		ts := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set(SignatureTimestampHeader, ts)
+		req.Header.Set(SignatureHeader, signatureVersion+"="+sign(a.signingKey, req.Method, req.URL.RequestURI(), ts, body))
+	}
+}
+
+// sign 计算请求的签名，格式见 SignatureHeader
+func sign(key []byte, method, uri, timestamp string, body []byte) string {
+	sum := sha256.Sum256(body)
+	mac := hmac.New(sha256.New, key)
+	fmt.Fprintf(mac, "%s\n%s\n%s\n%s", method, uri, timestamp, hex.EncodeToString(sum[:]))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -4,130 +4,180 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
+	neturl "net/url"
+	"strings"
 	"time"
+
+	tlsutil "github.com/huangxiaofeng10047/go-cli-example/cmd/helm"
+	"github.com/huangxiaofeng10047/go-cli-example/cmd/i18n"
 )
 
 // HTTPTestClient 实现TestClient接口的HTTP客户端
 type HTTPTestClient struct {
 	client *http.Client
-	schema string
-	host   string
-	port   int
+	auth   *requestAuth
+	// base 是测试服务的地址，可以带路径前缀
+	base string
+	// version 是接口版本，auto 时由第一次请求协商
+	version      string
+	triggerRoute string
+	statusRoute  string
+	suites       []string
 }
 
-// NewHTTPTestClient 创建新的HTTP测试客户端
-func NewHTTPTestClient(schema string, host string, port int) *HTTPTestClient {
+// NewHTTPTestClient 创建新的HTTP测试客户端。https 服务使用 config 中的 CA、客户端证书
+// 和认证信息，TLS 参数只能用于 https
+func NewHTTPTestClient(config *TestConfig) (*HTTPTestClient, error) {
+	endpoint, err := parseEndpoint(config)
+	if err != nil {
+		return nil, err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if config.CAFile != "" || config.CertFile != "" || config.KeyFile != "" || config.InsecureSkipTLSVerify {
+		if endpoint.Scheme != "https" {
+			return nil, errors.New(i18n.T("test.tlsNeedsHTTPS", endpoint))
+		}
+		tlsConf, err := tlsutil.NewTLSConfig(
+			tlsutil.WithInsecureSkipVerify(config.InsecureSkipTLSVerify),
+			tlsutil.WithCertKeyPairFiles(config.CertFile, config.KeyFile),
+			tlsutil.WithCAFile(config.CAFile),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", i18n.T("test.tlsConfig"), err)
+		}
+		transport.TLSClientConfig = tlsConf
+	}
+	auth, err := newRequestAuth(config)
+	if err != nil {
+		return nil, err
+	}
 	return &HTTPTestClient{
 		client: &http.Client{
-			Timeout: 30 * time.Minute,
+			Timeout:   30 * time.Minute,
+			Transport: transport,
 		},
-		schema: schema,
-		host:   host,
-		port:   port,
-	}
+		auth:         auth,
+		base:         config.BaseURL(),
+		version:      config.APIVersion,
+		triggerRoute: config.TriggerRoute,
+		statusRoute:  config.StatusRoute,
+		suites:       config.Suites,
+	}, nil
 }
 
 // Trigger 实现TestClient接口的Trigger方法
 func (c *HTTPTestClient) Trigger(ctx context.Context, ip string, token string) ([]byte, error) {
+	api, err := c.api(ctx)
+	if err != nil {
+		return []byte(""), err
+	}
 	// 构建请求URL，使用配置的参数
-	url := fmt.Sprintf("%s://%s:%d/testpulsar", c.schema, c.host, c.port)
-	//输出请求信息：
-	fmt.Printf("请求信息:\n")
-	fmt.Printf("URL: %s\n", url)
-	fmt.Printf("Client配置: schema=%s, host=%s, port=%d\n", c.schema, c.host, c.port)
+	url := c.base + api.triggerRoute
 	// 构建请求体
-	requestBody := map[string]string{
-		"pulsarServiceUrl":     "pulsar://" + ip + ":6650",
-		"pulsarHttpServiceUrl": "http://" + ip,
-		"authToken":            token,
-	}
-	// 打印请求体信息
-	fmt.Printf("请求体:\n")
-	fmt.Printf("PulsarServiceUrl: %s\n", requestBody["pulsarServiceUrl"])
-	fmt.Printf("PulsarHttpServiceUrl: %s\n", requestBody["pulsarHttpServiceUrl"])
-	fmt.Printf("Token长度: %d\n", len(token))
+	requestBody := triggerRequest{
+		PulsarServiceURL:     "pulsar://" + ip + ":6650",
+		PulsarHTTPServiceURL: "http://" + ip,
+		AuthToken:            token,
+	}
+	if api.version != APIVersionV1 {
+		requestBody.APIVersion = api.version
+		requestBody.Suites = c.suites
+	}
+	// 记录请求信息，不记录 Token 本身
+	slog.Debug("Triggering test cases", "url", url,
+		"pulsarServiceUrl", requestBody.PulsarServiceURL,
+		"pulsarHttpServiceUrl", requestBody.PulsarHTTPServiceURL,
+		"suites", requestBody.Suites,
+		"tokenLength", len(token))
 	// 将请求体转换为JSON
 	jsonBody, err := json.Marshal(requestBody)
 	if err != nil {
-		return []byte(""), fmt.Errorf("JSON编码失败: %v", err)
+		return []byte(""), fmt.Errorf("%s: %w", i18n.T("test.encodeRequest"), err)
 	}
 
 	// 创建请求，设置请求体
 	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonBody))
 	if err != nil {
-		return []byte(""), fmt.Errorf("创建HTTP请求失败: %v", err)
+		return []byte(""), fmt.Errorf("%s: %w", i18n.T("test.createRequest"), err)
 	}
 
 	// 设置Content-Type
 	req.Header.Set("Content-Type", "application/json")
+	c.auth.apply(req, jsonBody)
 
 	// 发送请求
 	resp, err := c.client.Do(req)
 	if err != nil {
-		return []byte(""), fmt.Errorf("发送HTTP请求失败: %v", err)
+		return []byte(""), fmt.Errorf("%s: %w", i18n.T("test.sendRequest"), err)
 	}
 	defer resp.Body.Close()
 
 	// 读取响应体
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return []byte(""), fmt.Errorf("读取响应体失败: %v", err)
+		return []byte(""), fmt.Errorf("%s: %w", i18n.T("test.readResponse"), err)
 	}
 
-	// 打印响应信息
-	fmt.Printf("\n响应信息:\n")
-	fmt.Printf("状态码: %d\n", resp.StatusCode)
-	fmt.Printf("响应头: %v\n", resp.Header)
-	fmt.Printf("响应体: %s\n", string(body))
+	logResponse(resp, body)
 
 	// 检查响应状态
 	if resp.StatusCode != http.StatusOK {
-		return []byte(""), fmt.Errorf("HTTP请求返回非200状态码: %d", resp.StatusCode)
+		return []byte(""), errors.New(i18n.T("test.badStatus", resp.StatusCode))
 	}
 
 	return body, nil
 }
 func (c *HTTPTestClient) Trigger2(ctx context.Context, taskId string) (string, error) {
+	api, err := c.api(ctx)
+	if err != nil {
+		return "", err
+	}
 	// 构建请求URL
-	url := fmt.Sprintf("%s://%s:%d/checkTestPulsarStatus/%s", c.schema, c.host, c.port, taskId)
+	url := c.base + strings.ReplaceAll(api.statusRoute, TaskIDPlaceholder, neturl.PathEscape(taskId))
 
-	// 输出请求信息
-	fmt.Printf("请求信息:\n")
-	fmt.Printf("URL: %s\n", url)
-	fmt.Printf("Client配置: schema=%s, host=%s, port=%d\n", c.schema, c.host, c.port)
+	slog.Debug("Querying test task status", "url", url, "taskId", taskId)
 
 	// 创建请求
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
-		return "", fmt.Errorf("创建HTTP请求失败: %v", err)
+		return "", fmt.Errorf("%s: %w", i18n.T("test.createRequest"), err)
 	}
+	c.auth.apply(req, nil)
 
 	// 发送请求
 	resp, err := c.client.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("发送HTTP请求失败: %v", err)
+		return "", fmt.Errorf("%s: %w", i18n.T("test.sendRequest"), err)
 	}
 	defer resp.Body.Close()
 
 	// 读取响应体
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", fmt.Errorf("读取响应体失败: %v", err)
+		return "", fmt.Errorf("%s: %w", i18n.T("test.readResponse"), err)
 	}
 
-	// 打印响应信息
-	fmt.Printf("\n响应信息:\n")
-	fmt.Printf("状态码: %d\n", resp.StatusCode)
-	fmt.Printf("响应头: %v\n", resp.Header)
-	fmt.Printf("响应体: %s\n", string(body))
+	logResponse(resp, body)
 
 	// 检查响应状态
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("HTTP请求返回非200状态码: %d", resp.StatusCode)
+		return "", errors.New(i18n.T("test.badStatus", resp.StatusCode))
 	}
 
 	return string(body), nil
 }
+
+// logResponse 以 debug 级别记录响应，敏感的响应头和响应体内容由日志处理器脱敏
+func logResponse(resp *http.Response, body []byte) {
+	headers := make([]any, 0, len(resp.Header))
+	for name, values := range resp.Header {
+		headers = append(headers, slog.String(name, strings.Join(values, ", ")))
+	}
+	slog.Debug("Test service response", "url", resp.Request.URL.String(), "status", resp.StatusCode,
+		slog.Group("headers", headers...), "body", string(body))
+}
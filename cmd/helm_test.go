@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	installevent "github.com/huangxiaofeng10047/go-cli-example/cmd/event"
+	"helm.sh/helm/v4/pkg/action"
+	"helm.sh/helm/v4/pkg/cli"
+	"helm.sh/helm/v4/pkg/time"
+)
+
+var updateGolden = flag.Bool("update", false, "update golden files")
+
+func testTimestamper() time.Time { return time.Unix(242085845, 0).UTC() }
+
+func init() {
+	action.Timestamper = testTimestamper
+}
+
+// cmdTestCase is a command run against a scenario of testdata, whose output
+// is compared with a golden file of testdata/output.
+type cmdTestCase struct {
+	name string
+	cmd  string
+	// scenario is the fixture loaded in place of a cluster, see LoadScenario.
+	scenario  string
+	golden    string
+	wantError bool
+	// plugins is the plugins directory, none are installed when empty.
+	plugins string
+}
+
+func runTestCmd(t *testing.T, tests []cmdTestCase) {
+	t.Helper()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := executeScenarioCommand(t, tt)
+			if (err != nil) != tt.wantError {
+				t.Errorf("expected error %t, got '%v'", tt.wantError, err)
+			}
+			if tt.golden != "" {
+				assertGolden(t, out, tt.golden)
+			}
+		})
+	}
+}
+
+// executeScenarioCommand runs tt.cmd as main does, with the cluster replaced
+// by the scenario. The output is followed by the calls made to the test
+// service and, if the command failed, by its error.
+func executeScenarioCommand(t *testing.T, tt cmdTestCase) (string, error) {
+	t.Helper()
+	isolateEnv(t, tt.plugins)
+
+	saved := *testConfig
+	t.Cleanup(func() {
+		*testConfig = saved
+		activeProfile = nil
+	})
+
+	args := strings.Fields(tt.cmd)
+	settings := cli.New()
+	cfg := new(action.Configuration)
+	buf := new(bytes.Buffer)
+	debug := func(string, ...interface{}) {}
+
+	root, err := NewRootCmd(settings, cfg, buf, args, debug)
+	if err != nil {
+		return "", err
+	}
+	if err := cfg.Init(settings.RESTClientGetter(), settings.Namespace(), "memory", debug); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadScenario(settings, cfg, filepath.Join("testdata", tt.scenario)); err != nil {
+		t.Fatal(err)
+	}
+	root.SetOut(buf)
+	root.SetErr(io.Discard)
+	root.SetArgs(args)
+	_, err = root.ExecuteC()
+
+	if c, ok := testConfig.Client.(*installevent.FakeTestClient); ok && len(c.Calls) > 0 {
+		fmt.Fprintln(buf, "TEST SERVICE CALLS:")
+		for _, call := range c.Calls {
+			fmt.Fprintln(buf, call)
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(buf, "ERROR: %s\n", err)
+	}
+	return buf.String(), err
+}
+
+// isolateEnv keeps the user's configuration, plugins and language out of the
+// tests.
+func isolateEnv(t *testing.T, plugins string) {
+	t.Helper()
+	for _, env := range os.Environ() {
+		name, _, _ := strings.Cut(env, "=")
+		if strings.HasPrefix(name, "HELM_") || strings.HasPrefix(name, "LC_") || name == "KUBECONFIG" {
+			t.Setenv(name, "")
+			os.Unsetenv(name)
+		}
+	}
+	dir := t.TempDir()
+	if plugins == "" {
+		plugins = filepath.Join(dir, "plugins")
+	} else {
+		abs, err := filepath.Abs(plugins)
+		if err != nil {
+			t.Fatal(err)
+		}
+		plugins = abs
+	}
+	t.Setenv("LANG", "C")
+	t.Setenv("HELM_DEPLOYED_BY", "tester")
+	t.Setenv("HELM_PLUGINS", plugins)
+	t.Setenv("HELM_CONFIG_HOME", filepath.Join(dir, "config"))
+	t.Setenv("HELM_CACHE_HOME", filepath.Join(dir, "cache"))
+	t.Setenv("HELM_DATA_HOME", filepath.Join(dir, "data"))
+	t.Setenv("KUBECONFIG", filepath.Join(dir, "kubeconfig"))
+}
+
+// assertGolden compares actual with testdata/output/filename, which is
+// rewritten instead when the tests run with -update.
+func assertGolden(t *testing.T, actual, filename string) {
+	t.Helper()
+	path := filepath.Join("testdata", "output", filename)
+	if *updateGolden {
+		if err := os.WriteFile(path, []byte(actual), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(want) != actual {
+		t.Errorf("output does not match %s:\n--- want\n%s\n--- got\n%s", path, want, actual)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/gosuri/uitable"
+	"github.com/huangxiaofeng10047/go-cli-example/cmd/i18n"
 	"github.com/spf13/cobra"
 
 	"helm.sh/helm/v4/cmd/helm/require"
@@ -19,30 +20,15 @@ import (
 	helmtime "helm.sh/helm/v4/pkg/time"
 )
 
-var historyHelp = `
-History prints historical revisions for a given release.
-
-A default maximum of 256 revisions will be returned. Setting '--max'
-configures the maximum length of the revision list returned.
-
-The historical release set is printed as a formatted table, e.g:
-
-    $ helm history angry-bird
-    REVISION    UPDATED                     STATUS          CHART             APP VERSION     DESCRIPTION
-    1           Mon Oct 3 10:15:13 2016     superseded      alpine-0.1.0      1.0             Initial install
-    2           Mon Oct 3 10:15:13 2016     superseded      alpine-0.1.0      1.0             Upgraded successfully
-    3           Mon Oct 3 10:15:13 2016     superseded      alpine-0.1.0      1.0             Rolled back to 2
-    4           Mon Oct 3 10:15:13 2016     deployed        alpine-0.1.0      1.0             Upgraded successfully
-`
-
-func newHistoryCmd(settings *cli.EnvSettings, cfg *action.Configuration, out io.Writer) *cobra.Command {
+func newHistoryCmd(settings *cli.EnvSettings, cfg *action.Configuration, out io.Writer, debug action.DebugLog) *cobra.Command {
 	client := action.NewHistory(cfg)
 	var outfmt output.Format
+	var wide, showValuesDiff bool
 
 	cmd := &cobra.Command{
 		Use:     "history RELEASE_NAME",
-		Long:    historyHelp,
-		Short:   "fetch release history",
+		Long:    i18n.T("history.long"),
+		Short:   i18n.T("history.short"),
 		Aliases: []string{"hist"},
 		Args:    require.ExactArgs(1),
 		ValidArgsFunction: func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -52,19 +38,23 @@ func newHistoryCmd(settings *cli.EnvSettings, cfg *action.Configuration, out io.
 			return compListReleases(settings, toComplete, args, cfg)
 		},
 		RunE: func(_ *cobra.Command, args []string) error {
-			history, err := getHistory(client, args[0])
+			history, err := getHistory(client, args[0], showValuesDiff)
 			if err != nil {
 				return err
 			}
 
-			return outfmt.Write(out, history)
+			return outfmt.Write(out, &releaseHistoryWriter{history, wide, showValuesDiff})
 		},
 	}
 
 	f := cmd.Flags()
 	f.IntVar(&client.Max, "max", 256, "maximum number of revision to include in history")
+	f.BoolVar(&wide, "wide", false, i18n.T("history.flagWide"))
+	f.BoolVar(&showValuesDiff, "show-values-diff", false, i18n.T("history.flagShowValuesDiff"))
 	bindOutputFlag(cmd, &outfmt)
 
+	cmd.AddCommand(newHistoryPruneCmd(settings, cfg, out, debug))
+
 	return cmd
 }
 
@@ -75,28 +65,70 @@ type releaseInfo struct {
 	Chart       string        `json:"chart"`
 	AppVersion  string        `json:"app_version"`
 	Description string        `json:"description"`
+	Duration    string        `json:"duration,omitempty"`
+	DeployedBy  string        `json:"deployed_by,omitempty"`
+	TestResult  string        `json:"test_result,omitempty"`
+	ValuesDiff  []valueChange `json:"values_diff,omitempty"`
 }
 
 type releaseHistory []releaseInfo
 
-func (r releaseHistory) WriteJSON(out io.Writer) error {
-	return output.EncodeJSON(out, r)
+type releaseHistoryWriter struct {
+	history        releaseHistory
+	wide           bool
+	showValuesDiff bool
+}
+
+func (r *releaseHistoryWriter) WriteJSON(out io.Writer) error {
+	return output.EncodeJSON(out, r.history)
 }
 
-func (r releaseHistory) WriteYAML(out io.Writer) error {
-	return output.EncodeYAML(out, r)
+func (r *releaseHistoryWriter) WriteYAML(out io.Writer) error {
+	return output.EncodeYAML(out, r.history)
 }
 
-func (r releaseHistory) WriteTable(out io.Writer) error {
+func (r *releaseHistoryWriter) WriteTable(out io.Writer) error {
 	tbl := uitable.New()
-	tbl.AddRow("REVISION", "UPDATED", "STATUS", "CHART", "APP VERSION", "DESCRIPTION")
-	for _, item := range r {
+	if r.wide {
+		tbl.AddRow("REVISION", "UPDATED", "STATUS", "CHART", "APP VERSION", "DURATION", "DEPLOYED BY", "TEST RESULT", "DESCRIPTION")
+	} else {
+		tbl.AddRow("REVISION", "UPDATED", "STATUS", "CHART", "APP VERSION", "DESCRIPTION")
+	}
+	for _, item := range r.history {
+		if r.wide {
+			tbl.AddRow(item.Revision, item.Updated.Format(time.ANSIC), item.Status, item.Chart, item.AppVersion,
+				orDash(item.Duration), orDash(item.DeployedBy), orDash(item.TestResult), item.Description)
+			continue
+		}
 		tbl.AddRow(item.Revision, item.Updated.Format(time.ANSIC), item.Status, item.Chart, item.AppVersion, item.Description)
 	}
-	return output.EncodeTable(out, tbl)
+	if err := output.EncodeTable(out, tbl); err != nil {
+		return err
+	}
+
+	if !r.showValuesDiff {
+		return nil
+	}
+	for _, item := range r.history {
+		if len(item.ValuesDiff) == 0 {
+			continue
+		}
+		fmt.Fprintf(out, "\nREVISION %d:\n", item.Revision)
+		for _, c := range item.ValuesDiff {
+			switch c.Change {
+			case valueAdded:
+				fmt.Fprintf(out, "  + %s: %v\n", c.Key, c.New)
+			case valueRemoved:
+				fmt.Fprintf(out, "  - %s\n", c.Key)
+			default:
+				fmt.Fprintf(out, "  ~ %s: %v -> %v\n", c.Key, c.Old, c.New)
+			}
+		}
+	}
+	return nil
 }
 
-func getHistory(client *action.History, name string) (releaseHistory, error) {
+func getHistory(client *action.History, name string, showValuesDiff bool) (releaseHistory, error) {
 	hist, err := client.Run(name)
 	if err != nil {
 		return nil, err
@@ -115,6 +147,25 @@ func getHistory(client *action.History, name string) (releaseHistory, error) {
 
 	releaseHistory := getReleaseHistory(rels)
 
+	if showValuesDiff {
+		// hist is sorted newest first, so the revision before hist[i] is
+		// hist[i+1]. It may be outside of the --max window.
+		previous := make(map[int]*release.Release, len(hist))
+		for i := 0; i < len(hist)-1; i++ {
+			previous[hist[i].Version] = hist[i+1]
+		}
+		current := make(map[int]*release.Release, len(rels))
+		for _, r := range rels {
+			current[r.Version] = r
+		}
+		for i := range releaseHistory {
+			rev := releaseHistory[i].Revision
+			if prev, ok := previous[rev]; ok {
+				releaseHistory[i].ValuesDiff = diffValues(prev.Config, current[rev].Config)
+			}
+		}
+	}
+
 	return releaseHistory, nil
 }
 
@@ -138,12 +189,24 @@ func getReleaseHistory(rls []*release.Release) (history releaseHistory) {
 			rInfo.Updated = r.Info.LastDeployed
 
 		}
+		if r.Labels != nil {
+			rInfo.Duration = r.Labels[deployDurationLabel]
+			rInfo.DeployedBy = r.Labels[deployedByLabel]
+			rInfo.TestResult = r.Labels[testResultLabel]
+		}
 		history = append(history, rInfo)
 	}
 
 	return history
 }
 
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
 func formatChartName(c *chart.Chart) string {
 	if c == nil || c.Metadata == nil {
 		// This is an edge case that has happened in prod, though we don't
@@ -0,0 +1,276 @@
+package cmd
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/huangxiaofeng10047/go-cli-example/cmd/i18n"
+	"helm.sh/helm/v4/pkg/cli"
+	"io"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gosuri/uitable"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"helm.sh/helm/v4/pkg/action"
+	"helm.sh/helm/v4/pkg/cli/output"
+	"helm.sh/helm/v4/pkg/release"
+	"helm.sh/helm/v4/pkg/storage/driver"
+	helmtime "helm.sh/helm/v4/pkg/time"
+)
+
+// prunedRevision is a revision deleted, or to be deleted, by history prune.
+type prunedRevision struct {
+	Name      string        `json:"name"`
+	Namespace string        `json:"namespace"`
+	Revision  int           `json:"revision"`
+	Status    string        `json:"status"`
+	Updated   helmtime.Time `json:"updated"`
+	Bytes     int           `json:"bytes"`
+}
+
+type pruneResult struct {
+	DryRun         bool             `json:"dry_run"`
+	Revisions      []prunedRevision `json:"revisions"`
+	ReclaimedBytes int              `json:"reclaimed_bytes"`
+}
+
+func newHistoryPruneCmd(settings *cli.EnvSettings, cfg *action.Configuration, out io.Writer, debug action.DebugLog) *cobra.Command {
+	var outfmt output.Format
+	var keep int
+	var olderThan string
+	var allNamespaces, dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "prune [RELEASE...]",
+		Short: i18n.T("historyPrune.short"),
+		Long:  i18n.T("historyPrune.long"),
+		RunE: func(_ *cobra.Command, args []string) error {
+			if keep < 0 {
+				return errors.New(i18n.T("historyPrune.negativeKeep"))
+			}
+			var age time.Duration
+			if olderThan != "" {
+				var err error
+				if age, err = parseAge(olderThan); err != nil {
+					return err
+				}
+			}
+			if keep == 0 && age == 0 {
+				return errors.New(i18n.T("historyPrune.noFilter"))
+			}
+
+			open := namespaceStorage(settings, cfg, debug)
+			store := cfg.Releases
+			if allNamespaces {
+				var err error
+				if store, err = open(""); err != nil {
+					return err
+				}
+			}
+			rels, err := selectReleases(store, args)
+			if err != nil {
+				return err
+			}
+
+			res := &pruneResult{DryRun: dryRun, Revisions: []prunedRevision{}}
+			for _, rel := range pruneCandidates(rels, keep, age, time.Now()) {
+				size, err := storedSize(store.Driver.Name(), rel)
+				if err != nil {
+					return err
+				}
+				if !dryRun {
+					s, err := open(rel.Namespace)
+					if err != nil {
+						return err
+					}
+					if _, err := s.Delete(rel.Name, rel.Version); err != nil {
+						return errors.Wrap(err, i18n.T("historyPrune.delete", rel.Name, rel.Version))
+					}
+				}
+				res.Revisions = append(res.Revisions, prunedRevision{
+					Name:      rel.Name,
+					Namespace: rel.Namespace,
+					Revision:  rel.Version,
+					Status:    rel.Info.Status.String(),
+					Updated:   rel.Info.LastDeployed,
+					Bytes:     size,
+				})
+				res.ReclaimedBytes += size
+			}
+			return outfmt.Write(out, &pruneWriter{res})
+		},
+	}
+
+	f := cmd.Flags()
+	f.IntVar(&keep, "keep", 0, i18n.T("historyPrune.flagKeep"))
+	f.StringVar(&olderThan, "older-than", "", i18n.T("historyPrune.flagOlderThan"))
+	f.BoolVarP(&allNamespaces, "all-namespaces", "A", false, i18n.T("historyPrune.flagAllNamespaces"))
+	f.BoolVar(&dryRun, "dry-run", false, i18n.T("historyPrune.flagDryRun"))
+	bindOutputFlag(cmd, &outfmt)
+
+	return cmd
+}
+
+// pruneCandidates returns the revisions of rels to delete, oldest first.
+func pruneCandidates(rels []*release.Release, keep int, age time.Duration, now time.Time) []*release.Release {
+	histories := map[string][]*release.Release{}
+	for _, rel := range rels {
+		key := rel.Namespace + "/" + rel.Name
+		histories[key] = append(histories[key], rel)
+	}
+
+	var res []*release.Release
+	for _, hist := range histories {
+		sort.Slice(hist, func(i, j int) bool { return hist[i].Version > hist[j].Version })
+		for i, rel := range hist {
+			switch {
+			case i == 0, keep > 0 && i < keep:
+				continue
+			case rel.Info == nil:
+				continue
+			case rel.Info.Status == release.StatusDeployed, rel.Info.Status.IsPending():
+				continue
+			case age > 0 && rel.Info.LastDeployed.Time.After(now.Add(-age)):
+				continue
+			}
+			res = append(res, rel)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		a, b := res[i], res[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Version < b.Version
+	})
+	return res
+}
+
+// parseAge parses a duration that may also be given in days or weeks.
+func parseAge(s string) (time.Duration, error) {
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if n, ok := strings.CutSuffix(s, suffix); ok {
+			v, err := strconv.Atoi(n)
+			if err != nil || v < 0 {
+				return 0, errors.New(i18n.T("historyPrune.badAge", s))
+			}
+			return time.Duration(v) * unit, nil
+		}
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, errors.New(i18n.T("historyPrune.badAge", s))
+	}
+	return d, nil
+}
+
+// storedSize returns the number of bytes the named storage driver uses to
+// store rel, which is what deleting the revision reclaims. The memory driver
+// only lives as long as the process, so nothing is reclaimed.
+func storedSize(driverName string, rel *release.Release) (int, error) {
+	if driverName == driver.MemoryDriverName {
+		return 0, nil
+	}
+	size, err := encodedSize(rel)
+	if err != nil {
+		return 0, err
+	}
+	if driverName != driver.SQLDriverName {
+		return size, nil
+	}
+
+	// The sql driver stores a row of the releases table, with the integer
+	// version, creation and modification times, and a row of the custom
+	// labels table per label.
+	const intColumns = 3 * 8
+	key := fmt.Sprintf("sh.helm.release.v1.%s.v%d", rel.Name, rel.Version)
+	size += len(key) + len("helm.sh/release.v1") + len(rel.Name) + len(rel.Namespace) + len(rel.Info.Status) + len("helm") + intColumns
+	for k, v := range rel.Labels {
+		size += len(key) + len(rel.Namespace) + len(k) + len(v)
+	}
+	return size, nil
+}
+
+// encodedSize returns the size of a release gzipped and base64 encoded, the
+// way the storage drivers encode it.
+func encodedSize(rel *release.Release) (int, error) {
+	b, err := json.Marshal(rel)
+	if err != nil {
+		return 0, err
+	}
+	var buf bytes.Buffer
+	w, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := w.Write(b); err != nil {
+		return 0, err
+	}
+	if err := w.Close(); err != nil {
+		return 0, err
+	}
+	return base64.StdEncoding.EncodedLen(buf.Len()), nil
+}
+
+func formatBytes(n int) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := unit, 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+type pruneWriter struct {
+	result *pruneResult
+}
+
+func (w *pruneWriter) WriteTable(out io.Writer) error {
+	res := w.result
+	if len(res.Revisions) == 0 {
+		_, err := fmt.Fprintln(out, i18n.T("historyPrune.none"))
+		return err
+	}
+	table := uitable.New()
+	table.AddRow("NAME", "NAMESPACE", "REVISION", "STATUS", "UPDATED", "SIZE")
+	var releases []string
+	for _, r := range res.Revisions {
+		table.AddRow(r.Name, r.Namespace, r.Revision, r.Status, r.Updated.Format(time.ANSIC), formatBytes(r.Bytes))
+		if key := r.Namespace + "/" + r.Name; !slices.Contains(releases, key) {
+			releases = append(releases, key)
+		}
+	}
+	if err := output.EncodeTable(out, table); err != nil {
+		return err
+	}
+
+	key := "historyPrune.pruned"
+	if res.DryRun {
+		key = "historyPrune.wouldPrune"
+	}
+	_, err := fmt.Fprint(out, "\n"+i18n.T(key, len(res.Revisions), len(releases), formatBytes(res.ReclaimedBytes)))
+	return err
+}
+
+func (w *pruneWriter) WriteJSON(out io.Writer) error {
+	return output.EncodeJSON(out, w.result)
+}
+
+func (w *pruneWriter) WriteYAML(out io.Writer) error {
+	return output.EncodeYAML(out, w.result)
+}
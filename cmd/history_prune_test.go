@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"helm.sh/helm/v4/pkg/release"
+	"helm.sh/helm/v4/pkg/storage/driver"
+	helmtime "helm.sh/helm/v4/pkg/time"
+)
+
+func TestStoredSize(t *testing.T) {
+	rel := &release.Release{
+		Name:      "pulsar-mini",
+		Namespace: "pulsar",
+		Version:   3,
+		Info:      &release.Info{Status: release.StatusSuperseded},
+		Manifest:  "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: pulsar-mini\n",
+	}
+	encoded, err := encodedSize(rel)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		driver string
+		want   func(int) bool
+	}{
+		{driver.MemoryDriverName, func(n int) bool { return n == 0 }},
+		{driver.SecretsDriverName, func(n int) bool { return n == encoded }},
+		{driver.ConfigMapsDriverName, func(n int) bool { return n == encoded }},
+		{driver.SQLDriverName, func(n int) bool { return n > encoded }},
+	}
+	for _, tt := range tests {
+		got, err := storedSize(tt.driver, rel)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !tt.want(got) {
+			t.Errorf("%s: unexpected size %d, the encoded release is %d bytes", tt.driver, got, encoded)
+		}
+	}
+
+	plain, _ := storedSize(driver.SQLDriverName, rel)
+	rel.Labels = map[string]string{"team": "messaging"}
+	labeled, _ := storedSize(driver.SQLDriverName, rel)
+	if labeled <= plain {
+		t.Errorf("expected the custom labels to add to the sql size, got %d and %d", plain, labeled)
+	}
+}
+
+func TestParseAge(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"36h", 36 * time.Hour, false},
+		{"90m", 90 * time.Minute, false},
+		{"30d", 30 * 24 * time.Hour, false},
+		{"2w", 14 * 24 * time.Hour, false},
+		{"0d", 0, false},
+		{"1.5d", 0, true},
+		{"-3d", 0, true},
+		{"-1h", 0, true},
+		{"d", 0, true},
+		{"month", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseAge(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: expected error %t, got '%v'", tt.input, tt.wantErr, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: expected %s, got %s", tt.input, tt.want, got)
+		}
+	}
+}
+
+func TestPruneCandidates(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	revision := func(name string, version int, status release.Status, daysAgo int) *release.Release {
+		return &release.Release{
+			Name:      name,
+			Namespace: "pulsar",
+			Version:   version,
+			Info:      &release.Info{Status: status, LastDeployed: helmtime.Time{Time: now.AddDate(0, 0, -daysAgo)}},
+		}
+	}
+	// pulsar-mini was rolled back to revision 3, so the deployed revision is
+	// not the latest one.
+	rels := []*release.Release{
+		revision("pulsar-mini", 1, release.StatusSuperseded, 60),
+		revision("pulsar-mini", 2, release.StatusSuperseded, 45),
+		revision("pulsar-mini", 3, release.StatusDeployed, 40),
+		revision("pulsar-mini", 4, release.StatusFailed, 20),
+		revision("pulsar-mini", 5, release.StatusSuperseded, 10),
+		revision("pulsar-mini", 6, release.StatusFailed, 1),
+		revision("kafka", 1, release.StatusSuperseded, 90),
+		revision("kafka", 2, release.StatusPendingUpgrade, 80),
+		revision("kafka", 3, release.StatusSuperseded, 70),
+		revision("zookeeper", 1, release.StatusDeployed, 100),
+	}
+
+	tests := []struct {
+		name string
+		keep int
+		age  time.Duration
+		want []string
+	}{
+		{
+			name: "keep the two newest",
+			keep: 2,
+			want: []string{"kafka.1", "pulsar-mini.1", "pulsar-mini.2", "pulsar-mini.4"},
+		},
+		{
+			name: "older than 30 days",
+			age:  30 * 24 * time.Hour,
+			want: []string{"kafka.1", "pulsar-mini.1", "pulsar-mini.2"},
+		},
+		{
+			name: "keep and age together",
+			keep: 4,
+			age:  30 * 24 * time.Hour,
+			want: []string{"pulsar-mini.1", "pulsar-mini.2"},
+		},
+		{
+			name: "keep more than stored",
+			keep: 10,
+			want: nil,
+		},
+		{
+			name: "only the latest is kept without filters",
+			want: []string{"kafka.1", "pulsar-mini.1", "pulsar-mini.2", "pulsar-mini.4", "pulsar-mini.5"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, rel := range pruneCandidates(slices.Clone(rels), tt.keep, tt.age, now) {
+				got = append(got, fmt.Sprintf("%s.%d", rel.Name, rel.Version))
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
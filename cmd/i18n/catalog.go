@@ -0,0 +1,2278 @@
+package i18n
+
+// catalog maps message keys to their translations. Every message must have
+// an English text; keys are grouped by the command or package that emits them.
+// Log messages are not translated: they stay the same in every language so
+// that logs, JSON ones in particular, can be searched for them.
+var catalog = map[string]map[Lang]string{
+	// Global flags.
+	"flag.lang": {
+		English: "language of messages: en or zh. Defaults to the language of $LC_ALL, $LC_MESSAGES or $LANG",
+		Chinese: "消息语言：en 或 zh。默认使用 $LC_ALL、$LC_MESSAGES 或 $LANG 的语言",
+	},
+	"flag.testCaseSchema": {
+		English: "scheme of the test service, http or https",
+		Chinese: "测试用例协议",
+	},
+	"flag.testCaseHost": {
+		English: "host of the test service",
+		Chinese: "测试用例主机地址",
+	},
+	"flag.testCasePort": {
+		English: "port of the test service",
+		Chinese: "测试用例端口",
+	},
+	"flag.testCaseCAFile": {
+		English: "verify the certificate of the https test service with this CA bundle",
+		Chinese: "使用此 CA 证书校验 https 测试服务的证书",
+	},
+	"flag.testCaseCertFile": {
+		English: "client certificate for mTLS with the test service",
+		Chinese: "与测试服务进行 mTLS 认证的客户端证书",
+	},
+	"flag.testCaseKeyFile": {
+		English: "key of the client certificate for mTLS with the test service",
+		Chinese: "mTLS 客户端证书的私钥",
+	},
+	"flag.testCaseInsecure": {
+		English: "skip the verification of the certificate of the test service (insecure)",
+		Chinese: "跳过测试服务证书校验（不安全）",
+	},
+	"flag.testCaseTokenFile": {
+		English: "file with the bearer token of the test service. Defaults to $HELM_TEST_CASE_TOKEN",
+		Chinese: "保存测试服务 bearer token 的文件，默认读取 $HELM_TEST_CASE_TOKEN",
+	},
+	"flag.testCaseUsername": {
+		English: "username for basic auth with the test service",
+		Chinese: "测试服务 basic 认证的用户名",
+	},
+	"flag.testCasePasswordFile": {
+		English: "file with the basic auth password of the test service. Defaults to $HELM_TEST_CASE_PASSWORD",
+		Chinese: "保存测试服务 basic 认证密码的文件，默认读取 $HELM_TEST_CASE_PASSWORD",
+	},
+	"flag.testCaseSigningKeyFile": {
+		English: "file with the HMAC key that signs the requests to the test service in the X-Gce-Signature header. Defaults to $HELM_TEST_CASE_SIGNING_KEY",
+		Chinese: "保存 HMAC 密钥的文件，用于在 X-Gce-Signature 请求头中对测试服务的请求签名，默认读取 $HELM_TEST_CASE_SIGNING_KEY",
+	},
+	"flag.testEndpoint": {
+		English: "URL of the test service, which may have a path prefix such as https://qa.example/api/v2. Supersedes --test-case-schema, --test-case-host and --test-case-port",
+		Chinese: "测试服务的地址，可以带路径前缀，例如 https://qa.example/api/v2。设置后替代 --test-case-schema、--test-case-host 和 --test-case-port",
+	},
+	"flag.testAPIVersion": {
+		English: "API version of the test service: v1, v2 or auto to ask the service for the versions it supports",
+		Chinese: "测试服务的接口版本：v1、v2，或 auto 查询测试服务支持的版本",
+	},
+	"flag.testTriggerRoute": {
+		English: "route under the test endpoint that triggers the test cases. Defaults to the route of the API version",
+		Chinese: "测试服务地址下触发测试用例的路由，默认使用接口版本的路由",
+	},
+	"flag.testStatusRoute": {
+		English: "route under the test endpoint that returns the status of a task, with {taskId} in place of the task ID. Defaults to the route of the API version",
+		Chinese: "测试服务地址下查询任务状态的路由，{taskId} 替换为任务 ID，默认使用接口版本的路由",
+	},
+	"flag.testSuite": {
+		English: "test suite to run, which needs API version v2. Can be repeated or comma separated. Defaults to the suites chosen by the test service",
+		Chinese: "要运行的测试套件，需要 v2 接口。可以重复或以逗号分隔，默认由测试服务选择",
+	},
+	"flag.testBackend": {
+		English: "name of a plugin with a test hook that runs the test cases instead of the test service. See 'gce plugin --help'",
+		Chinese: "使用带有 test hook 的插件代替测试服务执行测试用例，参见 'gce plugin --help'",
+	},
+	"flag.noProgress": {
+		English: "do not show progress bars, which are only shown when the output is a terminal and the format is table",
+		Chinese: "不显示进度条。仅当输出为终端且格式为 table 时显示进度条",
+	},
+	"flag.profile": {
+		English: "name of the environment profile to deploy with (see --profile-file)",
+		Chinese: "部署时使用的环境配置名称（参见 --profile-file）",
+	},
+	"flag.profileFile": {
+		English: "path to the environment profile file",
+		Chinese: "环境配置文件的路径",
+	},
+	"flag.pprofDir": {
+		English: "write CPU, heap, goroutine, block and mutex profiles, an execution trace and OTLP/JSON spans of the deploy phases to this directory",
+		Chinese: "将 CPU、堆、goroutine、阻塞和互斥锁的性能分析数据、执行跟踪以及部署各阶段的 OTLP/JSON span 写入该目录",
+	},
+	"flag.logLevel": {
+		English: "minimum level of log messages: debug, info, warn or error. --debug sets it to debug",
+		Chinese: "日志消息的最低级别：debug、info、warn 或 error。--debug 会将其设为 debug",
+	},
+	"flag.logFormat": {
+		English: "format of log messages written to stderr: text or json",
+		Chinese: "写入标准错误输出的日志消息格式：text 或 json",
+	},
+	"error.badLogLevel": {
+		English: "invalid --log-level %q",
+		Chinese: "无效的 --log-level %q",
+	},
+	"error.badLogFormat": {
+		English: "invalid --log-format %q, must be %s or %s",
+		Chinese: "无效的 --log-format %q，必须为 %s 或 %s",
+	},
+	"error.readProfile": {
+		English: "unable to read profile file %q",
+		Chinese: "无法读取环境配置文件 %q",
+	},
+	"error.parseProfile": {
+		English: "unable to parse profile file %q",
+		Chinese: "无法解析环境配置文件 %q",
+	},
+	"error.noProfile": {
+		English: "profile %q not found in %s (available: %s)",
+		Chinese: "在 %[2]s 中找不到环境配置 %[1]q（可用的有：%[3]s）",
+	},
+	"error.badKubeVersion": {
+		English: "invalid kube version '%s'",
+		Chinese: "无效的 Kubernetes 版本 '%s'",
+	},
+	"error.unsupportedLang": {
+		English: "unsupported language %q, must be one of: %s",
+		Chinese: "不支持的语言 %q，可选值：%s",
+	},
+
+	// install, upgrade and scale.
+	"release.cancelled": {
+		English: "Release %s has been cancelled.\n",
+		Chinese: "Release %s 已取消。\n",
+	},
+
+	// Phases of the progress display.
+	"progress.render": {
+		English: "Rendering the chart",
+		Chinese: "渲染 chart",
+	},
+	"progress.apply": {
+		English: "Applying resources",
+		Chinese: "创建或更新资源",
+	},
+	"progress.wait": {
+		English: "Waiting for resources to be ready",
+		Chinese: "等待资源就绪",
+	},
+	"progress.hooks": {
+		English: "Running hooks",
+		Chinese: "执行 hook",
+	},
+	"progress.pods": {
+		English: "Waiting for pods to run",
+		Chinese: "等待 Pod 运行",
+	},
+	"progress.checks": {
+		English: "Running post-deploy checks",
+		Chinese: "执行部署后检查",
+	},
+	"progress.test": {
+		English: "Running test cases",
+		Chinese: "执行测试用例",
+	},
+
+	// scale.
+	"scale.short": {
+		English: "scale statefulSet of the cluster",
+		Chinese: "扩容或缩容集群的 StatefulSet",
+	},
+	"scale.long": {
+		English: `
+Scale scales the StatefulSets of a Pulsar cluster.
+
+With '--replicase 0' the StatefulSets whose name starts with NAME- are scaled
+down to zero in the namespace given with -n:
+
+    $ gce scale pulsar-mini --replicase 0 -n pulsar-test
+
+Otherwise the chart is installed with the given values, and the test cases are
+run once the pods are running.
+`,
+		Chinese: `
+扩容或者缩容 Pulsar 集群的 StatefulSet。
+
+使用 '--replicase 0' 时，将 -n 指定的命名空间中名称以 NAME- 开头的
+StatefulSet 缩容为 0：
+
+    $ gce scale pulsar-mini --replicase 0 -n pulsar-test
+
+否则使用给定的 values 安装 chart，并在 Pod 运行后执行测试用例。
+`,
+	},
+	"scale.flagReplicas": {
+		English: "Number of replicas to scale the statefulSet to",
+		Chinese: "StatefulSet 的目标副本数",
+	},
+	"scale.flagNamespace": {
+		English: "Namespace to scale the statefulSet in",
+		Chinese: "StatefulSet 所在的命名空间",
+	},
+	"scale.missingPrefix": {
+		English: "the prefix of the StatefulSets to scale down is required",
+		Chinese: "请提供要匹配的 StatefulSet 前缀",
+	},
+	"scale.scaledDown": {
+		English: "Successfully scaled down %s to %d replicas in namespace %s\n",
+		Chinese: "已将命名空间 %[3]s 中的 %[1]s 缩容为 %[2]d 个副本\n",
+	},
+
+	// Test service client.
+	"test.encodeRequest": {
+		English: "unable to encode the request",
+		Chinese: "JSON编码失败",
+	},
+	"test.createRequest": {
+		English: "unable to create the HTTP request",
+		Chinese: "创建HTTP请求失败",
+	},
+	"test.sendRequest": {
+		English: "unable to send the HTTP request",
+		Chinese: "发送HTTP请求失败",
+	},
+	"test.readResponse": {
+		English: "unable to read the response body",
+		Chinese: "读取响应体失败",
+	},
+	"test.badStatus": {
+		English: "the test service returned status %d",
+		Chinese: "HTTP请求返回非200状态码: %d",
+	},
+	"test.tlsNeedsHTTPS": {
+		English: "the TLS options of the test service need an https endpoint, not %s",
+		Chinese: "测试服务的 TLS 参数需要 https 地址，当前为 %s",
+	},
+	"test.badEndpoint": {
+		English: "invalid test endpoint %q: need an http or https URL",
+		Chinese: "无效的测试服务地址 %q：需要 http 或 https 地址",
+	},
+	"test.badAPIVersion": {
+		English: "invalid API version %q of the test service: need v1, v2 or auto",
+		Chinese: "无效的测试服务接口版本 %q：需要 v1、v2 或 auto",
+	},
+	"test.suitesNeedV2": {
+		English: "test suites need API version v2 of the test service, not %s",
+		Chinese: "测试套件需要测试服务的 v2 接口，当前为 %s",
+	},
+	"test.statusRoute": {
+		English: "status route %q has no %s",
+		Chinese: "状态路由 %q 中没有 %s",
+	},
+	"test.noAPIVersion": {
+		English: "the test service supports none of the API versions of this client: %s",
+		Chinese: "客户端不支持测试服务的任何接口版本：%s",
+	},
+	"test.tlsConfig": {
+		English: "unable to load the TLS configuration of the test service",
+		Chinese: "加载测试服务的 TLS 配置失败",
+	},
+	"test.authConflict": {
+		English: "a bearer token and basic auth cannot both be used for the test service",
+		Chinese: "测试服务不能同时使用 bearer token 和 basic 认证",
+	},
+	"test.noPassword": {
+		English: "no password for the test service user %q: set --test-case-password-file or $%s",
+		Chinese: "测试服务用户 %q 没有密码：请设置 --test-case-password-file 或 $%s",
+	},
+	"test.readSecret": {
+		English: "unable to read %s",
+		Chinese: "读取 %s 失败",
+	},
+	"test.parseResponse": {
+		English: "unable to parse the response",
+		Chinese: "解析响应体失败",
+	},
+	"test.parseStatus": {
+		English: "unable to parse the task status",
+		Chinese: "解析任务状态响应体失败",
+	},
+	"test.resultName": {
+		English: "Test name: %s\n",
+		Chinese: "测试名称: %s\n",
+	},
+	"test.resultError": {
+		English: "Error: %s\n",
+		Chinese: "错误信息: %s\n",
+	},
+	"test.resultRequestID": {
+		English: "Request ID: %d\n",
+		Chinese: "请求 ID: %d\n",
+	},
+	"test.resultRemote": {
+		English: "Remote address: %s\n",
+		Chinese: "远程地址: %s\n",
+	},
+	"test.resultLocal": {
+		English: "Local address: %s\n",
+		Chinese: "本地地址: %s\n",
+	},
+	"test.finished": {
+		English: "Test task finished",
+		Chinese: "测试任务已完成",
+	},
+	"test.result": {
+		English: "Test result: %s",
+		Chinese: "测试结果：%s",
+	},
+	"test.casesFailed": {
+		English: "%d of %d test case(s) failed",
+		Chinese: "%[2]d 个测试用例中有 %[1]d 个失败",
+	},
+	"test.maxRetries": {
+		English: "Stopped waiting for the test task after the maximum number of retries",
+		Chinese: "任务已完成（达到最大重试次数）",
+	},
+
+	// Waiting for pods.
+	"pods.clientSet": {
+		English: "unable to create the Kubernetes client",
+		Chinese: "获取k8s客户端失败",
+	},
+	"pods.cancelled": {
+		English: "operation cancelled",
+		Chinese: "操作被取消",
+	},
+	"pods.timeout": {
+		English: "timed out waiting for the pods to run",
+		Chinese: "等待Pod运行超时",
+	},
+	"pods.list": {
+		English: "unable to list the pods",
+		Chinese: "获取Pod列表失败",
+	},
+
+	// history prune.
+	"historyPrune.short": {
+		English: "delete old revisions of releases",
+		Chinese: "删除 release 的旧版本",
+	},
+	"historyPrune.long": {
+		English: `
+Prune deletes old revisions of releases from the release storage.
+
+'--history-max' only limits the history of releases that are upgraded again.
+Prune cleans up releases that already have many revisions stored:
+
+    # Keep the 10 newest revisions of pulsar-mini
+    $ gce history prune pulsar-mini --keep 10
+
+    # Delete revisions older than 30 days of every release in every namespace
+    $ gce history prune -A --older-than 30d --dry-run
+
+With both --keep and --older-than, only revisions beyond the newest N that are
+also older than the given age are deleted. The deployed revision, the latest
+revision and pending revisions are always kept. Without release names all
+releases of the namespace are pruned.
+
+The reclaimed bytes are the size of the records as the storage driver stores
+them: the gzipped and base64 encoded release of the secret and configmap
+drivers, plus the other columns of the row and the custom labels for the sql
+driver. The memory driver reclaims nothing.
+`,
+		Chinese: `
+从 release 存储中删除 release 的旧版本。
+
+'--history-max' 只限制再次升级的 release 的历史版本数。prune 用于清理
+已经保存了大量版本的 release：
+
+    # 保留 pulsar-mini 最新的 10 个版本
+    $ gce history prune pulsar-mini --keep 10
+
+    # 删除所有命名空间中所有 release 超过 30 天的版本
+    $ gce history prune -A --older-than 30d --dry-run
+
+同时使用 --keep 和 --older-than 时，只删除最新 N 个版本之外、且早于给定时间的
+版本。已部署的版本、最新版本和处于 pending 状态的版本总是保留。不指定 release
+名称时清理命名空间中的所有 release。
+
+回收的字节数是存储驱动保存的记录大小：secret 和 configmap 驱动为 gzip 压缩并
+base64 编码后的 release，sql 驱动还包括该行的其他列和自定义标签。memory 驱动
+不会回收任何空间。
+`,
+	},
+	"historyPrune.flagKeep": {
+		English: "number of newest revisions to keep of each release",
+		Chinese: "每个 release 保留的最新版本数",
+	},
+	"historyPrune.flagOlderThan": {
+		English: "only delete revisions last deployed longer ago than this, e.g. 30d, 2w or 36h",
+		Chinese: "只删除最后部署时间早于此时长的版本，例如 30d、2w 或 36h",
+	},
+	"historyPrune.flagAllNamespaces": {
+		English: "prune releases of all namespaces",
+		Chinese: "清理所有命名空间的 release",
+	},
+	"historyPrune.flagDryRun": {
+		English: "only list the revisions that would be deleted",
+		Chinese: "只列出将被删除的版本",
+	},
+	"historyPrune.negativeKeep": {
+		English: "--keep must not be negative",
+		Chinese: "--keep 不能为负数",
+	},
+	"historyPrune.noFilter": {
+		English: "one of --keep or --older-than is required",
+		Chinese: "需要指定 --keep 或 --older-than",
+	},
+	"historyPrune.badAge": {
+		English: "invalid age %q",
+		Chinese: "无效的时长 %q",
+	},
+	"historyPrune.delete": {
+		English: "unable to delete %s revision %d",
+		Chinese: "删除 %s 的版本 %d 失败",
+	},
+	"historyPrune.none": {
+		English: "No revisions to prune",
+		Chinese: "没有需要清理的版本",
+	},
+	"historyPrune.pruned": {
+		English: "Pruned %d revision(s) of %d release(s), reclaimed %s\n",
+		Chinese: "已清理 %[2]d 个 release 的 %[1]d 个版本，回收 %[3]s\n",
+	},
+	"historyPrune.wouldPrune": {
+		English: "Would prune %d revision(s) of %d release(s), reclaiming %s\n",
+		Chinese: "将清理 %[2]d 个 release 的 %[1]d 个版本，可回收 %[3]s\n",
+	},
+
+	// recover.
+	"recover.short": {
+		English: "recover a release stuck in a pending state",
+		Chinese: "恢复卡在 pending 状态的 release",
+	},
+	"recover.long": {
+		English: `
+Recover a release that is stuck in pending-install, pending-upgrade or
+pending-rollback.
+
+When an install or upgrade is killed before it finishes, the last revision
+stays pending and every later upgrade fails with "another operation is in
+progress". Recover compares the manifest of that revision with the live
+objects to show what was and was not applied, and then:
+
+    --action report       only print the report (default when not on a terminal)
+    --action mark-failed  mark the pending revision as failed, so that the next
+                          upgrade can run
+    --action rollback     mark it failed and roll back to the last successful
+                          revision, or the one given with --to-revision
+
+On a terminal without --action the action is asked for. A revision that has
+been pending for less than --stuck-after may still be in progress and is left
+alone unless --force is given, so recover can be run unattended before every
+upgrade in CI:
+
+    $ gce recover pulsar-mini --action rollback --stuck-after 15m
+`,
+		Chinese: `
+恢复卡在 pending-install、pending-upgrade 或 pending-rollback 状态的 release。
+
+安装或升级在完成前被终止时，最后一个版本会一直处于 pending 状态，之后的每次
+升级都会失败并提示 "another operation is in progress"。recover 将该版本的
+manifest 与集群中的对象比较，显示哪些对象已创建、哪些没有，然后：
+
+    --action report       只输出报告（不在终端中运行时的默认值）
+    --action mark-failed  将 pending 版本标记为失败，以便执行下一次升级
+    --action rollback     标记为失败并回滚到最后一个成功的版本，或
+                          --to-revision 指定的版本
+
+在终端中运行且没有 --action 时会询问要执行的操作。pending 时间少于
+--stuck-after 的版本可能仍在执行，除非指定 --force，否则不做处理，因此可以在
+CI 中每次升级前无人值守地运行 recover：
+
+    $ gce recover pulsar-mini --action rollback --stuck-after 15m
+`,
+	},
+	"recover.flagAction": {
+		English: "what to do with a stuck revision: %s, %s or %s",
+		Chinese: "对卡住的版本执行的操作：%s、%s 或 %s",
+	},
+	"recover.flagStuckAfter": {
+		English: "how long a revision must have been pending to be considered stuck",
+		Chinese: "版本处于 pending 状态多久后视为卡住",
+	},
+	"recover.flagForce": {
+		English: "recover the release even if it has been pending for less than --stuck-after",
+		Chinese: "即使 pending 时间少于 --stuck-after 也恢复 release",
+	},
+	"recover.flagToRevision": {
+		English: "revision to roll back to, defaults to the last successful revision",
+		Chinese: "回滚的目标版本，默认为最后一个成功的版本",
+	},
+	"recover.flagWait": {
+		English: "wait until the rolled back resources are ready",
+		Chinese: "等待回滚后的资源就绪",
+	},
+	"recover.flagTimeout": {
+		English: "time to wait for any individual Kubernetes operation (like Jobs for hooks)",
+		Chinese: "等待单个 Kubernetes 操作（例如 hook 的 Job）的时间",
+	},
+	"recover.badAction": {
+		English: "invalid action %q, must be one of: %s, %s, %s",
+		Chinese: "无效的操作 %q，可选值：%s、%s、%s",
+	},
+	"recover.notPending": {
+		English: "revision %d is %s, nothing to recover",
+		Chinese: "版本 %d 的状态为 %s，无需恢复",
+	},
+	"recover.notStuck": {
+		English: "revision %d has been %s for less than %s and may still be in progress, use --force to recover it anyway",
+		Chinese: "版本 %d 处于 %s 状态不到 %s，可能仍在执行，使用 --force 强制恢复",
+	},
+	"recover.stuck": {
+		English: "revision %d is stuck in %s, run with --action mark-failed or --action rollback to recover it",
+		Chinese: "版本 %d 卡在 %s 状态，使用 --action mark-failed 或 --action rollback 恢复",
+	},
+	"recover.markedFailed": {
+		English: "marked revision %d as failed",
+		Chinese: "已将版本 %d 标记为失败",
+	},
+	"recover.rolledBack": {
+		English: "marked revision %d as failed and rolled back to revision %d",
+		Chinese: "已将版本 %d 标记为失败并回滚到版本 %d",
+	},
+	"recover.rollbackFailed": {
+		English: "revision %d was marked as failed, but the rollback to revision %d failed",
+		Chinese: "版本 %d 已标记为失败，但回滚到版本 %d 失败",
+	},
+	"recover.markFailed": {
+		English: "unable to mark revision %d as failed",
+		Chinese: "将版本 %d 标记为失败时出错",
+	},
+	"recover.noSuccessful": {
+		English: "release %s has no successful revision to roll back to, use --action mark-failed and install again",
+		Chinese: "release %s 没有可回滚的成功版本，请使用 --action mark-failed 后重新安装",
+	},
+	"recover.ownedBy": {
+		English: "owned by release %s",
+		Chinese: "属于 release %s",
+	},
+	"recover.ask": {
+		English: "Mark the revision as failed [f], roll back [r] or leave it [N]? ",
+		Chinese: "将版本标记为失败 [f]、回滚 [r] 还是不处理 [N]？",
+	},
+
+	// lint.
+	"lint.short": {
+		English: "check values against the chart's values schema",
+		Chinese: "根据 chart 的 values schema 检查 values",
+	},
+	"lint.long": {
+		English: `
+Lint checks the values you would deploy with against the values of a chart,
+before anything is rendered or sent to the cluster.
+
+The chart's values.schema.json is used when present. Otherwise the schema is
+inferred from the default values.yaml: keys that do not exist in the defaults
+are unknown, and a value whose type differs from the default is reported as a
+warning. Keys whose comment in values.yaml mentions 'deprecated' are reported
+when they are set. Subcharts are checked against their own values.
+
+    $ gce lint ./charts/pulsar -f ./charts/values.yaml --set broker.replicaCount=3
+    SEVERITY    POSITION                    KEY             MESSAGE
+    error       charts/values.yaml:12:3     brokr           unknown key, did you mean "broker"?
+    warning     charts/values.yaml:40:1     persistence     deprecated: Deprecated in favor of using ` + "`volumes.persistence`" + `
+
+Lint exits with an error when an error is found. Use '--strict' to also fail on
+warnings. The same check runs before install and upgrade with '--strict-values'.
+`,
+		Chinese: `
+在渲染 chart 或访问集群之前，根据 chart 的 values 检查要部署的 values。
+
+chart 有 values.schema.json 时使用它，否则从默认的 values.yaml 推断 schema：
+默认值中不存在的键为未知键，类型与默认值不同的值报告为警告。values.yaml 中
+注释提到 'deprecated' 的键在被设置时会报告。子 chart 使用自己的 values 检查。
+
+    $ gce lint ./charts/pulsar -f ./charts/values.yaml --set broker.replicaCount=3
+    SEVERITY    POSITION                    KEY             MESSAGE
+    error       charts/values.yaml:12:3     brokr           未知的键，是否是 "broker"？
+    warning     charts/values.yaml:40:1     persistence     已废弃：Deprecated in favor of using ` + "`volumes.persistence`" + `
+
+发现错误时 lint 以错误退出。使用 '--strict' 时警告也视为失败。安装和升级时
+使用 '--strict-values' 执行同样的检查。
+`,
+	},
+	"lint.flagStrict": {
+		English: "fail on lint warnings",
+		Chinese: "有警告时也视为失败",
+	},
+	"lint.flagStrictValues": {
+		English: "check values against the chart's values schema before deploying and fail on unknown keys or type mismatches",
+		Chinese: "部署前根据 chart 的 values schema 检查 values，有未知的键或类型不匹配时失败",
+	},
+	"lint.failed": {
+		English: "values of chart %s: %d error(s), %d warning(s)",
+		Chinese: "chart %s 的 values：%d 个错误，%d 个警告",
+	},
+	"lint.strictFailed": {
+		English: "values failed strict validation with %d error(s)",
+		Chinese: "values 严格校验失败，有 %d 个错误",
+	},
+	"lint.none": {
+		English: "No problems found in values",
+		Chinese: "values 中没有发现问题",
+	},
+	"lint.parseSchema": {
+		English: "unable to parse values.schema.json of chart %s",
+		Chinese: "解析 chart %s 的 values.schema.json 失败",
+	},
+	"lint.parseValues": {
+		English: "unable to parse values.yaml of chart %s",
+		Chinese: "解析 chart %s 的 values.yaml 失败",
+	},
+	"lint.readValues": {
+		English: "failed to read values file %s",
+		Chinese: "读取 values 文件 %s 失败",
+	},
+	"lint.parseFile": {
+		English: "failed to parse %s",
+		Chinese: "解析 %s 失败",
+	},
+	"lint.typeMismatch": {
+		English: "type mismatch: expected %s, got %s",
+		Chinese: "类型不匹配：期望 %s，实际为 %s",
+	},
+	"lint.or": {
+		English: " or ",
+		Chinese: " 或 ",
+	},
+	"lint.unknownKey": {
+		English: "unknown key",
+		Chinese: "未知的键",
+	},
+	"lint.didYouMean": {
+		English: "unknown key, did you mean %q?",
+		Chinese: "未知的键，是否是 %q？",
+	},
+	"lint.deprecated": {
+		English: "deprecated: %s",
+		Chinese: "已废弃：%s",
+	},
+
+	// Policy checks of install, upgrade and template.
+	"policy.flagPolicy": {
+		English: "check the rendered manifests against the policies in this directory before anything is sent to the cluster",
+		Chinese: "在向集群发送任何内容之前，使用此目录中的策略检查渲染出的 manifest",
+	},
+	"policy.flagOutput": {
+		English: "format of the policy report written to stderr. Allowed values: %s",
+		Chinese: "写到 stderr 的策略报告的格式，可选值：%s",
+	},
+	"policy.parseFile": {
+		English: "unable to parse policy file %s",
+		Chinese: "解析策略文件 %s 失败",
+	},
+	"policy.unknownRule": {
+		English: "%s: rule %d: unknown rule type %q",
+		Chinese: "%s：第 %d 条规则：未知的规则类型 %q",
+	},
+	"policy.badSeverity": {
+		English: "%s: rule %q: severity must be %s or %s",
+		Chinese: "%s：规则 %q：severity 必须为 %s 或 %s",
+	},
+	"policy.none": {
+		English: "no policies found in %s",
+		Chinese: "%s 中没有策略",
+	},
+	"policy.failed": {
+		English: "policy %s",
+		Chinese: "策略 %s",
+	},
+	"policy.badOutput": {
+		English: "invalid policy output %q, must be one of: %s",
+		Chinese: "无效的策略报告格式 %q，可选值：%s",
+	},
+	"policy.violations": {
+		English: "%d policy violation(s) found",
+		Chinese: "发现 %d 处违反策略",
+	},
+	"policy.render": {
+		English: "unable to render chart for policy check",
+		Chinese: "渲染 chart 以进行策略检查失败",
+	},
+	"policy.noViolations": {
+		English: "No policy violations found",
+		Chinese: "没有发现违反策略的对象",
+	},
+	"policy.parseObject": {
+		English: "unable to parse %s/%s",
+		Chinese: "解析 %s/%s 失败",
+	},
+	"policy.badSelector": {
+		English: "invalid selector in %s/%s",
+		Chinese: "%s/%s 中的选择器无效",
+	},
+	"policy.noTag": {
+		English: "container %q uses image %q without a tag",
+		Chinese: "容器 %q 使用的镜像 %q 没有 tag",
+	},
+	"policy.latestTag": {
+		English: "container %q uses the ':latest' tag (%s)",
+		Chinese: "容器 %q 使用了 ':latest' tag（%s）",
+	},
+	"policy.noResources": {
+		English: "container %q has no %s",
+		Chinese: "容器 %q 没有设置 %s",
+	},
+	"policy.privileged": {
+		English: "container %q runs privileged",
+		Chinese: "容器 %q 以特权模式运行",
+	},
+	"policy.noPDB": {
+		English: "no PodDisruptionBudget selects the pods of this StatefulSet",
+		Chinese: "没有 PodDisruptionBudget 选中此 StatefulSet 的 Pod",
+	},
+	"policy.noStorageClass": {
+		English: "claim %q does not set a storage class",
+		Chinese: "存储卷声明 %q 没有设置 storage class",
+	},
+	"policy.storageClass": {
+		English: "claim %q uses storage class %q, allowed: %s",
+		Chinese: "存储卷声明 %q 使用的 storage class %q 不被允许，允许的有：%s",
+	},
+
+	// check-apis.
+	"checkAPIs.short": {
+		English: "check for deprecated and removed Kubernetes APIs",
+		Chinese: "检查已废弃和已移除的 Kubernetes API",
+	},
+	"checkAPIs.long": {
+		English: `
+Check-apis reports resources of a release or chart that use an API version that
+is deprecated or removed in a target Kubernetes version, together with the API
+that replaces it.
+
+For an installed release the stored manifests are checked. Otherwise the
+argument is treated as a chart and rendered locally for '--kube-version', so
+templates that depend on .Capabilities render as they would on that cluster.
+The check uses tables built into this binary and never contacts the cluster.
+
+    $ gce check-apis pulsar -n pulsar --kube-version 1.25
+    OBJECT                           API VERSION      STATUS    DEPRECATED IN   REMOVED IN   REPLACEMENT
+    PodSecurityPolicy/pulsar-broker  policy/v1beta1   removed   v1.21           v1.25        none, use Pod Security Admission
+
+The command fails when a removed API is found. 'upgrade --check-apis' runs the
+same check before upgrading.
+`,
+		Chinese: `
+报告 release 或 chart 中使用了在目标 Kubernetes 版本中已废弃或已移除的 API
+版本的资源，以及替代的 API。
+
+对于已安装的 release，检查保存的 manifest。否则将参数视为 chart，并按
+'--kube-version' 在本地渲染，因此依赖 .Capabilities 的模板会像在该集群上一样
+渲染。检查使用内置在程序中的表，不会访问集群。
+
+    $ gce check-apis pulsar -n pulsar --kube-version 1.25
+    OBJECT                           API VERSION      STATUS    DEPRECATED IN   REMOVED IN   REPLACEMENT
+    PodSecurityPolicy/pulsar-broker  policy/v1beta1   removed   v1.21           v1.25        none, use Pod Security Admission
+
+发现已移除的 API 时命令失败。'upgrade --check-apis' 在升级前执行同样的检查。
+`,
+	},
+	"checkAPIs.flagKubeVersion": {
+		English: "Kubernetes version to check against, e.g. 1.25",
+		Chinese: "检查所针对的 Kubernetes 版本，例如 1.25",
+	},
+	"checkAPIs.flagAPIVersions": {
+		English: "Kubernetes api versions used for Capabilities.APIVersions when rendering a chart",
+		Chinese: "渲染 chart 时用于 Capabilities.APIVersions 的 Kubernetes API 版本",
+	},
+	"checkAPIs.flagCheckAPIs": {
+		English: "check the chart and the deployed revision for APIs removed in the cluster's Kubernetes version before upgrading",
+		Chinese: "升级前检查 chart 和已部署的版本是否使用了集群 Kubernetes 版本中已移除的 API",
+	},
+	"checkAPIs.flagCheckAPIsKubeVersion": {
+		English: "Kubernetes version used by --check-apis instead of the cluster's version",
+		Chinese: "--check-apis 使用的 Kubernetes 版本，代替集群的版本",
+	},
+	"checkAPIs.noKubeVersion": {
+		English: "--kube-version is required",
+		Chinese: "需要指定 --kube-version",
+	},
+	"checkAPIs.removed": {
+		English: "%d resource(s) use APIs removed in Kubernetes %s: %s",
+		Chinese: "%d 个资源使用了 Kubernetes %s 中已移除的 API：%s",
+	},
+	"checkAPIs.none": {
+		English: "No deprecated or removed APIs found",
+		Chinese: "没有发现已废弃或已移除的 API",
+	},
+	"checkAPIs.serverVersion": {
+		English: "unable to get the Kubernetes version, use --check-apis-kube-version",
+		Chinese: "获取 Kubernetes 版本失败，请使用 --check-apis-kube-version",
+	},
+	"checkAPIs.render": {
+		English: "unable to render chart for API check",
+		Chinese: "渲染 chart 以检查 API 失败",
+	},
+	"checkAPIs.deployedRemoved": {
+		English: "the deployed revision must be migrated to supported APIs before it can be upgraded",
+		Chinese: "已部署的版本必须先迁移到受支持的 API 才能升级",
+	},
+
+	// storage.
+	"storage.short": {
+		English: "export, import and migrate release records between storage drivers",
+		Chinese: "在存储驱动之间导出、导入和迁移 release 记录",
+	},
+	"storage.long": {
+		English: `
+Move release records between storage backends.
+
+Releases are stored by the driver selected with $HELM_DRIVER: configmap,
+secret (the default), memory or sql. These commands copy every revision of the
+selected releases, including their labels, without touching the deployed
+resources.
+
+    # Snapshot all releases of a cluster before tearing it down
+    $ gce storage export -A -f releases.yaml
+
+    # Move the history of the pulsar namespace into a central SQL store
+    $ export HELM_DRIVER_SQL_CONNECTION_STRING=postgres://...
+    $ gce storage migrate --from secret --to sql -n pulsar
+
+Exported files are lists of releases, the format HELM_MEMORY_DRIVER_DATA
+reads, so they can also seed the memory driver.
+`,
+		Chinese: `
+在存储后端之间移动 release 记录。
+
+release 由 $HELM_DRIVER 选择的驱动保存：configmap、secret（默认）、memory 或
+sql。这些命令复制所选 release 的每个版本及其标签，不会改动已部署的资源。
+
+    # 在销毁集群前保存所有 release 的快照
+    $ gce storage export -A -f releases.yaml
+
+    # 将 pulsar 命名空间的历史迁移到集中的 SQL 存储
+    $ export HELM_DRIVER_SQL_CONNECTION_STRING=postgres://...
+    $ gce storage migrate --from secret --to sql -n pulsar
+
+导出的文件是 release 列表，即 HELM_MEMORY_DRIVER_DATA 读取的格式，因此也可以
+用来初始化 memory 驱动。
+`,
+	},
+	"storage.exportShort": {
+		English: "export all revisions of releases to a YAML file",
+		Chinese: "将 release 的所有版本导出为 YAML 文件",
+	},
+	"storage.exportLong": {
+		English: `
+Export writes every revision of the selected releases as YAML. Without
+arguments all releases of the namespace, or of all namespaces with -A, are
+exported. The records are read with the current $HELM_DRIVER.
+`,
+		Chinese: `
+将所选 release 的每个版本输出为 YAML。没有参数时导出命名空间中的所有
+release，使用 -A 时导出所有命名空间的 release。记录使用当前的 $HELM_DRIVER
+读取。
+`,
+	},
+	"storage.importShort": {
+		English: "import release records exported with 'storage export'",
+		Chinese: "导入 'storage export' 导出的 release 记录",
+	},
+	"storage.importLong": {
+		English: `
+Import stores the releases of a file written by 'storage export' with the
+current $HELM_DRIVER, or the one given with --driver. Each record is created
+in its own namespace. Revisions that already exist are skipped unless
+--overwrite is given.
+`,
+		Chinese: `
+使用当前的 $HELM_DRIVER 或 --driver 指定的驱动保存 'storage export' 输出的
+文件中的 release。每条记录创建在它自己的命名空间中。除非指定 --overwrite，
+否则跳过已存在的版本。
+`,
+	},
+	"storage.migrateShort": {
+		English: "copy release records from one storage driver to another",
+		Chinese: "将 release 记录从一个存储驱动复制到另一个",
+	},
+	"storage.migrateLong": {
+		English: `
+Migrate copies the revisions of the selected releases from one storage driver
+to another, for example from Kubernetes Secrets to SQL. Use --delete-source to
+remove the records from the source driver once they are copied.
+`,
+		Chinese: `
+将所选 release 的版本从一个存储驱动复制到另一个，例如从 Kubernetes Secret
+复制到 SQL。使用 --delete-source 在复制后从源驱动删除记录。
+`,
+	},
+	"storage.flagExportAll": {
+		English: "export releases of all namespaces",
+		Chinese: "导出所有命名空间的 release",
+	},
+	"storage.flagFile": {
+		English: "write the releases to this file instead of stdout",
+		Chinese: "将 release 写入此文件而不是标准输出",
+	},
+	"storage.flagDriver": {
+		English: "storage driver to import into. Allowed values: %s",
+		Chinese: "导入的目标存储驱动，可选值：%s",
+	},
+	"storage.flagOverwrite": {
+		English: "replace revisions that already exist",
+		Chinese: "替换已存在的版本",
+	},
+	"storage.flagImportDryRun": {
+		English: "only report what would be imported",
+		Chinese: "只报告将要导入的内容",
+	},
+	"storage.flagFrom": {
+		English: "storage driver to read from. Allowed values: %s",
+		Chinese: "读取的源存储驱动，可选值：%s",
+	},
+	"storage.flagTo": {
+		English: "storage driver to write to",
+		Chinese: "写入的目标存储驱动",
+	},
+	"storage.flagMigrateAll": {
+		English: "migrate releases of all namespaces",
+		Chinese: "迁移所有命名空间的 release",
+	},
+	"storage.flagMigrateOverwrite": {
+		English: "replace revisions that already exist in the target",
+		Chinese: "替换目标中已存在的版本",
+	},
+	"storage.flagMigrateDryRun": {
+		English: "only report what would be migrated",
+		Chinese: "只报告将要迁移的内容",
+	},
+	"storage.flagDeleteSource": {
+		English: "delete the records from the source driver after copying them",
+		Chinese: "复制后从源驱动删除记录",
+	},
+	"storage.exported": {
+		English: "Exported %s to %s\n",
+		Chinese: "已将 %s 导出到 %s\n",
+	},
+	"storage.imported": {
+		English: "Imported %s\n",
+		Chinese: "已导入 %s\n",
+	},
+	"storage.wouldImport": {
+		English: "Would import %s\n",
+		Chinese: "将导入 %s\n",
+	},
+	"storage.skipped": {
+		English: "Skipped %s that already exist, use --overwrite to replace them\n",
+		Chinese: "跳过已存在的 %s，使用 --overwrite 替换\n",
+	},
+	"storage.deleted": {
+		English: "Deleted %s from %s\n",
+		Chinese: "已从 %[2]s 删除 %[1]s\n",
+	},
+	"storage.revisionCount": {
+		English: "%d revision(s) of %d release(s)",
+		Chinese: "%[2]d 个 release 的 %[1]d 个版本",
+	},
+	"storage.parse": {
+		English: "unable to parse %s",
+		Chinese: "解析 %s 失败",
+	},
+	"storage.noFromTo": {
+		English: "both --from and --to are required",
+		Chinese: "需要同时指定 --from 和 --to",
+	},
+	"storage.sameDriver": {
+		English: "--from and --to are the same driver %q",
+		Chinese: "--from 和 --to 是同一个驱动 %q",
+	},
+	"storage.delete": {
+		English: "unable to delete %s revision %d from %s",
+		Chinese: "从 %[3]s 删除 %[1]s 的版本 %[2]d 失败",
+	},
+	"storage.unknownDriver": {
+		English: "unknown storage driver %q, must be one of: %s",
+		Chinese: "未知的存储驱动 %q，可选值：%s",
+	},
+	"storage.open": {
+		English: "unable to open storage driver %q",
+		Chinese: "打开存储驱动 %q 失败",
+	},
+	"storage.notFound": {
+		English: "release: %q not found",
+		Chinese: "release %q 不存在",
+	},
+	"storage.store": {
+		English: "unable to store %s revision %d",
+		Chinese: "保存 %s 的版本 %d 失败",
+	},
+	"storage.notRelease": {
+		English: "record %d is not a release",
+		Chinese: "第 %d 条记录不是 release",
+	},
+
+	// outdated.
+	"outdated.short": {
+		English: "list releases whose chart has newer versions",
+		Chinese: "列出 chart 有更新版本的 release",
+	},
+	"outdated.long": {
+		English: `
+Outdated compares the chart version of every deployed release with the newest
+version of that chart in the cached repository indexes and in OCI registries.
+
+Repositories come from 'helm repo add' and are read from the local cache, so
+run 'helm repo update' first. OCI charts are listed with --oci, or with
+repositories.yaml entries that have an oci:// URL, and their tags are read
+from the registry.
+
+For every release that is behind, the semver distance, the changes recorded
+in the 'artifacthub.io/changes' annotation of the newer versions, and the
+upgrade command are shown:
+
+    $ gce outdated -n pulsar
+    NAME         NAMESPACE  CHART   INSTALLED  LATEST  DISTANCE  BEHIND  SOURCE  DEPRECATED
+    pulsar-mini  pulsar     pulsar  3.9.0      4.0.2   major     4       apache  false
+
+    pulsar-mini (3.9.0 -> 4.0.2)
+      - changed: Upgrade Apache Pulsar to 4.0.2
+      gce upgrade pulsar-mini apache/pulsar --version 4.0.2 -n pulsar
+
+Releases that are up to date, or whose chart is not found, are only listed
+with --all. Deprecated charts are always listed.
+`,
+		Chinese: `
+将每个已部署 release 的 chart 版本与本地缓存的仓库索引和 OCI 仓库中该 chart
+的最新版本进行比较。
+
+仓库来自 'helm repo add'，从本地缓存读取，因此请先运行 'helm repo update'。
+OCI chart 通过 --oci 或 repositories.yaml 中 oci:// 地址的条目指定，其 tag
+从镜像仓库读取。
+
+对每个落后的 release，显示语义化版本差距、较新版本的 'artifacthub.io/changes'
+注解中记录的变更以及升级命令：
+
+    $ gce outdated -n pulsar
+    NAME         NAMESPACE  CHART   INSTALLED  LATEST  DISTANCE  BEHIND  SOURCE  DEPRECATED
+    pulsar-mini  pulsar     pulsar  3.9.0      4.0.2   major     4       apache  false
+
+    pulsar-mini (3.9.0 -> 4.0.2)
+      - changed: Upgrade Apache Pulsar to 4.0.2
+      gce upgrade pulsar-mini apache/pulsar --version 4.0.2 -n pulsar
+
+已是最新或找不到 chart 的 release 只在使用 --all 时列出。已废弃的 chart 总是
+列出。
+`,
+	},
+	"outdated.flagAllNamespaces": {
+		English: "check releases across all namespaces",
+		Chinese: "检查所有命名空间的 release",
+	},
+	"outdated.flagFilter": {
+		English: "a regular expression (Perl compatible). Only releases that match the expression are checked",
+		Chinese: "正则表达式（Perl 兼容），只检查匹配的 release",
+	},
+	"outdated.flagOCI": {
+		English: "also look up charts in this OCI chart reference, e.g. oci://registry.local/charts/pulsar (can specify multiple)",
+		Chinese: "同时在此 OCI chart 地址中查找 chart，例如 oci://registry.local/charts/pulsar（可以指定多个）",
+	},
+	"outdated.flagPlainHTTP": {
+		English: "use insecure HTTP connections for OCI registries",
+		Chinese: "使用不安全的 HTTP 连接访问 OCI 仓库",
+	},
+	"outdated.flagDevel": {
+		English: "also consider development versions (alpha, beta, and release candidate releases)",
+		Chinese: "同时考虑开发版本（alpha、beta 和 release candidate 版本）",
+	},
+	"outdated.flagAll": {
+		English: "also list releases that are up to date or whose chart was not found",
+		Chinese: "同时列出已是最新或找不到 chart 的 release",
+	},
+	"outdated.none": {
+		English: "All releases are up to date",
+		Chinese: "所有 release 都已是最新版本",
+	},
+
+	// plugin.
+	"plugin.short": {
+		English: "install, list, update, or uninstall plugins",
+		Chinese: "安装、列出、更新或卸载插件",
+	},
+	"plugin.long": {
+		English: `
+Manage client-side plugins.
+
+Plugins are installed in $HELM_PLUGINS and added as top-level commands. A
+plugin whose name is the name or an alias of a built-in command, such as
+'install' or 'scale', is not loaded and cannot be installed.
+
+PLUGIN PROTOCOL
+
+Besides adding commands, a plugin can take part in 'install' and 'upgrade' by
+declaring a hook for one of these events in its plugin.yaml:
+
+    post-deploy  runs once the pods of the release are running. Every plugin
+                 with this hook runs, unless --no-checks is given.
+    test         runs the test cases instead of the test service, for the
+                 plugin named with --test-backend.
+
+    name: smoke
+    version: 0.1.0
+    platformHooks:
+      post-deploy:
+        - command: "$HELM_PLUGIN_DIR/check.sh"
+
+The hook reads a JSON request from stdin:
+
+    {
+      "apiVersion": "gce.plugin/v1",
+      "event": "post-deploy",
+      "release": {"name": "pulsar-mini", "namespace": "pulsar", "revision": 2,
+                  "status": "deployed", "chart": "pulsar", "chartVersion": "3.9.0",
+                  "appVersion": "4.0.2", "manifest": "..."},
+      "endpoints": {"testService": "http://127.0.0.1:8080",
+                    "pulsarServiceUrl": "pulsar://10.0.0.10:6650",
+                    "pulsarHttpServiceUrl": "http://10.0.0.10"},
+      "credentials": {"authToken": "..."}
+    }
+
+and writes a JSON response to stdout. The status of a result is passed, failed
+or skipped:
+
+    {"results": [{"name": "produce-consume", "status": "passed", "message": "..."}]}
+
+Post-deploy results are printed after the release status, or, with '-o json'
+and '-o yaml', as the 'checks' field of the release document. A failed check
+fails the command and is recorded as the test result of the revision. Results
+of a test backend are reported like those of the test service. A hook that
+exits with an error or writes an invalid response fails. Logs of the hook
+belong on stderr.
+`,
+		Chinese: `
+管理客户端插件。
+
+插件安装在 $HELM_PLUGINS 中，并作为顶级命令添加。名称与内置命令的名称或别名
+相同的插件（例如 'install' 或 'scale'）不会被加载，也不能安装。
+
+插件协议
+
+除了添加命令，插件还可以在 plugin.yaml 中为以下事件声明 hook，参与 'install'
+和 'upgrade'：
+
+    post-deploy  在 release 的 Pod 运行后执行。除非指定 --no-checks，否则
+                 所有带有此 hook 的插件都会执行。
+    test         对 --test-backend 指定的插件，代替测试服务执行测试用例。
+
+    name: smoke
+    version: 0.1.0
+    platformHooks:
+      post-deploy:
+        - command: "$HELM_PLUGIN_DIR/check.sh"
+
+hook 从标准输入读取 JSON 请求：
+
+    {
+      "apiVersion": "gce.plugin/v1",
+      "event": "post-deploy",
+      "release": {"name": "pulsar-mini", "namespace": "pulsar", "revision": 2,
+                  "status": "deployed", "chart": "pulsar", "chartVersion": "3.9.0",
+                  "appVersion": "4.0.2", "manifest": "..."},
+      "endpoints": {"testService": "http://127.0.0.1:8080",
+                    "pulsarServiceUrl": "pulsar://10.0.0.10:6650",
+                    "pulsarHttpServiceUrl": "http://10.0.0.10"},
+      "credentials": {"authToken": "..."}
+    }
+
+并向标准输出写入 JSON 响应。结果的状态为 passed、failed 或 skipped：
+
+    {"results": [{"name": "produce-consume", "status": "passed", "message": "..."}]}
+
+post-deploy 的结果在 release 状态之后输出；使用 '-o json' 和 '-o yaml' 时
+作为 release 文档的 'checks' 字段输出。检查失败时命令失败，并记录为该版本的
+测试结果。测试后端的结果与测试服务的结果一样输出。以错误退出或输出无效响应的
+hook 视为失败。hook 的日志应写到标准错误。
+`,
+	},
+	"plugin.flagNoChecks": {
+		English: "do not run the post-deploy checks of plugins",
+		Chinese: "不执行插件的部署后检查",
+	},
+	"plugin.checksFailed": {
+		English: "post-deploy checks failed",
+		Chinese: "部署后检查失败",
+	},
+	"plugin.hookExited": {
+		English: "plugin %s hook for %q exited with error",
+		Chinese: "插件 %[2]q 的 %[1]s hook 以错误退出",
+	},
+	"plugin.hookFailed": {
+		English: "plugin %s hook for %q failed",
+		Chinese: "插件 %[2]q 的 %[1]s hook 执行失败",
+	},
+	"plugin.invalidResponse": {
+		English: "plugin %s hook for %q wrote an invalid response",
+		Chinese: "插件 %[2]q 的 %[1]s hook 输出了无效的响应",
+	},
+	"plugin.invalidStatus": {
+		English: "invalid status %q. %s",
+		Chinese: "无效的状态 %q。%s",
+	},
+	"plugin.noHook": {
+		English: "plugin %q has no %s hook",
+		Chinese: "插件 %q 没有 %s hook",
+	},
+	"plugin.noBackend": {
+		English: "test backend %q is not an installed plugin with a %s hook",
+		Chinese: "测试后端 %q 不是已安装的带有 %s hook 的插件",
+	},
+	"plugin.installShort": {
+		English: "install a plugin",
+		Chinese: "安装插件",
+	},
+	"plugin.installLong": {
+		English: `
+This command installs a plugin from a local directory, a local or remote
+.tgz/.tar.gz archive, or the URL of a VCS repository:
+
+    $ gce plugin install ./my-plugin
+    $ gce plugin install ./my-plugin-1.0.0.tgz
+    $ gce plugin install https://example.com/my-plugin-1.0.0.tar.gz
+    $ gce plugin install https://github.com/example/my-plugin --version 1.0.0
+
+A local directory is linked rather than copied, so changes to it take effect
+immediately. The install hook of the plugin runs once it is installed.
+`,
+		Chinese: `
+从本地目录、本地或远程的 .tgz/.tar.gz 压缩包或 VCS 仓库地址安装插件：
+
+    $ gce plugin install ./my-plugin
+    $ gce plugin install ./my-plugin-1.0.0.tgz
+    $ gce plugin install https://example.com/my-plugin-1.0.0.tar.gz
+    $ gce plugin install https://github.com/example/my-plugin --version 1.0.0
+
+本地目录以链接而不是复制的方式安装，因此对它的修改立即生效。插件安装后执行
+它的 install hook。
+`,
+	},
+	"plugin.flagVersion": {
+		English: "specify a version constraint. If this is not specified, the latest version is installed",
+		Chinese: "指定版本约束，不指定时安装最新版本",
+	},
+	"plugin.installed": {
+		English: "Installed plugin: %s\n",
+		Chinese: "已安装插件：%s\n",
+	},
+	"plugin.unusable": {
+		English: "plugin is installed but unusable",
+		Chinese: "插件已安装但无法使用",
+	},
+	"plugin.conflict": {
+		English: "plugin %q conflicts with the built-in command %q and was not installed",
+		Chinese: "插件 %q 与内置命令 %q 冲突，未安装",
+	},
+	"plugin.extract": {
+		English: "extracting files from %s",
+		Chinese: "解压 %s 失败",
+	},
+	"plugin.noPlugin": {
+		English: "%s does not contain a plugin",
+		Chinese: "%s 中没有插件",
+	},
+	"plugin.archiveUpdate": {
+		English: "a plugin installed from an archive cannot be updated, uninstall it and install the new archive",
+		Chinese: "从压缩包安装的插件不能更新，请卸载后安装新的压缩包",
+	},
+	"plugin.listShort": {
+		English: "list installed plugins",
+		Chinese: "列出已安装的插件",
+	},
+	"plugin.notLoaded": {
+		English: "(not loaded: conflicts with the built-in command %q) %s",
+		Chinese: "（未加载：与内置命令 %q 冲突）%s",
+	},
+	"plugin.uninstallShort": {
+		English: "uninstall one or more plugins",
+		Chinese: "卸载一个或多个插件",
+	},
+	"plugin.uninstallNoName": {
+		English: "please provide plugin name to uninstall",
+		Chinese: "请提供要卸载的插件名称",
+	},
+	"plugin.uninstallFailed": {
+		English: "Failed to uninstall plugin %s, got error (%v)",
+		Chinese: "卸载插件 %s 失败（%v）",
+	},
+	"plugin.uninstalled": {
+		English: "Uninstalled plugin: %s\n",
+		Chinese: "已卸载插件：%s\n",
+	},
+	"plugin.updateShort": {
+		English: "update one or more plugins",
+		Chinese: "更新一个或多个插件",
+	},
+	"plugin.updateLong": {
+		English: `
+This command updates plugins installed from a VCS repository to the latest
+version. Plugins installed from a local directory are always up to date;
+plugins installed from an archive have to be uninstalled and installed again.
+`,
+		Chinese: `
+将从 VCS 仓库安装的插件更新到最新版本。从本地目录安装的插件总是最新的；从
+压缩包安装的插件需要卸载后重新安装。
+`,
+	},
+	"plugin.updateNoName": {
+		English: "please provide plugin name to update",
+		Chinese: "请提供要更新的插件名称",
+	},
+	"plugin.updateFailed": {
+		English: "Failed to update plugin %s, got error (%v)",
+		Chinese: "更新插件 %s 失败（%v）",
+	},
+	"plugin.updated": {
+		English: "Updated plugin: %s\n",
+		Chinese: "已更新插件：%s\n",
+	},
+	"plugin.notFound": {
+		English: "Plugin: %s not found",
+		Chinese: "插件 %s 不存在",
+	},
+
+	// dependency verify.
+	"depVerify.short": {
+		English: "verify the charts/ directory against the Chart.lock file",
+		Chinese: "根据 Chart.lock 文件校验 charts/ 目录",
+	},
+	"depVerify.long": {
+		English: `
+Verify that the charts/ directory matches the Chart.lock file. CHART is the
+chart directory and defaults to the current directory.
+
+For every locked dependency the vendored archive or directory in 'charts/' must
+exist and have the locked name and version. Archives are also compared with the
+digest published in the cached repository index, when one is available. Charts
+in 'charts/' that are not in the lock file are reported as extra, and the lock
+file itself must still match the dependencies declared in Chart.yaml.
+
+The files of a vendored directory are compared with the locked archive. The
+archive is taken from the repository cache,
+$HELM_REPOSITORY_CACHE/<name>-<version>.tgz, or downloaded from the dependency
+repository, and must match the digest of the repository index. An archive that
+does not match is reported as a digest mismatch. An archive that cannot be
+fetched fails the command, unless '--allow-unverified' is set, in which case
+the directory is reported as unverified.
+
+Newer versions that satisfy the version constraint of a dependency are listed
+from the cached repository indexes; run 'helm repo update' to refresh them.
+The chart is not changed.
+
+The command exits with an error when any drift is found:
+
+    $ gce dependency verify charts/pulsar
+    NAME                    CONSTRAINT  LOCKED  VENDORED             STATUS  NEWER
+    kube-prometheus-stack   65.x.x      65.8.1  65.8.1 (directory)   ok      65.8.2
+`,
+		Chinese: `
+校验 charts/ 目录是否与 Chart.lock 文件一致。CHART 为 chart 目录，默认为当前目录。
+
+对每个锁定的依赖，'charts/' 中必须存在名称和版本与锁定一致的压缩包或目录。
+压缩包还会与本地缓存的仓库索引中发布的摘要比较（如果有）。'charts/' 中不在
+锁文件里的 chart 报告为多余，锁文件本身也必须与 Chart.yaml 中声明的依赖一致。
+
+目录形式的依赖会与锁定的压缩包比较文件内容。压缩包取自仓库缓存
+$HELM_REPOSITORY_CACHE/<name>-<version>.tgz，或从依赖仓库下载，并且必须与仓库
+索引中的摘要一致。不一致的压缩包报告为摘要不一致。无法获取压缩包时命令失败；
+设置 '--allow-unverified' 时则将该目录报告为未校验。
+
+满足依赖版本约束的更新版本从本地缓存的仓库索引中列出；运行
+'helm repo update' 刷新索引。不会修改 chart。
+
+发现任何偏离时命令以错误退出：
+
+    $ gce dependency verify charts/pulsar
+    NAME                    CONSTRAINT  LOCKED  VENDORED             STATUS  NEWER
+    kube-prometheus-stack   65.x.x      65.8.1  65.8.1 (目录)        ok      65.8.2
+`,
+	},
+	"depVerify.lockOutOfSync": {
+		English: "Chart.lock of %s is out of sync with Chart.yaml, run 'helm dependency update'",
+		Chinese: "%s 的 Chart.lock 与 Chart.yaml 不一致，请运行 'helm dependency update'",
+	},
+	"depVerify.lockWarning": {
+		English: "WARNING: Chart.lock is out of sync with the dependencies in Chart.yaml",
+		Chinese: "警告：Chart.lock 与 Chart.yaml 中的依赖不一致",
+	},
+	"depVerify.drifted": {
+		English: "%d dependency(ies) of %s drifted from Chart.lock",
+		Chinese: "%[2]s 有 %[1]d 个依赖与 Chart.lock 不一致",
+	},
+	"depVerify.notChart": {
+		English: "%s is not a chart directory",
+		Chinese: "%s 不是 chart 目录",
+	},
+	"depVerify.parseLock": {
+		English: "unable to parse Chart.lock",
+		Chinese: "解析 Chart.lock 失败",
+	},
+	"depVerify.load": {
+		English: "unable to load %s",
+		Chinese: "加载 %s 失败",
+	},
+	"depVerify.dependency": {
+		English: "dependency %s",
+		Chinese: "依赖 %s",
+	},
+	"depVerify.notLocked": {
+		English: "not in Chart.lock",
+		Chinese: "不在 Chart.lock 中",
+	},
+	"depVerify.constraint": {
+		English: "locked version %s does not satisfy %s",
+		Chinese: "锁定的版本 %s 不满足 %s",
+	},
+	"depVerify.missing": {
+		English: "%s-%s not found in charts/",
+		Chinese: "charts/ 中没有 %s-%s",
+	},
+	"depVerify.versionDrift": {
+		English: "vendored version %s, locked %s",
+		Chinese: "charts/ 中的版本为 %s，锁定的版本为 %s",
+	},
+	"depVerify.digestMismatch": {
+		English: "archive digest %s, repository index %s",
+		Chinese: "压缩包摘要为 %s，仓库索引中为 %s",
+	},
+	"depVerify.noArchive": {
+		English: "unable to fetch %s to compare the directory with: %s",
+		Chinese: "无法获取用于比较目录内容的 %s：%s",
+	},
+	"depVerify.fetch": {
+		English: "unable to fetch %s",
+		Chinese: "无法获取 %s",
+	},
+	"depVerify.archiveMismatch": {
+		English: "%s digest %s, repository index %s",
+		Chinese: "%s 的摘要为 %s，仓库索引中为 %s",
+	},
+	"depVerify.flagAllowUnverified": {
+		English: "report vendored directories whose locked archive cannot be fetched as unverified instead of failing",
+		Chinese: "无法获取锁定的压缩包时，将目录形式的依赖报告为未校验，而不是失败",
+	},
+	"depVerify.readArchive": {
+		English: "unable to read %s",
+		Chinese: "读取 %s 失败",
+	},
+	"depVerify.contentDrift": {
+		English: "differs from %s, %s",
+		Chinese: "与 %s 不一致，%s",
+	},
+	"depVerify.changed": {
+		English: "changed",
+		Chinese: "已修改",
+	},
+	"depVerify.added": {
+		English: "added",
+		Chinese: "新增",
+	},
+	"depVerify.removed": {
+		English: "removed",
+		Chinese: "已删除",
+	},
+	"depVerify.andMore": {
+		English: "%s and %d more",
+		Chinese: "%s 等，另有 %d 个",
+	},
+	"depVerify.badConstraint": {
+		English: "invalid version constraint %q",
+		Chinese: "无效的版本约束 %q",
+	},
+	"depVerify.badVersion": {
+		English: "invalid version %q",
+		Chinese: "无效的版本 %q",
+	},
+	"depVerify.badLockedVersion": {
+		English: "invalid locked version %q",
+		Chinese: "无效的锁定版本 %q",
+	},
+	"depVerify.archive": {
+		English: "archive",
+		Chinese: "压缩包",
+	},
+	"depVerify.directory": {
+		English: "directory",
+		Chinese: "目录",
+	},
+
+	// images.
+	"images.short": {
+		English: "list the container images of a release or chart",
+		Chinese: "列出 release 或 chart 的容器镜像",
+	},
+	"images.long": {
+		English: `
+Images lists every container image used by a release or a chart.
+
+For an installed release the stored manifests and hooks are used. Otherwise the
+argument is treated as a chart, which is rendered locally with the given values,
+including all enabled subcharts such as kube-prometheus-stack.
+
+    $ gce images pulsar -n pulsar
+    IMAGE                                       USED BY
+    docker.io/apachepulsar/pulsar-all:3.0.7     StatefulSet/pulsar-broker, StatefulSet/pulsar-proxy, ...
+
+Every 'image' field of the rendered objects is reported, so images of custom
+resources (e.g. Prometheus) are found as well.
+`,
+		Chinese: `
+列出 release 或 chart 使用的所有容器镜像。
+
+对于已安装的 release，使用其保存的清单和 hook。否则参数被视为 chart，并使用
+给定的 values 在本地渲染，包括所有启用的子 chart，例如 kube-prometheus-stack。
+
+    $ gce images pulsar -n pulsar
+    IMAGE                                       USED BY
+    docker.io/apachepulsar/pulsar-all:3.0.7     StatefulSet/pulsar-broker, StatefulSet/pulsar-proxy, ...
+
+渲染出的对象中的每个 'image' 字段都会被报告，因此自定义资源（例如 Prometheus）
+的镜像也能被找到。
+`,
+	},
+	"images.dependencies": {
+		English: "An error occurred while checking for chart dependencies. You may need to run `helm dependency build` to fetch missing dependencies",
+		Chinese: "检查 chart 依赖时出错。可能需要运行 `helm dependency build` 来获取缺失的依赖",
+	},
+	"images.parseManifest": {
+		English: "unable to parse rendered manifest",
+		Chinese: "无法解析渲染出的清单",
+	},
+
+	// images bundle.
+	"bundle.short": {
+		English: "pack a chart and its images for air-gapped installs",
+		Chinese: "打包 chart 及其镜像，用于离线安装",
+	},
+	"bundle.long": {
+		English: `
+Bundle packs a chart, its dependencies and every image it uses into a single
+tarball for air-gapped environments.
+
+The images are found the same way as by 'images' and are stored as an OCI image
+layout inside the bundle. With '--registry-prefix' the bundle records where the
+images will live in the private registry:
+
+    $ gce images bundle ./charts/pulsar -f ./charts/values.yaml \
+        --registry-prefix registry.local:5000/pulsar
+
+The bundle is then installed with:
+
+    $ gce install pulsar --from-bundle pulsar-3.9.0-bundle.tgz --push-images
+
+which pushes the images to the registry prefix and rewrites the image
+references of the rendered manifests to point at it. Hooks are not
+post-rendered, so images used only by hooks keep their original reference.
+`,
+		Chinese: `
+将 chart、其依赖以及其使用的所有镜像打包成一个 tar 包，用于离线环境。
+
+镜像的查找方式与 'images' 相同，并以 OCI 镜像布局保存在包中。使用
+'--registry-prefix' 时，包会记录镜像在私有镜像仓库中的位置：
+
+    $ gce images bundle ./charts/pulsar -f ./charts/values.yaml \
+        --registry-prefix registry.local:5000/pulsar
+
+之后使用以下命令安装该包：
+
+    $ gce install pulsar --from-bundle pulsar-3.9.0-bundle.tgz --push-images
+
+它会把镜像推送到镜像仓库前缀下，并改写渲染出的清单中的镜像引用，使其指向该
+前缀。hook 不会经过后期渲染，因此只被 hook 使用的镜像保留原来的引用。
+`,
+	},
+	"bundle.flagPlainHTTP": {
+		English: "use insecure HTTP connections for image registries",
+		Chinese: "对镜像仓库使用不安全的 HTTP 连接",
+	},
+	"bundle.flagFromBundle": {
+		English: "install the chart of a bundle created by 'images bundle' instead of a CHART argument",
+		Chinese: "安装由 'images bundle' 创建的包中的 chart，而不是 CHART 参数",
+	},
+	"bundle.flagInstallRegistryPrefix": {
+		English: "registry prefix the images of the bundle are retargeted to. Defaults to the prefix the bundle was created with",
+		Chinese: "包中镜像重定向到的镜像仓库前缀。默认为创建包时使用的前缀",
+	},
+	"bundle.flagPushImages": {
+		English: "push the images of the bundle to the registry prefix before installing",
+		Chinese: "安装前将包中的镜像推送到镜像仓库前缀下",
+	},
+	"bundle.flagRegistryPrefix": {
+		English: "private registry prefix the images will be retargeted to on install, e.g. registry.local:5000/pulsar",
+		Chinese: "安装时镜像重定向到的私有镜像仓库前缀，例如 registry.local:5000/pulsar",
+	},
+	"bundle.flagFile": {
+		English: "bundle file to write. Defaults to CHART-VERSION-bundle.tgz",
+		Chinese: "要写入的包文件。默认为 CHART-VERSION-bundle.tgz",
+	},
+	"bundle.flagPlatform": {
+		English: "only bundle images for this platform, e.g. linux/amd64. All platforms are bundled by default",
+		Chinese: "只打包该平台的镜像，例如 linux/amd64。默认打包所有平台",
+	},
+	"bundle.pulling": {
+		English: "Pulling %s",
+		Chinese: "正在拉取 %s",
+	},
+	"bundle.pushing": {
+		English: "Pushing %s",
+		Chinese: "正在推送 %s",
+	},
+	"bundle.written": {
+		English: "Bundle with %d image(s) written to %s",
+		Chinese: "包含 %d 个镜像的包已写入 %s",
+	},
+	"bundle.pull": {
+		English: "unable to pull %s",
+		Chinese: "无法拉取 %s",
+	},
+	"bundle.push": {
+		English: "unable to push %s",
+		Chinese: "无法推送 %s",
+	},
+	"bundle.extract": {
+		English: "unable to extract bundle %s",
+		Chinese: "无法解压包 %s",
+	},
+	"bundle.parse": {
+		English: "unable to parse %s of bundle %s",
+		Chinese: "无法解析包 %[2]s 中的 %[1]s",
+	},
+	"bundle.badPlatform": {
+		English: "invalid platform %q, expected OS/ARCH[/VARIANT]",
+		Chinese: "无效的平台 %q，应为 OS/ARCH[/VARIANT]",
+	},
+	"bundle.illegalPath": {
+		English: "illegal path %q in archive",
+		Chinese: "压缩包中有非法路径 %q",
+	},
+	"bundle.notBundle": {
+		English: "%s is not a bundle",
+		Chinese: "%s 不是一个包",
+	},
+
+	// search.
+	"search.short": {
+		English: "search for a keyword in charts",
+		Chinese: "在 chart 中搜索关键字",
+	},
+	"search.long": {
+		English: `
+Search provides the ability to search for Helm charts in the various places
+they can be stored: the repositories added with 'helm repo add', OCI
+registries and local chart directories.
+`,
+		Chinese: `
+Search 用于在 Helm chart 可能存放的各个位置中搜索 chart：通过 'helm repo add'
+添加的仓库、OCI 镜像仓库以及本地 chart 目录。
+`,
+	},
+	"search.repoShort": {
+		English: "search repositories for a keyword in charts",
+		Chinese: "在仓库的 chart 中搜索关键字",
+	},
+	"search.repoLong": {
+		English: `
+Search reads through all of the repositories configured on the system, and
+looks for matches. Search of these repositories uses the metadata stored on
+the system.
+
+Charts in OCI registries and in local directories are searched too: every
+--oci chart reference, and repositories.yaml entries with an oci:// URL, are
+indexed from the tags of the registry, and every --chart-dir is indexed from
+the chart directories and archives it contains. OCI results are named after the
+registry path of the chart, without the oci:// scheme, and the SOURCE column
+tells where a result was found.
+
+It will display the latest stable versions of the charts found. If you
+specify the --devel flag, the output will include pre-release versions.
+If you want to search using a version constraint, use --version.
+
+Examples:
+
+    # Search for stable release versions matching the keyword "nginx"
+    $ helm search repo nginx
+
+    # Search for release versions matching the keyword "nginx", including pre-release versions
+    $ helm search repo nginx --devel
+
+    # Search for the latest stable release for nginx-ingress with a major version of 1
+    $ helm search repo nginx-ingress --version ^1.0.0
+
+    # Search the local charts and a chart in a registry for charts that
+    # support Kubernetes 1.28 and are tagged with the "messaging" keyword
+    $ helm search repo --chart-dir ./charts --oci oci://registry.local/charts/pulsar \
+        --keyword messaging --kube-version 1.28.0
+
+    # Only show charts with an annotation, and an app version of 3.x
+    $ helm search repo --annotation category=Streaming --app-version ^3
+
+Repositories are managed with 'helm repo' commands.
+`,
+		Chinese: `
+Search 读取系统中配置的所有仓库并查找匹配项。对这些仓库的搜索使用系统中保存的
+元数据。
+
+OCI 镜像仓库和本地目录中的 chart 也会被搜索：每个 --oci chart 引用，以及
+repositories.yaml 中 URL 为 oci:// 的条目，都根据镜像仓库的标签建立索引；每个
+--chart-dir 则根据其包含的 chart 目录和压缩包建立索引。OCI 结果以 chart 在镜像
+仓库中的路径命名（不含 oci:// 前缀），SOURCE 列表示结果的来源。
+
+默认显示找到的 chart 的最新稳定版本。指定 --devel 时，输出会包含预发布版本。
+如需按版本约束搜索，请使用 --version。
+
+示例：
+
+    # 搜索与关键字 "nginx" 匹配的稳定版本
+    $ helm search repo nginx
+
+    # 搜索与关键字 "nginx" 匹配的版本，包括预发布版本
+    $ helm search repo nginx --devel
+
+    # 搜索主版本为 1 的 nginx-ingress 的最新稳定版本
+    $ helm search repo nginx-ingress --version ^1.0.0
+
+    # 在本地 chart 和镜像仓库中的一个 chart 里搜索支持 Kubernetes 1.28
+    # 且带有 "messaging" 关键字的 chart
+    $ helm search repo --chart-dir ./charts --oci oci://registry.local/charts/pulsar \
+        --keyword messaging --kube-version 1.28.0
+
+    # 只显示带有某个注解且应用版本为 3.x 的 chart
+    $ helm search repo --annotation category=Streaming --app-version ^3
+
+仓库通过 'helm repo' 命令管理。
+`,
+	},
+	"search.flagRegexp": {
+		English: "use regular expressions for searching repositories you have added",
+		Chinese: "在已添加的仓库中使用正则表达式搜索",
+	},
+	"search.flagVersions": {
+		English: "show the long listing, with each version of each chart on its own line, for repositories you have added",
+		Chinese: "对已添加的仓库显示详细列表，每个 chart 的每个版本各占一行",
+	},
+	"search.flagDevel": {
+		English: "use development versions (alpha, beta, and release candidate releases), too. Equivalent to version '>0.0.0-0'. If --version is set, this is ignored",
+		Chinese: "同时使用开发版本（alpha、beta 和候选发布版本）。等同于版本 '>0.0.0-0'。设置了 --version 时忽略此选项",
+	},
+	"search.flagVersion": {
+		English: "search using semantic versioning constraints on repositories you have added",
+		Chinese: "在已添加的仓库中按语义化版本约束搜索",
+	},
+	"search.flagMaxColWidth": {
+		English: "maximum column width for output table",
+		Chinese: "输出表格的最大列宽",
+	},
+	"search.flagFailOnNoResult": {
+		English: "search fails if no results are found",
+		Chinese: "没有找到结果时搜索失败",
+	},
+	"search.flagOCI": {
+		English: "also search the tags of this OCI chart reference, e.g. oci://registry.local/charts/pulsar (can specify multiple)",
+		Chinese: "同时搜索该 OCI chart 引用的标签，例如 oci://registry.local/charts/pulsar（可多次指定）",
+	},
+	"search.flagChartDir": {
+		English: "also search the charts and chart archives in this directory (can specify multiple)",
+		Chinese: "同时搜索该目录中的 chart 和 chart 压缩包（可多次指定）",
+	},
+	"search.flagPlainHTTP": {
+		English: "use insecure HTTP connections for OCI registries",
+		Chinese: "对 OCI 镜像仓库使用不安全的 HTTP 连接",
+	},
+	"search.flagKeyword": {
+		English: "only show charts with this keyword (can specify multiple)",
+		Chinese: "只显示带有该关键字的 chart（可多次指定）",
+	},
+	"search.flagAnnotation": {
+		English: "only show charts with this annotation, given as KEY or KEY=VALUE (can specify multiple)",
+		Chinese: "只显示带有该注解的 chart，格式为 KEY 或 KEY=VALUE（可多次指定）",
+	},
+	"search.flagKubeVersion": {
+		English: "only show charts compatible with this Kubernetes version",
+		Chinese: "只显示与该 Kubernetes 版本兼容的 chart",
+	},
+	"search.flagAppVersion": {
+		English: "only show charts whose app version matches this semantic versioning constraint",
+		Chinese: "只显示应用版本满足该语义化版本约束的 chart",
+	},
+	"search.badVersion": {
+		English: "an invalid version/constraint format",
+		Chinese: "无效的版本或约束格式",
+	},
+	"search.badAppVersion": {
+		English: "an invalid app version constraint format",
+		Chinese: "无效的应用版本约束格式",
+	},
+	"search.noRepositories": {
+		English: "no repositories configured",
+		Chinese: "没有配置任何仓库",
+	},
+	"search.notOCI": {
+		English: "%q is not an oci:// chart reference",
+		Chinese: "%q 不是 oci:// chart 引用",
+	},
+	"search.noResults": {
+		English: "no results found",
+		Chinese: "没有找到结果",
+	},
+	"search.write": {
+		English: "unable to write results",
+		Chinese: "无法写入结果",
+	},
+	"search.fetch": {
+		English: "unable to fetch %s:%s",
+		Chinese: "无法获取 %s:%s",
+	},
+	"search.badManifest": {
+		English: "invalid manifest for %s:%s",
+		Chinese: "%s:%s 的清单无效",
+	},
+	"search.fetchMetadata": {
+		English: "unable to fetch the chart metadata of %s:%s",
+		Chinese: "无法获取 %s:%s 的 chart 元数据",
+	},
+	"search.badMetadata": {
+		English: "invalid chart metadata for %s:%s",
+		Chinese: "%s:%s 的 chart 元数据无效",
+	},
+
+	"search.none": {
+		English: "No results found",
+		Chinese: "没有找到结果",
+	},
+
+	// repo serve.
+	"repoServe.short": {
+		English: "serve local chart archives as a chart repository and OCI registry",
+		Chinese: "将本地 chart 压缩包作为 chart 仓库和 OCI 镜像仓库提供服务",
+	},
+	"repoServe.long": {
+		English: `
+Serve the chart archives in a local directory as a chart repository.
+
+The repository index is built from the '*.tgz' files in DIR and its direct
+subdirectories, and rebuilt whenever an archive is added, changed or removed.
+The archives are served next to 'index.yaml', so the server can be added with
+'repo add', which writes it to the repositories file, and used by 'install',
+'dependency update' and 'search repo' without network access:
+
+    $ gce repo serve ./testdata/charts --address 127.0.0.1:8879
+    $ gce repo add local http://127.0.0.1:8879
+    $ gce repo update local
+
+The same charts are available from a read-only OCI registry endpoint. Every
+repository path ending in the chart name works, and chart versions are tags:
+
+    $ gce install pulsar oci://127.0.0.1:8879/charts/pulsar --version 3.6.0 --plain-http
+
+With --cert-file and --key-file the server uses TLS. With --ca-file clients
+must present a certificate signed by that CA.
+`,
+		Chinese: `
+将本地目录中的 chart 压缩包作为 chart 仓库提供服务。
+
+仓库索引根据 DIR 及其直接子目录中的 '*.tgz' 文件生成，并在压缩包被添加、修改或
+删除时重新生成。压缩包与 'index.yaml' 一起提供，因此无需网络即可通过
+'repo add' 将该服务写入仓库文件，并用于 'install'、'dependency update' 和
+'search repo'：
+
+    $ gce repo serve ./testdata/charts --address 127.0.0.1:8879
+    $ gce repo add local http://127.0.0.1:8879
+    $ gce repo update local
+
+同样的 chart 也可以通过只读的 OCI 镜像仓库端点获取。任何以 chart 名称结尾的仓库
+路径都可以使用，chart 版本即为标签：
+
+    $ gce install pulsar oci://127.0.0.1:8879/charts/pulsar --version 3.6.0 --plain-http
+
+指定 --cert-file 和 --key-file 时服务使用 TLS。指定 --ca-file 时客户端必须提供
+由该 CA 签发的证书。
+`,
+	},
+	"repoServe.flagAddress": {
+		English: "address to listen on",
+		Chinese: "监听的地址",
+	},
+	"repoServe.flagURL": {
+		English: "URL of the chart archives written to index.yaml (default: the listen address)",
+		Chinese: "写入 index.yaml 的 chart 压缩包 URL（默认为监听地址）",
+	},
+	"repoServe.flagCertFile": {
+		English: "serve HTTPS using this SSL certificate file",
+		Chinese: "使用该 SSL 证书文件提供 HTTPS 服务",
+	},
+	"repoServe.flagKeyFile": {
+		English: "serve HTTPS using this SSL key file",
+		Chinese: "使用该 SSL 密钥文件提供 HTTPS 服务",
+	},
+	"repoServe.flagCAFile": {
+		English: "require client certificates signed by this CA bundle",
+		Chinese: "要求客户端证书由该 CA 证书包签发",
+	},
+	"repoServe.notDir": {
+		English: "%s is not a directory",
+		Chinese: "%s 不是目录",
+	},
+	"repoServe.certKey": {
+		English: "both --cert-file and --key-file are required for TLS",
+		Chinese: "使用 TLS 时必须同时指定 --cert-file 和 --key-file",
+	},
+	"repoServe.caFile": {
+		English: "--ca-file requires --cert-file and --key-file",
+		Chinese: "--ca-file 需要同时指定 --cert-file 和 --key-file",
+	},
+	"repoServe.tlsConfig": {
+		English: "can't create TLS config for server",
+		Chinese: "无法为服务创建 TLS 配置",
+	},
+	"repoServe.serving": {
+		English: "Serving charts from %s",
+		Chinese: "正在提供 %s 中的 chart",
+	},
+	"repoServe.repository": {
+		English: "Chart repository: %s",
+		Chinese: "Chart 仓库：%s",
+	},
+	"repoServe.registry": {
+		English: "OCI registry:     oci://%s/charts",
+		Chinese: "OCI 镜像仓库：  oci://%s/charts",
+	},
+	"repoServe.index": {
+		English: "unable to index %s",
+		Chinese: "无法为 %s 生成索引",
+	},
+	"repoServe.artifact": {
+		English: "unable to create OCI artifact for %s",
+		Chinese: "无法为 %s 创建 OCI 制品",
+	},
+
+	// history.
+	"history.short": {
+		English: "fetch release history",
+		Chinese: "获取 release 的历史",
+	},
+	"history.long": {
+		English: `
+History prints historical revisions for a given release.
+
+A default maximum of 256 revisions will be returned. Setting '--max'
+configures the maximum length of the revision list returned.
+
+The historical release set is printed as a formatted table, e.g:
+
+    $ helm history angry-bird
+    REVISION    UPDATED                     STATUS          CHART             APP VERSION     DESCRIPTION
+    1           Mon Oct 3 10:15:13 2016     superseded      alpine-0.1.0      1.0             Initial install
+    2           Mon Oct 3 10:15:13 2016     superseded      alpine-0.1.0      1.0             Upgraded successfully
+    3           Mon Oct 3 10:15:13 2016     superseded      alpine-0.1.0      1.0             Rolled back to 2
+    4           Mon Oct 3 10:15:13 2016     deployed        alpine-0.1.0      1.0             Upgraded successfully
+
+Use '--wide' to also print how long each revision took to deploy, who or what
+deployed it and the result of the external test run. The deployer is taken from
+$HELM_DEPLOYED_BY, then from the CI job name (GitLab, GitHub Actions, Jenkins,
+Azure Pipelines) and finally from the current OS user.
+
+Use '--show-values-diff' to print what changed in the user-supplied values
+between each revision and the one before it:
+
+    $ helm history angry-bird --show-values-diff
+    ...
+    REVISION 4:
+      ~ broker.replicaCount: 2 -> 3
+      + proxy.service.type: LoadBalancer
+      - zookeeper.resources
+
+Use 'history prune' to delete old revisions from the release storage.
+`,
+		Chinese: `
+打印指定 release 的历史版本。
+
+默认最多返回 256 个版本。设置 '--max' 可以修改返回的版本列表的最大长度。
+
+历史版本以表格形式打印，例如：
+
+    $ helm history angry-bird
+    REVISION    UPDATED                     STATUS          CHART             APP VERSION     DESCRIPTION
+    1           Mon Oct 3 10:15:13 2016     superseded      alpine-0.1.0      1.0             Initial install
+    2           Mon Oct 3 10:15:13 2016     superseded      alpine-0.1.0      1.0             Upgraded successfully
+    3           Mon Oct 3 10:15:13 2016     superseded      alpine-0.1.0      1.0             Rolled back to 2
+    4           Mon Oct 3 10:15:13 2016     deployed        alpine-0.1.0      1.0             Upgraded successfully
+
+使用 '--wide' 还会打印每个版本的部署耗时、部署者以及外部测试的结果。部署者依次
+取自 $HELM_DEPLOYED_BY、CI 任务名称（GitLab、GitHub Actions、Jenkins、
+Azure Pipelines）和当前操作系统用户。
+
+使用 '--show-values-diff' 打印每个版本相对上一个版本在用户提供的 values 上的变化：
+
+    $ helm history angry-bird --show-values-diff
+    ...
+    REVISION 4:
+      ~ broker.replicaCount: 2 -> 3
+      + proxy.service.type: LoadBalancer
+      - zookeeper.resources
+
+使用 'history prune' 从 release 存储中删除旧版本。
+`,
+	},
+	"history.flagWide": {
+		English: "also show deploy duration, deployer and external test result of each revision",
+		Chinese: "同时显示每个版本的部署耗时、部署者和外部测试结果",
+	},
+	"history.flagShowValuesDiff": {
+		English: "show the changes in user-supplied values between consecutive revisions",
+		Chinese: "显示相邻版本之间用户提供的 values 的变化",
+	},
+
+	// list.
+	"list.short": {
+		English: "list releases",
+		Chinese: "列出 release",
+	},
+	"list.long": {
+		English: `
+This command lists all of the releases for a specified namespace (uses current namespace context if namespace not specified).
+
+Use '--contexts ctx1,ctx2' or '--all-contexts' to list releases in several
+clusters at once. Each context is queried concurrently and a CONTEXT column is
+added to the output. Unless '--namespace' is given, each context uses its own
+default namespace. A context that cannot be reached is reported as a warning
+and does not stop the listing.
+
+Use '--watch' to keep the list open and re-render it whenever a release
+changes. On a terminal the table is redrawn in place; when the output is not a
+terminal, or '-o json' is used, one JSON line is written per change.
+
+By default, it lists only releases that are deployed or failed. Flags like
+'--uninstalled' and '--all' will alter this behavior. Such flags can be combined:
+'--uninstalled --failed'.
+
+By default, items are sorted alphabetically. Use the '-d' flag to sort by
+release date.
+
+If the --filter flag is provided, it will be treated as a filter. Filters are
+regular expressions (Perl compatible) that are applied to the list of releases.
+Only items that match the filter will be returned.
+
+    $ helm list --filter 'ara[a-z]+'
+    NAME                UPDATED                                  CHART
+    maudlin-arachnid    2020-06-18 14:17:46.125134977 +0000 UTC  alpine-0.1.0
+
+If no results are found, 'helm list' will exit 0, but with no output (or in
+the case of no '-q' flag, only headers).
+
+By default, up to 256 items may be returned. To limit this, use the '--max' flag.
+Setting '--max' to 0 will not return all results. Rather, it will return the
+server's default, which may be much higher than 256. Pairing the '--max'
+flag with the '--offset' flag allows you to page through results.
+`,
+		Chinese: `
+列出指定命名空间中的所有 release（未指定命名空间时使用当前上下文的命名空间）。
+
+使用 '--contexts ctx1,ctx2' 或 '--all-contexts' 可以同时列出多个集群中的
+release。各个上下文并发查询，输出中会增加 CONTEXT 列。除非指定了 '--namespace'，
+每个上下文使用自己的默认命名空间。无法访问的上下文会以警告报告，不会中断列表。
+
+使用 '--watch' 保持列表打开，并在任何 release 变化时重新渲染。在终端上表格会
+原地重绘；输出不是终端或使用 '-o json' 时，每次变化写入一行 JSON。
+
+默认只列出已部署或失败的 release。'--uninstalled' 和 '--all' 等选项会改变这一
+行为，这些选项可以组合使用：'--uninstalled --failed'。
+
+默认按字母顺序排序。使用 '-d' 按 release 日期排序。
+
+指定 --filter 时，其值作为过滤条件。过滤条件是应用于 release 列表的正则表达式
+（兼容 Perl），只返回匹配的条目。
+
+    $ helm list --filter 'ara[a-z]+'
+    NAME                UPDATED                                  CHART
+    maudlin-arachnid    2020-06-18 14:17:46.125134977 +0000 UTC  alpine-0.1.0
+
+没有找到结果时，'helm list' 以 0 退出，但没有输出（未指定 '-q' 时只输出表头）。
+
+默认最多返回 256 条。使用 '--max' 限制数量。将 '--max' 设为 0 不会返回所有结果，
+而是返回服务端的默认数量，可能远大于 256。'--max' 与 '--offset' 配合使用可以
+分页浏览结果。
+`,
+	},
+	"list.flagContexts": {
+		English: "list releases in each of the given kubeconfig contexts (comma separated) and add a CONTEXT column",
+		Chinese: "列出给定的每个 kubeconfig 上下文（以逗号分隔）中的 release，并增加 CONTEXT 列",
+	},
+	"list.flagAllContexts": {
+		English: "list releases in every kubeconfig context and add a CONTEXT column",
+		Chinese: "列出每个 kubeconfig 上下文中的 release，并增加 CONTEXT 列",
+	},
+	"list.flagWatch": {
+		English: "keep watching the release storage and re-render the list when it changes",
+		Chinese: "持续监视 release 存储，并在变化时重新渲染列表",
+	},
+	"list.watchContexts": {
+		English: "--watch cannot be combined with --contexts or --all-contexts",
+		Chinese: "--watch 不能与 --contexts 或 --all-contexts 同时使用",
+	},
+	"list.loadContexts": {
+		English: "unable to load kubeconfig contexts",
+		Chinese: "无法加载 kubeconfig 上下文",
+	},
+	"list.contextFailed": {
+		English: "WARNING: unable to list releases in context %q: %s",
+		Chinese: "警告：无法列出上下文 %q 中的 release：%s",
+	},
+	"list.allContextsFailed": {
+		English: "unable to list releases in any of the requested contexts",
+		Chinese: "无法列出所请求的任何上下文中的 release",
+	},
+
+	// status.
+	"status.short": {
+		English: "display the status of the named release",
+		Chinese: "显示指定 release 的状态",
+	},
+	"status.long": {
+		English: `
+This command shows the status of a named release.
+The status consists of:
+- last deployment time
+- k8s namespace in which the release lives
+- state of the release (can be: unknown, deployed, uninstalled, superseded, failed, uninstalling, pending-install, pending-upgrade or pending-rollback)
+- revision of the release
+- description of the release (can be completion message or error message)
+- list of resources that this release consists of
+- details on last test suite run, if applicable
+- additional notes provided by the chart
+
+Use '--watch' to keep following the release and its pods while a deployment or
+scale operation is in progress. On a terminal the status is redrawn in place;
+when the output is not a terminal, or '-o json' is used, one JSON line is
+written per change.
+`,
+		Chinese: `
+显示指定 release 的状态。
+状态包括：
+- 最后一次部署的时间
+- release 所在的 k8s 命名空间
+- release 的状态（可能为：unknown、deployed、uninstalled、superseded、failed、uninstalling、pending-install、pending-upgrade 或 pending-rollback）
+- release 的版本
+- release 的描述（完成消息或错误消息）
+- release 包含的资源列表
+- 最近一次测试的详情（如果有）
+- chart 提供的附加说明
+
+使用 '--watch' 在部署或扩缩容进行期间持续跟踪 release 及其 Pod。在终端上状态会
+原地重绘；输出不是终端或使用 '-o json' 时，每次变化写入一行 JSON。
+`,
+	},
+	"status.flagWatch": {
+		English: "keep watching the release and its pods and re-render the status when they change",
+		Chinese: "持续监视 release 及其 Pod，并在变化时重新渲染状态",
+	},
+
+	// watch.
+	"watch.flagInterval": {
+		English: "polling interval used by --watch for storage drivers that cannot be watched (memory, sql)",
+		Chinese: "--watch 对无法监视的存储驱动（memory、sql）使用的轮询间隔",
+	},
+	"watch.renderFailed": {
+		English: "WARNING: %s",
+		Chinese: "警告：%s",
+	},
+
+	// template.
+	"template.short": {
+		English: "locally render templates",
+		Chinese: "在本地渲染模板",
+	},
+	"template.long": {
+		English: `
+Render chart templates locally and display the output.
+
+Any values that would normally be looked up or retrieved in-cluster will be
+faked locally. Additionally, none of the server-side testing of chart validity
+(e.g. whether an API is supported) is done.
+
+With '--output-dir' the manifests are written to files instead of stdout.
+'--output-layout' chooses how they are split:
+
+    template    one file per chart template (default)
+    kind        one file per Kubernetes kind, e.g. statefulset.yaml
+    component   one file per Pulsar component, taken from the 'component' label
+    object      one file per object, in a directory per component
+
+An index.json listing every object with its group, version, kind, name, source
+template and a SHA-256 of its manifest is written next to the files, so the
+output can be reviewed and diffed between chart versions.
+
+'--policy DIR' checks the rendered manifests against the policies in DIR,
+without talking to the cluster. Every *.yaml file in DIR holds a list of rules:
+
+    rules:
+      - name: no-latest-images
+        rule: disallowLatestTag
+      - name: resources
+        rule: requireResources          # params: resources, requests, limits
+      - name: no-privileged
+        rule: disallowPrivileged
+      - name: statefulset-pdb
+        rule: requirePodDisruptionBudget
+      - name: storage-classes
+        rule: allowedStorageClasses     # params: classes, allowDefault
+        severity: warning
+        exclude: ["StatefulSet/*-toolset"]
+        params:
+          classes: [local-path]
+
+The report is written to stderr as a table, JSON or SARIF ('--policy-output')
+and the command fails when a rule with severity 'error' is violated. install
+and upgrade accept the same flags and check the chart before deploying it.
+`,
+		Chinese: `
+在本地渲染 chart 模板并显示输出。
+
+通常需要在集群中查找或获取的值都会在本地伪造。此外，不会执行任何服务端的 chart
+有效性检查（例如某个 API 是否受支持）。
+
+使用 '--output-dir' 时清单写入文件而不是标准输出。'--output-layout' 选择文件的
+拆分方式：
+
+    template    每个 chart 模板一个文件（默认）
+    kind        每种 Kubernetes 类型一个文件，例如 statefulset.yaml
+    component   每个 Pulsar 组件一个文件，取自 'component' 标签
+    object      每个对象一个文件，每个组件一个目录
+
+文件旁边还会写入 index.json，列出每个对象的 group、version、kind、名称、来源模板
+及其清单的 SHA-256，便于审查输出并在 chart 版本之间比较。
+
+'--policy DIR' 在不访问集群的情况下，根据 DIR 中的策略检查渲染出的清单。DIR 中
+的每个 *.yaml 文件包含一个规则列表：
+
+    rules:
+      - name: no-latest-images
+        rule: disallowLatestTag
+      - name: resources
+        rule: requireResources          # params: resources, requests, limits
+      - name: no-privileged
+        rule: disallowPrivileged
+      - name: statefulset-pdb
+        rule: requirePodDisruptionBudget
+      - name: storage-classes
+        rule: allowedStorageClasses     # params: classes, allowDefault
+        severity: warning
+        exclude: ["StatefulSet/*-toolset"]
+        params:
+          classes: [local-path]
+
+报告以表格、JSON 或 SARIF（'--policy-output'）格式写入标准错误输出，违反严重级别
+为 'error' 的规则时命令失败。install 和 upgrade 接受相同的选项，并在部署前检查
+chart。
+`,
+	},
+	"template.flagOutputLayout": {
+		English: "how to split files in output-dir. Allowed values: %s",
+		Chinese: "output-dir 中文件的拆分方式。可选值：%s",
+	},
+	"template.badLayout": {
+		English: "invalid output layout %q, must be one of: %s",
+		Chinese: "无效的输出布局 %q，必须为以下之一：%s",
+	},
+	"template.parseManifest": {
+		English: "unable to parse manifest rendered from %s",
+		Chinese: "无法解析由 %s 渲染出的清单",
+	},
+	"template.wrote": {
+		English: "wrote %s",
+		Chinese: "已写入 %s",
+	},
+}
@@ -0,0 +1,84 @@
+// Package i18n translates the messages of the CLI. Messages are looked up by
+// key in a catalog of English and Chinese texts.
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+)
+
+// Lang is a language of the catalog.
+type Lang string
+
+// Languages of the catalog.
+const (
+	English Lang = "en"
+	Chinese Lang = "zh"
+)
+
+// Languages lists the languages of the catalog.
+var Languages = []Lang{English, Chinese}
+
+var current atomic.Value
+
+func init() {
+	current.Store(English)
+}
+
+// Detect returns the language of the locale environment variables LC_ALL,
+// LC_MESSAGES and LANG, in that order. It falls back to English.
+func Detect() Lang {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		v := os.Getenv(name)
+		if v == "" {
+			continue
+		}
+		if l, ok := Parse(v); ok {
+			return l
+		}
+		return English
+	}
+	return English
+}
+
+// Parse returns the language of a locale such as zh, zh_CN.UTF-8 or en-US.
+func Parse(locale string) (Lang, bool) {
+	tag := strings.ToLower(locale)
+	if i := strings.IndexAny(tag, "_-.@"); i >= 0 {
+		tag = tag[:i]
+	}
+	for _, l := range Languages {
+		if Lang(tag) == l {
+			return l, true
+		}
+	}
+	return "", false
+}
+
+// SetLanguage selects the language of T.
+func SetLanguage(l Lang) {
+	current.Store(l)
+}
+
+// Language returns the selected language.
+func Language() Lang {
+	return current.Load().(Lang)
+}
+
+// T returns the message of key in the selected language, formatted with args.
+// Messages missing in a language fall back to English, and unknown keys are
+// returned as they are.
+func T(key string, args ...interface{}) string {
+	msg, ok := catalog[key][Language()]
+	if !ok {
+		if msg, ok = catalog[key][English]; !ok {
+			msg = key
+		}
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
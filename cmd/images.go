@@ -0,0 +1,287 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"helm.sh/helm/v4/pkg/cli"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/gosuri/uitable"
+	"github.com/huangxiaofeng10047/go-cli-example/cmd/i18n"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+
+	"helm.sh/helm/v4/cmd/helm/require"
+	"helm.sh/helm/v4/pkg/action"
+	chart "helm.sh/helm/v4/pkg/chart/v2"
+	"helm.sh/helm/v4/pkg/chart/v2/loader"
+	"helm.sh/helm/v4/pkg/cli/output"
+	"helm.sh/helm/v4/pkg/cli/values"
+	"helm.sh/helm/v4/pkg/getter"
+	"helm.sh/helm/v4/pkg/postrender"
+	"helm.sh/helm/v4/pkg/storage/driver"
+)
+
+// imageUse is a container image and the objects that use it.
+type imageUse struct {
+	Image  string   `json:"image"`
+	UsedBy []string `json:"usedBy"`
+}
+
+func newImagesCmd(settings *cli.EnvSettings, cfg *action.Configuration, out io.Writer, debug action.DebugLog) *cobra.Command {
+	valueOpts := &values.Options{}
+	chartOpts := &action.ChartPathOptions{}
+	var outfmt output.Format
+
+	cmd := &cobra.Command{
+		Use:   "images RELEASE|CHART",
+		Short: i18n.T("images.short"),
+		Long:  i18n.T("images.long"),
+		Args:  require.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			manifests, err := releaseOrChartManifests(settings, cfg, chartOpts, valueOpts, args[0], offlineRender{}, debug)
+			if err != nil {
+				return err
+			}
+			objs, err := parseRenderedManifests(manifests)
+			if err != nil {
+				return err
+			}
+			return outfmt.Write(out, &imageListWriter{collectImages(objs)})
+		},
+	}
+
+	f := cmd.Flags()
+	addValueOptionsFlags(f, valueOpts)
+	addChartPathOptionsFlags(f, chartOpts)
+	bindOutputFlag(cmd, &outfmt)
+
+	cmd.AddCommand(newImagesBundleCmd(settings, out, debug))
+	return cmd
+}
+
+// releaseOrChartManifests returns the manifests of the named release, or
+// renders the chart at ref when it is a local path or no such release exists.
+// The release name of the rendered chart defaults to the chart name.
+func releaseOrChartManifests(settings *cli.EnvSettings, cfg *action.Configuration, chartOpts *action.ChartPathOptions, valueOpts *values.Options, ref string, render offlineRender, debug action.DebugLog) ([]string, error) {
+	if _, err := os.Stat(ref); err != nil && !strings.Contains(ref, "/") {
+		rel, err := action.NewGet(cfg).Run(ref)
+		if err == nil {
+			return releaseManifests(rel), nil
+		}
+		if !errors.Is(err, driver.ErrReleaseNotFound) {
+			debug("unable to get release %s, treating it as a chart: %s", ref, err)
+		}
+	}
+
+	ch, vals, err := loadChartWithValues(settings, chartOpts, valueOpts, ref, debug)
+	if err != nil {
+		return nil, err
+	}
+	if render.releaseName == "" {
+		render.releaseName = ch.Name()
+	}
+	render.namespace = settings.Namespace()
+	return renderChartOffline(ch, vals, render, debug)
+}
+
+// loadChartWithValues locates and loads a chart and merges the user values,
+// including those of the active profile.
+func loadChartWithValues(settings *cli.EnvSettings, chartOpts *action.ChartPathOptions, valueOpts *values.Options, ref string, debug action.DebugLog) (*chart.Chart, map[string]interface{}, error) {
+	cp, err := chartOpts.LocateChart(ref, settings)
+	if err != nil {
+		return nil, nil, err
+	}
+	ch, err := loader.Load(cp)
+	if err != nil {
+		return nil, nil, err
+	}
+	if req := ch.Metadata.Dependencies; req != nil {
+		if err := action.CheckDependencies(ch, req); err != nil {
+			return nil, nil, errors.Wrap(err, i18n.T("images.dependencies"))
+		}
+	}
+
+	applyProfileValues(valueOpts, debug)
+	vals, err := valueOpts.MergeValues(getter.All(settings))
+	if err != nil {
+		return nil, nil, err
+	}
+	return ch, vals, nil
+}
+
+// collectImages returns the images used by objs, sorted by name. Any string
+// field called 'image' is taken as an image reference.
+func collectImages(objs []*renderedObject) []imageUse {
+	users := map[string][]string{}
+	for _, o := range objs {
+		var u map[string]interface{}
+		if err := yaml.Unmarshal([]byte(o.body), &u); err != nil {
+			continue
+		}
+		ref := o.Kind + "/" + o.Metadata.Name
+		walkImageFields(u, func(image string) string {
+			image = normalizeImage(image)
+			if len(users[image]) == 0 || users[image][len(users[image])-1] != ref {
+				users[image] = append(users[image], ref)
+			}
+			return ""
+		})
+	}
+
+	images := make([]imageUse, 0, len(users))
+	for image, by := range users {
+		images = append(images, imageUse{Image: image, UsedBy: by})
+	}
+	sort.Slice(images, func(i, j int) bool { return images[i].Image < images[j].Image })
+	return images
+}
+
+// walkImageFields calls fn for every non-empty string field called 'image'.
+// When fn returns a non-empty string, the field is replaced by it.
+func walkImageFields(v interface{}, fn func(string) string) {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, val := range t {
+			if s, ok := val.(string); ok && k == "image" && s != "" {
+				if r := fn(s); r != "" {
+					t[k] = r
+				}
+				continue
+			}
+			walkImageFields(val, fn)
+		}
+	case []interface{}:
+		for _, val := range t {
+			walkImageFields(val, fn)
+		}
+	}
+}
+
+// dockerHub is the registry of image references without a registry host.
+const dockerHub = "docker.io"
+
+// normalizeImage returns the fully qualified form of a Docker image
+// reference: 'busybox' becomes 'docker.io/library/busybox:latest'.
+func normalizeImage(image string) string {
+	registry, repository, ref := splitImage(image)
+	if strings.HasPrefix(ref, "sha256:") {
+		return registry + "/" + repository + "@" + ref
+	}
+	return registry + "/" + repository + ":" + ref
+}
+
+// splitImage splits an image reference into registry, repository and tag or
+// digest, filling in the Docker defaults.
+func splitImage(image string) (registry, repository, ref string) {
+	name := image
+	if i := strings.Index(name, "@"); i >= 0 {
+		name, ref = name[:i], name[i+1:]
+	}
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		if ref == "" {
+			ref = name[i+1:]
+		}
+		name = name[:i]
+	}
+	if ref == "" {
+		ref = "latest"
+	}
+
+	registry = dockerHub
+	if i := strings.Index(name, "/"); i >= 0 {
+		host := name[:i]
+		if strings.ContainsAny(host, ".:") || host == "localhost" {
+			registry, name = host, name[i+1:]
+		}
+	}
+	if registry == dockerHub && !strings.Contains(name, "/") {
+		name = "library/" + name
+	}
+	return registry, name, ref
+}
+
+// retargetImage moves image under the registry prefix, keeping its
+// repository path and tag: with prefix 'registry.local/mirror',
+// 'docker.io/apachepulsar/pulsar:3.0' becomes
+// 'registry.local/mirror/apachepulsar/pulsar:3.0'.
+func retargetImage(image, prefix string) string {
+	_, repository, ref := splitImage(image)
+	sep := ":"
+	if strings.HasPrefix(ref, "sha256:") {
+		sep = "@"
+	}
+	return strings.TrimSuffix(prefix, "/") + "/" + repository + sep + ref
+}
+
+// imageRetargeter is a post-renderer that replaces image references of
+// rendered manifests, chained after any post-renderer given by the user.
+type imageRetargeter struct {
+	images map[string]string
+	next   postrender.PostRenderer
+}
+
+func (r *imageRetargeter) Run(renderedManifests *bytes.Buffer) (*bytes.Buffer, error) {
+	if r.next != nil {
+		var err error
+		if renderedManifests, err = r.next.Run(renderedManifests); err != nil {
+			return nil, err
+		}
+	}
+
+	res := new(bytes.Buffer)
+	for _, doc := range sortedManifests(renderedManifests.String()) {
+		header, body := "", doc
+		if strings.HasPrefix(doc, "# Source: ") {
+			line, rest, _ := strings.Cut(doc, "\n")
+			header, body = line+"\n", rest
+		}
+		var u map[string]interface{}
+		if err := yaml.Unmarshal([]byte(body), &u); err != nil {
+			return nil, errors.Wrap(err, i18n.T("images.parseManifest"))
+		}
+		changed := false
+		walkImageFields(u, func(image string) string {
+			target := r.images[normalizeImage(image)]
+			if target != "" {
+				changed = true
+			}
+			return target
+		})
+		if changed {
+			b, err := yaml.Marshal(u)
+			if err != nil {
+				return nil, err
+			}
+			body = string(b)
+		}
+		fmt.Fprintf(res, "---\n%s%s\n", header, strings.TrimSpace(body))
+	}
+	return res, nil
+}
+
+type imageListWriter struct {
+	images []imageUse
+}
+
+func (w *imageListWriter) WriteTable(out io.Writer) error {
+	table := uitable.New()
+	table.MaxColWidth = 120
+	table.AddRow("IMAGE", "USED BY")
+	for _, i := range w.images {
+		table.AddRow(i.Image, strings.Join(i.UsedBy, ", "))
+	}
+	return output.EncodeTable(out, table)
+}
+
+func (w *imageListWriter) WriteJSON(out io.Writer) error {
+	return output.EncodeJSON(out, w.images)
+}
+
+func (w *imageListWriter) WriteYAML(out io.Writer) error {
+	return output.EncodeYAML(out, w.images)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	installevent "github.com/huangxiaofeng10047/go-cli-example/cmd/event"
+	"github.com/huangxiaofeng10047/go-cli-example/cmd/i18n"
 	"helm.sh/helm/v4/pkg/cli"
 	"io"
 	"log"
@@ -89,6 +90,16 @@ The --dry-run flag will output all generated chart manifests, including Secrets
 which can contain sensitive values. To hide Kubernetes Secrets use the
 --hide-secret flag. Please carefully consider how and when these flags are used.
 
+Values, namespace, kube context and the test service can also be taken from an
+environment profile with '--profile'. Profiles are read from ./profiles.yaml
+(or '--profile-file') and map an environment name to an ordered list of value
+files and '--set' overrides. Values given on the command line take precedence
+over the profile:
+
+    $ helm install --profile pulsar-test pulsar-test ./charts/pulsar
+
+The resolved values stack is printed with '--debug'.
+
 If --verify is set, the chart MUST have a provenance file, and the provenance
 file MUST pass all verification steps.
 
@@ -119,12 +130,7 @@ func newInstallCmd(settings *cli.EnvSettings, cfg *action.Configuration, out io.
 	client := action.NewInstall(cfg)
 	valueOpts := &values.Options{}
 	var outfmt output.Format
-	// 添加测试配置
-	//event := GetGlobalEvent()
-	//if event == nil {
-	//	fmt.Fprintln(out, "event is not initialized...")
-	//	return nil
-	//}
+	var skipChecks bool
 	cmd := &cobra.Command{
 		Use:   "install [NAME] [CHART]",
 		Short: "install a target version ",
@@ -133,8 +139,14 @@ func newInstallCmd(settings *cli.EnvSettings, cfg *action.Configuration, out io.
 		ValidArgsFunction: func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 			return compInstall(settings, args, toComplete, client)
 		},
-		RunE: func(_ *cobra.Command, args []string) error {
-			event := installevent.NewInstallEvent(testConfig)
+		RunE: func(_ *cobra.Command, args []string) (err error) {
+			prog := newProgress(out, outfmt)
+			defer func() { prog.Finish(err) }()
+			reportKubeProgress(cfg, prog)
+			event, err := newInstallEvent(settings, cfg, args[0], prog, debug)
+			if err != nil {
+				return err
+			}
 			ctx := context.Background()
 			registryClient, err := newRegistryClient(settings, client.CertFile, client.KeyFile, client.CaFile,
 				client.InsecureSkipTLSverify, client.PlainHTTP, client.Username, client.Password)
@@ -149,11 +161,26 @@ func newInstallCmd(settings *cli.EnvSettings, cfg *action.Configuration, out io.
 			if client.DryRunOption == "" {
 				client.DryRunOption = "none"
 			}
+			started := time.Now()
+			prog.Phase(i18n.T("progress.render"))
 			rel, err := runInstall(settings, args, client, valueOpts, out, debug)
 			if err != nil {
 				return errors.Wrap(err, "INSTALLATION FAILED")
 			}
-			err = outfmt.Write(out, &statusPrinter{
+			prog.Stop()
+			recordDeploy := !isDryRunOption(client.DryRunOption)
+			if recordDeploy {
+				if err := recordDeployInfo(cfg, rel, started); err != nil {
+					debug("unable to record deploy info: %s", err)
+				}
+			}
+			report := newDeployReport(out, outfmt)
+			defer func() {
+				if ferr := report.flush(); err == nil {
+					err = ferr
+				}
+			}()
+			err = report.writeStatus(&statusPrinter{
 				release:      rel,
 				debug:        settings.Debug,
 				showMetadata: false,
@@ -162,21 +189,51 @@ func newInstallCmd(settings *cli.EnvSettings, cfg *action.Configuration, out io.
 			if err != nil {
 				return errors.Wrap(err, "INSTALLATION FAILED")
 			}
+			sp := startSpan(phaseTest, "release", rel.Name)
 			err = event.QueryRunningPod(settings, ctx, cfg, out)
 			if err != nil {
+				sp.finish(err)
 				return err
 			}
+			var failedChecks bool
+			if !skipChecks {
+				checks, err := runPostDeployChecks(ctx, settings, cfg, event, rel, prog, debug)
+				if err != nil {
+					sp.finish(err)
+					return err
+				}
+				if err := report.writeChecks(checks); err != nil {
+					sp.finish(err)
+					return err
+				}
+				failedChecks = checksFailed(checks)
+			}
 			taskID, err2 := event.FinishInstall(settings, cfg, args[0])
 			if err2 != nil {
-				return errors.Wrap(err, "INSTALLATION FAILED")
+				sp.finish(err2)
+				return errors.Wrap(err2, "INSTALLATION FAILED")
 			}
-			fmt.Fprintln(out, "Waiting for testcase finish...")
 
-			err = event.WaitTestCaseFinish(settings, ctx, out, taskID)
-			if err != nil {
-				return err
+			tests, err := event.WaitTestCaseFinish(settings, ctx, taskID)
+			sp.finish(err)
+			result := testResultError
+			if err == nil {
+				result = tests.Result
+				err = report.writeTests(tests)
+			}
+			if err == nil {
+				err = tests.Err()
 			}
-			return nil
+			if err == nil && failedChecks {
+				result = installevent.TestResultFailed
+				err = errors.New(i18n.T("plugin.checksFailed"))
+			}
+			if recordDeploy {
+				if rerr := recordTestResult(cfg, rel, result); rerr != nil {
+					debug("unable to record test result: %s", rerr)
+				}
+			}
+			return err
 		},
 	}
 
@@ -185,6 +242,7 @@ func newInstallCmd(settings *cli.EnvSettings, cfg *action.Configuration, out io.
 	// it is added separately
 	f := cmd.Flags()
 	f.BoolVar(&client.HideSecret, "hide-secret", false, "hide Kubernetes Secrets when also using the --dry-run flag")
+	f.BoolVar(&skipChecks, "no-checks", false, i18n.T("plugin.flagNoChecks"))
 	bindOutputFlag(cmd, &outfmt)
 	bindPostRenderFlag(cmd, &client.PostRenderer)
 
@@ -221,6 +279,9 @@ func addInstallFlags(settings *cli.EnvSettings, cmd *cobra.Command, f *pflag.Fla
 	f.BoolVar(&client.HideNotes, "hide-notes", false, "if set, do not show notes in install output. Does not affect presence in chart metadata")
 	f.BoolVar(&client.TakeOwnership, "take-ownership", false, "if set, install will ignore the check for helm annotations and take ownership of the existing resources")
 	addValueOptionsFlags(f, valueOpts)
+	addStrictValuesFlag(f)
+	addPolicyFlags(f)
+	addBundleInstallFlags(f)
 	addChartPathOptionsFlags(f, &client.ChartPathOptions)
 
 	err := cmd.RegisterFlagCompletionFunc("version", func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -245,19 +306,31 @@ func runInstall(settings *cli.EnvSettings, args []string, client *action.Install
 		client.Version = ">0.0.0-0"
 	}
 
+	if fromBundle != "" {
+		chartPath, cleanup, err := prepareBundleInstall(settings, client, out)
+		if err != nil {
+			return nil, err
+		}
+		defer cleanup()
+		args = append(args, chartPath)
+	}
+
 	name, chart, err := client.NameAndChart(args)
 	if err != nil {
 		return nil, err
 	}
 	client.ReleaseName = name
 
+	sp := startSpan(phaseChartLocate, "chart", chart)
 	cp, err := client.ChartPathOptions.LocateChart(chart, settings)
+	sp.finish(err)
 	if err != nil {
 		return nil, err
 	}
 
 	debug("CHART PATH: %s\n", cp)
 
+	applyProfileValues(valueOpts, debug)
 	p := getter.All(settings)
 	vals, err := valueOpts.MergeValues(p)
 	if err != nil {
@@ -274,11 +347,17 @@ func runInstall(settings *cli.EnvSettings, args []string, client *action.Install
 		return nil, err
 	}
 
+	if err := checkStrictValues(chartRequested, valueOpts, p, debug); err != nil {
+		return nil, err
+	}
+
 	if chartRequested.Metadata.Deprecated {
 		debug("This chart is deprecated")
 	}
 
 	if req := chartRequested.Metadata.Dependencies; req != nil {
+		sp := startSpan(phaseDependencyCheck)
+		defer sp.finish(nil)
 		// If CheckDependencies returns an error, we have unfulfilled dependencies.
 		// As of Helm 2.4.0, this is treated as a stopping condition:
 		// https://github.com/helm/helm/issues/2209
@@ -307,6 +386,7 @@ func runInstall(settings *cli.EnvSettings, args []string, client *action.Install
 				return nil, err
 			}
 		}
+		sp.finish(nil)
 	}
 
 	client.Namespace = settings.Namespace()
@@ -316,6 +396,13 @@ func runInstall(settings *cli.EnvSettings, args []string, client *action.Install
 		return nil, err
 	}
 
+	// 'template' renders client side and checks its own output.
+	if !client.ClientOnly {
+		if err := checkChartPolicy(chartRequested, vals, client.ReleaseName, client.Namespace, client.IsUpgrade, client.PostRenderer, debug); err != nil {
+			return nil, err
+		}
+	}
+
 	// Create context and prepare the handle of SIGTERM
 	ctx := context.Background()
 	ctx, cancel := context.WithCancel(ctx)
@@ -327,11 +414,16 @@ func runInstall(settings *cli.EnvSettings, args []string, client *action.Install
 	signal.Notify(cSignal, os.Interrupt, syscall.SIGTERM)
 	go func() {
 		<-cSignal
-		fmt.Fprintf(out, "Release %s has been cancelled.\n", args[0])
+		fmt.Fprint(out, i18n.T("release.cancelled", args[0]))
 		cancel()
 	}()
 
-	return client.RunWithContext(ctx, chartRequested, vals)
+	// The render span is ended by the kube client at the first change to the
+	// cluster, or here if rendering fails.
+	render := startSpan(phaseRender, "release", client.ReleaseName)
+	rel, err := client.RunWithContext(ctx, chartRequested, vals)
+	render.finish(err)
+	return rel, err
 }
 
 // checkIfInstallable validates if a chart can be installed
@@ -0,0 +1,56 @@
+package cmd
+
+import "testing"
+
+func TestInstallCmd(t *testing.T) {
+	tests := []cmdTestCase{
+		{
+			name:     "install and run the test cases",
+			cmd:      "install pt testdata/testcharts/mini",
+			scenario: "scenario-install.yaml",
+			golden:   "install.golden",
+		},
+		{
+			name:     "install with json output",
+			cmd:      "install pt testdata/testcharts/mini -o json",
+			scenario: "scenario-install.yaml",
+			golden:   "install-json.golden",
+		},
+		{
+			name:      "install with failed test cases",
+			cmd:       "install pt testdata/testcharts/mini",
+			scenario:  "scenario-test-failed.yaml",
+			golden:    "install-test-failed.golden",
+			wantError: true,
+		},
+		{
+			name:      "install without the admin token",
+			cmd:       "install pt testdata/testcharts/mini",
+			scenario:  "scenario-no-token.yaml",
+			golden:    "install-no-token.golden",
+			wantError: true,
+		},
+		{
+			name:      "install with the test service down",
+			cmd:       "install pt testdata/testcharts/mini",
+			scenario:  "scenario-test-service-down.yaml",
+			golden:    "install-test-service-down.golden",
+			wantError: true,
+		},
+		{
+			name:     "install with post-deploy checks",
+			cmd:      "install pt testdata/testcharts/mini -o json",
+			scenario: "scenario-install.yaml",
+			plugins:  "testdata/plugins",
+			golden:   "install-checks-json.golden",
+		},
+		{
+			name:     "install without post-deploy checks",
+			cmd:      "install pt testdata/testcharts/mini --no-checks",
+			scenario: "scenario-install.yaml",
+			plugins:  "testdata/plugins",
+			golden:   "install-no-checks.golden",
+		},
+	}
+	runTestCmd(t, tests)
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"sort"
+
+	"helm.sh/helm/v4/pkg/cli"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// loadKubeConfig loads the raw kubeconfig honouring --kubeconfig and $KUBECONFIG.
+func loadKubeConfig(settings *cli.EnvSettings) (clientcmdapi.Config, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	if len(settings.KubeConfig) > 0 {
+		loadingRules = &clientcmd.ClientConfigLoadingRules{ExplicitPath: settings.KubeConfig}
+	}
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		loadingRules,
+		&clientcmd.ConfigOverrides{}).RawConfig()
+}
+
+// kubeContextNames returns the sorted names of all contexts in the kubeconfig.
+func kubeContextNames(settings *cli.EnvSettings) ([]string, error) {
+	config, err := loadKubeConfig(settings)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(config.Contexts))
+	for name := range config.Contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// settingsForContext returns a copy of settings that talks to the given
+// kubeconfig context. Connection overrides that only make sense for a single
+// cluster (API server, token, CA file, TLS server name and verification) are
+// not carried over, neither from settings nor from the HELM_KUBE* environment
+// variables that cli.New reads again. The namespace is
+// only carried over when it was set explicitly, otherwise the default
+// namespace of the context is used.
+func settingsForContext(settings *cli.EnvSettings, kubeContext string, keepNamespace bool) *cli.EnvSettings {
+	s := cli.New()
+	s.KubeAPIServer = ""
+	s.KubeToken = ""
+	s.KubeCaFile = ""
+	s.KubeTLSServerName = ""
+	s.KubeInsecureSkipTLSVerify = false
+	s.KubeConfig = settings.KubeConfig
+	s.KubeContext = kubeContext
+	s.KubeAsUser = settings.KubeAsUser
+	s.KubeAsGroups = settings.KubeAsGroups
+	s.Debug = settings.Debug
+	s.RegistryConfig = settings.RegistryConfig
+	s.RepositoryConfig = settings.RepositoryConfig
+	s.RepositoryCache = settings.RepositoryCache
+	s.PluginsDirectory = settings.PluginsDirectory
+	s.MaxHistory = settings.MaxHistory
+	s.BurstLimit = settings.BurstLimit
+	s.QPS = settings.QPS
+	if keepNamespace {
+		s.SetNamespace(settings.Namespace())
+	}
+	return s
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"helm.sh/helm/v4/pkg/cli"
+)
+
+const twoClustersKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: east
+  cluster: {server: "https://east.example.com"}
+- name: west
+  cluster: {server: "https://west.example.com"}
+users:
+- name: admin
+  user: {token: kubeconfig-token}
+contexts:
+- name: east
+  context: {cluster: east, user: admin, namespace: pulsar}
+- name: west
+  context: {cluster: west, user: admin}
+current-context: east
+`
+
+func TestSettingsForContext(t *testing.T) {
+	isolateEnv(t, "")
+	kubeconfig := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(kubeconfig, []byte(twoClustersKubeconfig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("KUBECONFIG", kubeconfig)
+	t.Setenv("HELM_KUBEAPISERVER", "https://east.example.com")
+	t.Setenv("HELM_KUBETOKEN", "env-token")
+	t.Setenv("HELM_KUBECAFILE", "/etc/east-ca.crt")
+	t.Setenv("HELM_KUBETLS_SERVER_NAME", "east")
+	t.Setenv("HELM_KUBEINSECURE_SKIP_TLS_VERIFY", "true")
+
+	tests := []struct {
+		context       string
+		keepNamespace bool
+		wantHost      string
+		wantNamespace string
+	}{
+		{"east", false, "https://east.example.com", "pulsar"},
+		{"west", false, "https://west.example.com", "default"},
+		{"west", true, "https://west.example.com", "staging"},
+	}
+	for _, tt := range tests {
+		settings := cli.New()
+		settings.SetNamespace("staging")
+		s := settingsForContext(settings, tt.context, tt.keepNamespace)
+
+		conf, err := s.RESTClientGetter().ToRESTConfig()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if conf.Host != tt.wantHost {
+			t.Errorf("%s: expected host %s, got %s", tt.context, tt.wantHost, conf.Host)
+		}
+		if conf.BearerToken != "kubeconfig-token" {
+			t.Errorf("%s: expected the token of the kubeconfig, got %q", tt.context, conf.BearerToken)
+		}
+		if conf.CAFile != "" || conf.ServerName != "" || conf.Insecure {
+			t.Errorf("%s: expected no TLS overrides, got CA file %q, server name %q, insecure %t", tt.context, conf.CAFile, conf.ServerName, conf.Insecure)
+		}
+		if got := s.Namespace(); got != tt.wantNamespace {
+			t.Errorf("%s: expected namespace %s, got %s", tt.context, tt.wantNamespace, got)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/huangxiaofeng10047/go-cli-example/cmd/i18n"
+	"helm.sh/helm/v4/pkg/cli"
+	"io"
+	"os"
+
+	"github.com/gosuri/uitable"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"helm.sh/helm/v4/cmd/helm/require"
+	"helm.sh/helm/v4/pkg/action"
+	chart "helm.sh/helm/v4/pkg/chart/v2"
+	"helm.sh/helm/v4/pkg/chart/v2/loader"
+	"helm.sh/helm/v4/pkg/cli/output"
+	"helm.sh/helm/v4/pkg/cli/values"
+	"helm.sh/helm/v4/pkg/getter"
+)
+
+// strictValues is set by --strict-values on install, upgrade and template.
+var strictValues bool
+
+func newLintCmd(settings *cli.EnvSettings, out io.Writer) *cobra.Command {
+	valueOpts := &values.Options{}
+	var outfmt output.Format
+	var strict bool
+
+	cmd := &cobra.Command{
+		Use:   "lint CHART",
+		Short: i18n.T("lint.short"),
+		Long:  i18n.T("lint.long"),
+		Args:  require.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			ch, err := loader.Load(args[0])
+			if err != nil {
+				return err
+			}
+			applyProfileValues(valueOpts, func(string, ...interface{}) {})
+			findings, err := lintValues(ch, valueOpts, getter.All(settings))
+			if err != nil {
+				return err
+			}
+			if err := outfmt.Write(out, &valuesFindingWriter{findings}); err != nil {
+				return err
+			}
+
+			errs, warnings := countFindings(findings)
+			if errs > 0 || (strict && warnings > 0) {
+				return errors.New(i18n.T("lint.failed", ch.Name(), errs, warnings))
+			}
+			return nil
+		},
+	}
+
+	f := cmd.Flags()
+	f.BoolVar(&strict, "strict", false, i18n.T("lint.flagStrict"))
+	addValueOptionsFlags(f, valueOpts)
+	bindOutputFlag(cmd, &outfmt)
+
+	return cmd
+}
+
+// addStrictValuesFlag adds --strict-values to a command that deploys a chart.
+func addStrictValuesFlag(f *pflag.FlagSet) {
+	f.BoolVar(&strictValues, "strict-values", false, i18n.T("lint.flagStrictValues"))
+}
+
+// checkStrictValues runs the values lint when --strict-values is set. Findings
+// are printed to stderr so they don't mix with structured output.
+func checkStrictValues(ch *chart.Chart, valueOpts *values.Options, p getter.Providers, debug action.DebugLog) error {
+	if !strictValues {
+		return nil
+	}
+	findings, err := lintValues(ch, valueOpts, p)
+	if err != nil {
+		return err
+	}
+	errs, warnings := countFindings(findings)
+	debug("strict values: %d error(s), %d warning(s)", errs, warnings)
+	if len(findings) > 0 {
+		if err := output.Table.Write(os.Stderr, &valuesFindingWriter{findings}); err != nil {
+			return err
+		}
+	}
+	if errs > 0 {
+		return errors.New(i18n.T("lint.strictFailed", errs))
+	}
+	return nil
+}
+
+type valuesFindingWriter struct {
+	findings []valuesFinding
+}
+
+func (w *valuesFindingWriter) WriteTable(out io.Writer) error {
+	if len(w.findings) == 0 {
+		_, err := fmt.Fprintln(out, i18n.T("lint.none"))
+		return err
+	}
+	table := uitable.New()
+	table.AddRow("SEVERITY", "POSITION", "KEY", "MESSAGE")
+	for _, f := range w.findings {
+		table.AddRow(f.Severity, f.position(), f.Key, f.Message)
+	}
+	return output.EncodeTable(out, table)
+}
+
+func (w *valuesFindingWriter) WriteJSON(out io.Writer) error {
+	return output.EncodeJSON(out, w.findings)
+}
+
+func (w *valuesFindingWriter) WriteYAML(out io.Writer) error {
+	return output.EncodeYAML(out, w.findings)
+}
@@ -1,13 +1,20 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"helm.sh/helm/v4/pkg/cli"
 	"io"
+	"log"
 	"os"
+	"os/signal"
 	"strconv"
+	"syscall"
+	"time"
 
 	"github.com/gosuri/uitable"
+	"github.com/huangxiaofeng10047/go-cli-example/cmd/i18n"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
 	"helm.sh/helm/v4/cmd/helm/require"
@@ -16,45 +23,48 @@ import (
 	"helm.sh/helm/v4/pkg/release"
 )
 
-var listHelp = `
-This command lists all of the releases for a specified namespace (uses current namespace context if namespace not specified).
-
-By default, it lists only releases that are deployed or failed. Flags like
-'--uninstalled' and '--all' will alter this behavior. Such flags can be combined:
-'--uninstalled --failed'.
-
-By default, items are sorted alphabetically. Use the '-d' flag to sort by
-release date.
-
-If the --filter flag is provided, it will be treated as a filter. Filters are
-regular expressions (Perl compatible) that are applied to the list of releases.
-Only items that match the filter will be returned.
-
-    $ helm list --filter 'ara[a-z]+'
-    NAME                UPDATED                                  CHART
-    maudlin-arachnid    2020-06-18 14:17:46.125134977 +0000 UTC  alpine-0.1.0
-
-If no results are found, 'helm list' will exit 0, but with no output (or in
-the case of no '-q' flag, only headers).
-
-By default, up to 256 items may be returned. To limit this, use the '--max' flag.
-Setting '--max' to 0 will not return all results. Rather, it will return the
-server's default, which may be much higher than 256. Pairing the '--max'
-flag with the '--offset' flag allows you to page through results.
-`
-
 func newListCmd(settings *cli.EnvSettings, cfg *action.Configuration, out io.Writer, debug action.DebugLog) *cobra.Command {
 	client := action.NewList(cfg)
 	var outfmt output.Format
+	var contexts []string
+	var allContexts bool
+	var watch bool
+	var watchInterval time.Duration
 
 	cmd := &cobra.Command{
 		Use:               "list",
-		Short:             "list releases",
-		Long:              listHelp,
+		Short:             i18n.T("list.short"),
+		Long:              i18n.T("list.long"),
 		Aliases:           []string{"ls"},
 		Args:              require.NoArgs,
 		ValidArgsFunction: noMoreArgsCompFunc,
 		RunE: func(cmd *cobra.Command, _ []string) error {
+			if allContexts || len(contexts) > 0 {
+				if watch {
+					return errors.New(i18n.T("list.watchContexts"))
+				}
+				if allContexts {
+					names, err := kubeContextNames(settings)
+					if err != nil {
+						return errors.Wrap(err, i18n.T("list.loadContexts"))
+					}
+					contexts = names
+				}
+				keepNamespace := cmd.Flags().Changed("namespace") || os.Getenv("HELM_NAMESPACE") != ""
+				results := listAcrossContexts(settings, client, contexts, keepNamespace, debug)
+				w, err := mergeContextResults(results, client.TimeFormat, client.NoHeaders)
+				if err != nil {
+					return err
+				}
+				if client.Short && outfmt == output.Table {
+					for _, e := range w.releases {
+						fmt.Fprintf(out, "%s/%s\n", e.Context, e.Name)
+					}
+					return nil
+				}
+				return outfmt.Write(out, w)
+			}
+
 			if client.AllNamespaces {
 				if err := cfg.Init(settings.RESTClientGetter(), "", os.Getenv("HELM_DRIVER"), debug); err != nil {
 					return err
@@ -62,6 +72,23 @@ func newListCmd(settings *cli.EnvSettings, cfg *action.Configuration, out io.Wri
 			}
 			client.SetStateMask()
 
+			if watch {
+				ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+				defer stop()
+				namespace := settings.Namespace()
+				if client.AllNamespaces {
+					namespace = ""
+				}
+				w := newWatchRenderer(out, outfmt)
+				return watchChanges(ctx, cfg, watchOptions{namespace: namespace, interval: watchInterval}, func() error {
+					results, err := client.Run()
+					if err != nil {
+						return err
+					}
+					return w.render(newReleaseListWriter(results, client.TimeFormat, client.NoHeaders))
+				})
+			}
+
 			results, err := client.Run()
 			if err != nil {
 				return err
@@ -108,16 +135,32 @@ func newListCmd(settings *cli.EnvSettings, cfg *action.Configuration, out io.Wri
 	f.BoolVar(&client.Failed, "failed", false, "show failed releases")
 	f.BoolVar(&client.Pending, "pending", false, "show pending releases")
 	f.BoolVarP(&client.AllNamespaces, "all-namespaces", "A", false, "list releases across all namespaces")
+	f.StringSliceVar(&contexts, "contexts", nil, i18n.T("list.flagContexts"))
+	f.BoolVar(&allContexts, "all-contexts", false, i18n.T("list.flagAllContexts"))
+	f.BoolVarP(&watch, "watch", "w", false, i18n.T("list.flagWatch"))
+	f.DurationVar(&watchInterval, "watch-interval", 2*time.Second, i18n.T("watch.flagInterval"))
 	f.IntVarP(&client.Limit, "max", "m", 256, "maximum number of releases to fetch")
 	f.IntVar(&client.Offset, "offset", 0, "next release index in the list, used to offset from start value")
 	f.StringVarP(&client.Filter, "filter", "f", "", "a regular expression (Perl compatible). Any releases that match the expression will be included in the results")
 	f.StringVarP(&client.Selector, "selector", "l", "", "Selector (label query) to filter on, supports '=', '==', and '!='.(e.g. -l key1=value1,key2=value2). Works only for secret(default) and configmap storage backends.")
 	bindOutputFlag(cmd, &outfmt)
 
+	err := cmd.RegisterFlagCompletionFunc("contexts", func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		names, err := kubeContextNames(settings)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return names, cobra.ShellCompDirectiveNoFileComp
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	return cmd
 }
 
 type releaseElement struct {
+	Context    string `json:"context,omitempty"`
 	Name       string `json:"name"`
 	Namespace  string `json:"namespace"`
 	Revision   string `json:"revision"`
@@ -128,8 +171,9 @@ type releaseElement struct {
 }
 
 type releaseListWriter struct {
-	releases  []releaseElement
-	noHeaders bool
+	releases    []releaseElement
+	noHeaders   bool
+	showContext bool
 }
 
 func newReleaseListWriter(releases []*release.Release, timeFormat string, noHeaders bool) *releaseListWriter {
@@ -157,16 +201,24 @@ func newReleaseListWriter(releases []*release.Release, timeFormat string, noHead
 
 		elements = append(elements, element)
 	}
-	return &releaseListWriter{elements, noHeaders}
+	return &releaseListWriter{releases: elements, noHeaders: noHeaders}
 }
 
 func (r *releaseListWriter) WriteTable(out io.Writer) error {
 	table := uitable.New()
 	if !r.noHeaders {
-		table.AddRow("NAME", "NAMESPACE", "REVISION", "UPDATED", "STATUS", "CHART", "APP VERSION")
+		if r.showContext {
+			table.AddRow("CONTEXT", "NAME", "NAMESPACE", "REVISION", "UPDATED", "STATUS", "CHART", "APP VERSION")
+		} else {
+			table.AddRow("NAME", "NAMESPACE", "REVISION", "UPDATED", "STATUS", "CHART", "APP VERSION")
+		}
 	}
-	for _, r := range r.releases {
-		table.AddRow(r.Name, r.Namespace, r.Revision, r.Updated, r.Status, r.Chart, r.AppVersion)
+	for _, e := range r.releases {
+		if r.showContext {
+			table.AddRow(e.Context, e.Name, e.Namespace, e.Revision, e.Updated, e.Status, e.Chart, e.AppVersion)
+			continue
+		}
+		table.AddRow(e.Name, e.Namespace, e.Revision, e.Updated, e.Status, e.Chart, e.AppVersion)
 	}
 	return output.EncodeTable(out, table)
 }
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/huangxiaofeng10047/go-cli-example/cmd/i18n"
+	"github.com/pkg/errors"
+
+	"helm.sh/helm/v4/pkg/action"
+	"helm.sh/helm/v4/pkg/cli"
+	"helm.sh/helm/v4/pkg/release"
+)
+
+// contextListResult holds the releases found in a single kubeconfig context.
+type contextListResult struct {
+	context  string
+	releases []*release.Release
+	err      error
+}
+
+// listAcrossContexts runs the list action against every given kubeconfig
+// context concurrently. The results are returned in the order of contexts.
+// A failure in one context is recorded in its result and does not stop the
+// others.
+func listAcrossContexts(settings *cli.EnvSettings, client *action.List, contexts []string, keepNamespace bool, debug action.DebugLog) []contextListResult {
+	results := make([]contextListResult, len(contexts))
+
+	var wg sync.WaitGroup
+	for i, kubeContext := range contexts {
+		wg.Add(1)
+		go func(i int, kubeContext string) {
+			defer wg.Done()
+			results[i] = contextListResult{context: kubeContext}
+
+			s := settingsForContext(settings, kubeContext, keepNamespace)
+			namespace := s.Namespace()
+			if client.AllNamespaces {
+				namespace = ""
+			}
+			cfg := new(action.Configuration)
+			if err := cfg.Init(s.RESTClientGetter(), namespace, os.Getenv("HELM_DRIVER"), debug); err != nil {
+				results[i].err = err
+				return
+			}
+
+			l := action.NewList(cfg)
+			copyListOptions(l, client)
+			l.SetStateMask()
+			results[i].releases, results[i].err = l.Run()
+		}(i, kubeContext)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// copyListOptions copies the user facing options of src into dst.
+func copyListOptions(dst, src *action.List) {
+	dst.All = src.All
+	dst.AllNamespaces = src.AllNamespaces
+	dst.ByDate = src.ByDate
+	dst.SortReverse = src.SortReverse
+	dst.Limit = src.Limit
+	dst.Offset = src.Offset
+	dst.Filter = src.Filter
+	dst.Short = src.Short
+	dst.NoHeaders = src.NoHeaders
+	dst.TimeFormat = src.TimeFormat
+	dst.Uninstalled = src.Uninstalled
+	dst.Superseded = src.Superseded
+	dst.Uninstalling = src.Uninstalling
+	dst.Deployed = src.Deployed
+	dst.Failed = src.Failed
+	dst.Pending = src.Pending
+	dst.Selector = src.Selector
+}
+
+// mergeContextResults builds a single list writer out of per-context results.
+// Errors are reported on stderr; an error is only returned when no context
+// could be listed at all.
+func mergeContextResults(results []contextListResult, timeFormat string, noHeaders bool) (*releaseListWriter, error) {
+	merged := &releaseListWriter{releases: []releaseElement{}, noHeaders: noHeaders, showContext: true}
+	failed := 0
+	for _, res := range results {
+		if res.err != nil {
+			failed++
+			fmt.Fprintln(os.Stderr, i18n.T("list.contextFailed", res.context, res.err))
+			continue
+		}
+		w := newReleaseListWriter(res.releases, timeFormat, noHeaders)
+		for _, e := range w.releases {
+			e.Context = res.context
+			merged.releases = append(merged.releases, e)
+		}
+	}
+	if len(results) > 0 && failed == len(results) {
+		return nil, errors.New(i18n.T("list.allContextsFailed"))
+	}
+	return merged, nil
+}
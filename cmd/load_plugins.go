@@ -21,6 +21,7 @@ import (
 	"helm.sh/helm/v4/pkg/cli"
 	"io"
 	"log"
+	"log/slog"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -97,7 +98,16 @@ func loadPlugins(settings *cli.EnvSettings, baseCmd *cobra.Command, out io.Write
 			DisableFlagParsing: true,
 		}
 
-		// TODO: Make sure a command with this name does not already exist.
+		// A plugin must not shadow a built-in command, nor a plugin loaded before.
+		if existing := builtinCommand(baseCmd, md.Name); existing != nil {
+			slog.Warn("plugin conflicts with a built-in command and is not loaded", "plugin", md.Name, "command", existing.Name(), "dir", plug.Dir)
+			continue
+		}
+		if existing, _, err := baseCmd.Find([]string{md.Name}); err == nil && existing != baseCmd {
+			slog.Warn("plugin has the name of another plugin and is not loaded", "plugin", md.Name, "dir", plug.Dir, "loaded", existing.Annotations[pluginAnnotation])
+			continue
+		}
+		c.Annotations = map[string]string{pluginAnnotation: plug.Dir}
 		baseCmd.AddCommand(c)
 
 		// For completion, we try to load more details about the plugins so as to allow for command and
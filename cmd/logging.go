@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/huangxiaofeng10047/go-cli-example/cmd/i18n"
+	"github.com/spf13/pflag"
+	"k8s.io/klog/v2"
+)
+
+// Values of --log-format.
+const (
+	logFormatText = "text"
+	logFormatJSON = "json"
+)
+
+var logLevel, logFormat string
+
+// redacted replaces secrets in log records.
+const redacted = "[REDACTED]"
+
+var (
+	// sensitiveKey matches attribute names whose values are secrets.
+	sensitiveKey = regexp.MustCompile(`(?i)(token|passw(or)?d|secret|authorization|credential|api[-_]?key|private[-_]?key|cookie)`)
+	// authHeader matches the credentials of bearer and basic auth headers.
+	authHeader = regexp.MustCompile(`(?i)\b(bearer|basic)\s+[A-Za-z0-9._~+/=-]+`)
+	// sensitiveField matches key=value pairs and JSON or YAML fields with a
+	// sensitive name.
+	sensitiveField = regexp.MustCompile(`(?i)("?[\w-]*(?:token|passw(?:or)?d|secret|api[-_]?key)[\w-]*"?\s*[:=]\s*)("[^"]*"|[^\s,}&]+)`)
+	// jwt matches JSON web tokens.
+	jwt = regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`)
+)
+
+func addLoggingFlags(f *pflag.FlagSet) {
+	f.StringVar(&logLevel, "log-level", "info", "")
+	f.StringVar(&logFormat, "log-format", logFormatText, "")
+}
+
+// setupLogging makes a logger writing to w the default logger of slog, the log
+// package and klog. Secrets are redacted from every record. debug lowers the
+// level to debug unless --log-level is set.
+func setupLogging(w io.Writer, f *pflag.FlagSet, debug bool) error {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(logLevel)); err != nil {
+		return fmt.Errorf("%s: %w", i18n.T("error.badLogLevel", logLevel), err)
+	}
+	if debug && !f.Changed("log-level") {
+		level = slog.LevelDebug
+	}
+	// klog -v N messages are logged at level -N.
+	if v := f.Lookup("v"); v != nil {
+		if n, err := strconv.Atoi(v.Value.String()); err == nil && n > 0 && slog.Level(-n) < level {
+			level = slog.Level(-n)
+		}
+	}
+
+	opts := &slog.HandlerOptions{Level: level, ReplaceAttr: redactAttr}
+	var handler slog.Handler
+	switch logFormat {
+	case logFormatText:
+		handler = slog.NewTextHandler(w, opts)
+	case logFormatJSON:
+		handler = slog.NewJSONHandler(w, opts)
+	default:
+		return fmt.Errorf("%s", i18n.T("error.badLogFormat", logFormat, logFormatText, logFormatJSON))
+	}
+
+	logger := slog.New(handler)
+	slog.SetDefault(logger)
+	klog.SetSlogLogger(logger)
+	return nil
+}
+
+func redactAttr(_ []string, a slog.Attr) slog.Attr {
+	if a.Key != slog.MessageKey && sensitiveKey.MatchString(a.Key) {
+		return slog.String(a.Key, redacted)
+	}
+	switch a.Value.Kind() {
+	case slog.KindString:
+		return slog.String(a.Key, redact(a.Value.String()))
+	case slog.KindAny:
+		if err, ok := a.Value.Any().(error); ok {
+			return slog.String(a.Key, redact(err.Error()))
+		}
+		if s, ok := a.Value.Any().(fmt.Stringer); ok {
+			return slog.String(a.Key, redact(s.String()))
+		}
+	}
+	return a
+}
+
+// redact removes secrets from s, including the data of Secret manifests.
+func redact(s string) string {
+	if strings.Contains(s, "kind: Secret") {
+		s = redactSecretManifests(s)
+	}
+	s = authHeader.ReplaceAllString(s, "${1} "+redacted)
+	s = sensitiveField.ReplaceAllString(s, "${1}"+redacted)
+	return jwt.ReplaceAllString(s, redacted)
+}
+
+// redactSecretManifests replaces the values under data and stringData of the
+// Secrets in a YAML stream.
+func redactSecretManifests(s string) string {
+	docs := strings.Split(s, "\n---")
+	for i, doc := range docs {
+		if !strings.Contains(doc, "kind: Secret") {
+			continue
+		}
+		lines := strings.Split(doc, "\n")
+		indent := -1
+		for j, line := range lines {
+			trimmed := strings.TrimLeft(line, " ")
+			depth := len(line) - len(trimmed)
+			switch {
+			case trimmed == "data:" || trimmed == "stringData:":
+				indent = depth
+			case indent >= 0 && trimmed != "" && depth > indent:
+				if key, _, ok := strings.Cut(trimmed, ":"); ok {
+					lines[j] = line[:depth] + key + ": " + redacted
+				}
+			case trimmed != "":
+				indent = -1
+			}
+		}
+		docs[i] = strings.Join(lines, "\n")
+	}
+	return strings.Join(docs, "\n---")
+}
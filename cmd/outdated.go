@@ -0,0 +1,318 @@
+package cmd
+
+import (
+	"fmt"
+	"helm.sh/helm/v4/pkg/cli"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/gosuri/uitable"
+	"github.com/huangxiaofeng10047/go-cli-example/cmd/i18n"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+
+	"helm.sh/helm/v4/pkg/action"
+	"helm.sh/helm/v4/pkg/cli/output"
+	"helm.sh/helm/v4/pkg/helmpath"
+	"helm.sh/helm/v4/pkg/registry"
+	"helm.sh/helm/v4/pkg/repo"
+)
+
+// Semver distances between the installed and the latest chart version.
+const (
+	distanceMajor    = "major"
+	distanceMinor    = "minor"
+	distancePatch    = "patch"
+	distanceUpToDate = "up-to-date"
+	distanceUnknown  = "unknown"
+)
+
+// outdatedRelease compares a deployed release with the newest chart version.
+type outdatedRelease struct {
+	Name       string   `json:"name"`
+	Namespace  string   `json:"namespace"`
+	Chart      string   `json:"chart"`
+	Installed  string   `json:"installed"`
+	Latest     string   `json:"latest,omitempty"`
+	Distance   string   `json:"distance"`
+	Behind     int      `json:"behind"`
+	Source     string   `json:"source,omitempty"`
+	Deprecated bool     `json:"deprecated"`
+	Changes    []string `json:"changes,omitempty"`
+	Upgrade    string   `json:"upgrade,omitempty"`
+
+	// ref is the chart reference of the latest version.
+	ref string
+}
+
+// chartSource holds the versions of a chart in one repository or registry.
+type chartSource struct {
+	// name is the repository name, or the OCI reference.
+	name  string
+	chart string
+	// ref is the chart reference used to upgrade from this source.
+	ref      string
+	versions repo.ChartVersions
+}
+
+func newOutdatedCmd(settings *cli.EnvSettings, cfg *action.Configuration, out io.Writer, debug action.DebugLog) *cobra.Command {
+	client := action.NewList(cfg)
+	var outfmt output.Format
+	var ociRefs []string
+	var plainHTTP, devel, all bool
+
+	cmd := &cobra.Command{
+		Use:   "outdated [RELEASE...]",
+		Short: i18n.T("outdated.short"),
+		Long:  i18n.T("outdated.long"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if client.AllNamespaces {
+				if err := cfg.Init(settings.RESTClientGetter(), "", os.Getenv("HELM_DRIVER"), debug); err != nil {
+					return err
+				}
+			}
+			client.Deployed = true
+			client.SetStateMask()
+			releases, err := client.Run()
+			if err != nil {
+				return err
+			}
+
+			sources := newChartSources(settings, ociRefs, plainHTTP, debug)
+			res := []outdatedRelease{}
+			for _, rel := range releases {
+				if len(args) > 0 && !slices.Contains(args, rel.Name) {
+					continue
+				}
+				if rel.Chart == nil || rel.Chart.Metadata == nil {
+					continue
+				}
+				o := compareRelease(rel.Name, rel.Namespace, rel.Chart.Metadata.Name, rel.Chart.Metadata.Version, sources.lookup(rel.Chart.Metadata.Name), devel)
+				o.Deprecated = o.Deprecated || rel.Chart.Metadata.Deprecated
+				if o.Latest != "" && o.Distance != distanceUpToDate {
+					o.Upgrade = fmt.Sprintf("%s upgrade %s %s --version %s -n %s", filepath.Base(os.Args[0]), rel.Name, o.ref, o.Latest, rel.Namespace)
+				}
+				if all || o.Upgrade != "" || o.Deprecated {
+					res = append(res, o)
+				}
+			}
+			return outfmt.Write(out, &outdatedWriter{res})
+		},
+	}
+
+	f := cmd.Flags()
+	f.BoolVarP(&client.AllNamespaces, "all-namespaces", "A", false, i18n.T("outdated.flagAllNamespaces"))
+	f.StringVarP(&client.Filter, "filter", "f", "", i18n.T("outdated.flagFilter"))
+	f.StringArrayVar(&ociRefs, "oci", nil, i18n.T("outdated.flagOCI"))
+	f.BoolVar(&plainHTTP, "plain-http", false, i18n.T("outdated.flagPlainHTTP"))
+	f.BoolVar(&devel, "devel", false, i18n.T("outdated.flagDevel"))
+	f.BoolVar(&all, "all", false, i18n.T("outdated.flagAll"))
+	bindOutputFlag(cmd, &outfmt)
+
+	return cmd
+}
+
+// chartSources looks up chart versions in the repository caches and in OCI
+// registries. OCI references are only queried for charts of the same name.
+type chartSources struct {
+	settings  *cli.EnvSettings
+	plainHTTP bool
+	debug     action.DebugLog
+	repos     []chartSource
+	ociRefs   map[string][]string
+	oci       map[string]*chartSource
+}
+
+func newChartSources(settings *cli.EnvSettings, ociRefs []string, plainHTTP bool, debug action.DebugLog) *chartSources {
+	s := &chartSources{
+		settings:  settings,
+		plainHTTP: plainHTTP,
+		debug:     debug,
+		ociRefs:   map[string][]string{},
+		oci:       map[string]*chartSource{},
+	}
+	addOCI := func(ref string) {
+		name := ref[strings.LastIndex(ref, "/")+1:]
+		s.ociRefs[name] = append(s.ociRefs[name], ref)
+	}
+	for _, ref := range ociRefs {
+		addOCI(ref)
+	}
+
+	rf, err := repo.LoadFile(settings.RepositoryConfig)
+	if err != nil && !isNotExist(err) {
+		debug("unable to load %s: %s", settings.RepositoryConfig, err)
+	}
+	for _, re := range rf.Repositories {
+		if registry.IsOCI(re.URL) {
+			addOCI(re.URL)
+			continue
+		}
+		ind, err := repo.LoadIndexFile(filepath.Join(settings.RepositoryCache, helmpath.CacheIndexFile(re.Name)))
+		if err != nil {
+			debug("Repo %q is corrupt or missing. Try 'helm repo update'.", re.Name)
+			continue
+		}
+		for name, versions := range ind.Entries {
+			s.repos = append(s.repos, chartSource{name: re.Name, chart: name, ref: re.Name + "/" + name, versions: versions})
+		}
+	}
+	return s
+}
+
+// lookup returns every source that has versions of the named chart.
+func (s *chartSources) lookup(name string) []*chartSource {
+	var res []*chartSource
+	for i, src := range s.repos {
+		if src.chart == name {
+			res = append(res, &s.repos[i])
+		}
+	}
+	for _, ref := range s.ociRefs[name] {
+		src, ok := s.oci[ref]
+		if !ok {
+			ind, err := ociChartIndex(s.settings, ref, s.plainHTTP)
+			if err != nil {
+				s.debug("Unable to list the tags of %s: %s", ref, err)
+			} else {
+				src = &chartSource{name: ref, chart: name, ref: ref, versions: ind.Entries[name]}
+			}
+			s.oci[ref] = src
+		}
+		if src != nil {
+			res = append(res, src)
+		}
+	}
+	return res
+}
+
+// compareRelease finds the newest version of a chart across sources and
+// compares it with the installed version.
+func compareRelease(name, namespace, chartName, installed string, sources []*chartSource, devel bool) outdatedRelease {
+	o := outdatedRelease{
+		Name:      name,
+		Namespace: namespace,
+		Chart:     chartName,
+		Installed: installed,
+		Distance:  distanceUnknown,
+	}
+	current, err := semver.NewVersion(installed)
+	if err != nil {
+		return o
+	}
+
+	var latest *repo.ChartVersion
+	var latestVersion *semver.Version
+	var from *chartSource
+	for _, src := range sources {
+		for _, cv := range src.versions {
+			v, err := semver.NewVersion(cv.Version)
+			if err != nil || !devel && v.Prerelease() != "" {
+				continue
+			}
+			if latestVersion == nil || v.GreaterThan(latestVersion) {
+				latest, latestVersion, from = cv, v, src
+			}
+		}
+	}
+	if latest == nil {
+		return o
+	}
+
+	o.Latest, o.Source, o.ref = latest.Version, from.name, from.ref
+	o.Deprecated = latest.Deprecated
+	o.Distance = semverDistance(current, latestVersion)
+
+	// The changes of every version between the installed and the latest one,
+	// newest first.
+	for _, cv := range from.versions {
+		v, err := semver.NewVersion(cv.Version)
+		if err != nil || !v.GreaterThan(current) || v.GreaterThan(latestVersion) || !devel && v.Prerelease() != "" {
+			continue
+		}
+		o.Behind++
+		o.Changes = append(o.Changes, chartChanges(cv.Annotations["artifacthub.io/changes"])...)
+	}
+	return o
+}
+
+func semverDistance(installed, latest *semver.Version) string {
+	switch {
+	case !latest.GreaterThan(installed):
+		return distanceUpToDate
+	case latest.Major() != installed.Major():
+		return distanceMajor
+	case latest.Minor() != installed.Minor():
+		return distanceMinor
+	}
+	return distancePatch
+}
+
+// chartChanges parses the 'artifacthub.io/changes' annotation, which is a
+// YAML list of descriptions or of objects with a kind and a description.
+func chartChanges(annotation string) []string {
+	if annotation == "" {
+		return nil
+	}
+	var items []interface{}
+	if err := yaml.Unmarshal([]byte(annotation), &items); err != nil {
+		return []string{strings.TrimSpace(annotation)}
+	}
+	var changes []string
+	for _, item := range items {
+		switch c := item.(type) {
+		case string:
+			changes = append(changes, c)
+		case map[string]interface{}:
+			desc := fmt.Sprint(c["description"])
+			if kind, ok := c["kind"].(string); ok && kind != "" {
+				desc = kind + ": " + desc
+			}
+			changes = append(changes, desc)
+		}
+	}
+	return changes
+}
+
+type outdatedWriter struct {
+	releases []outdatedRelease
+}
+
+func (w *outdatedWriter) WriteTable(out io.Writer) error {
+	if len(w.releases) == 0 {
+		_, err := fmt.Fprintln(out, i18n.T("outdated.none"))
+		return err
+	}
+	table := uitable.New()
+	table.AddRow("NAME", "NAMESPACE", "CHART", "INSTALLED", "LATEST", "DISTANCE", "BEHIND", "SOURCE", "DEPRECATED")
+	for _, r := range w.releases {
+		table.AddRow(r.Name, r.Namespace, r.Chart, r.Installed, orDash(r.Latest), r.Distance, r.Behind, orDash(r.Source), r.Deprecated)
+	}
+	if err := output.EncodeTable(out, table); err != nil {
+		return err
+	}
+
+	for _, r := range w.releases {
+		if r.Upgrade == "" {
+			continue
+		}
+		fmt.Fprintf(out, "\n%s (%s -> %s)\n", r.Name, r.Installed, r.Latest)
+		for _, c := range r.Changes {
+			fmt.Fprintf(out, "  - %s\n", c)
+		}
+		fmt.Fprintf(out, "  %s\n", r.Upgrade)
+	}
+	return nil
+}
+
+func (w *outdatedWriter) WriteJSON(out io.Writer) error {
+	return output.EncodeJSON(out, w.releases)
+}
+
+func (w *outdatedWriter) WriteYAML(out io.Writer) error {
+	return output.EncodeYAML(out, w.releases)
+}
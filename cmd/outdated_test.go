@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/Masterminds/semver/v3"
+
+	chart "helm.sh/helm/v4/pkg/chart/v2"
+	"helm.sh/helm/v4/pkg/repo"
+)
+
+func TestSemverDistance(t *testing.T) {
+	tests := []struct {
+		installed string
+		latest    string
+		want      string
+	}{
+		{"3.3.1", "3.3.1", distanceUpToDate},
+		{"3.3.1", "3.3.0", distanceUpToDate},
+		{"3.3.1", "3.3.2", distancePatch},
+		{"3.3.1", "3.4.0", distanceMinor},
+		{"3.3.1", "4.0.0", distanceMajor},
+		{"3.4.0-rc.1", "3.4.0", distancePatch},
+	}
+	for _, tt := range tests {
+		got := semverDistance(semver.MustParse(tt.installed), semver.MustParse(tt.latest))
+		if got != tt.want {
+			t.Errorf("%s to %s: expected %s, got %s", tt.installed, tt.latest, tt.want, got)
+		}
+	}
+}
+
+func TestCompareRelease(t *testing.T) {
+	chartVersion := func(version, changes string) *repo.ChartVersion {
+		cv := &repo.ChartVersion{Metadata: &chart.Metadata{Name: "pulsar", Version: version}}
+		if changes != "" {
+			cv.Annotations = map[string]string{"artifacthub.io/changes": changes}
+		}
+		return cv
+	}
+	stable := &chartSource{name: "apache", chart: "pulsar", ref: "apache/pulsar", versions: repo.ChartVersions{
+		chartVersion("3.4.0-rc.1", "- release candidate"),
+		chartVersion("3.3.1", "- kind: fixed\n  description: broker probes"),
+		chartVersion("3.3.0", "- TLS for the proxy"),
+		chartVersion("3.2.0", ""),
+	}}
+	mirror := &chartSource{name: "oci://registry.local/charts/pulsar", chart: "pulsar", ref: "oci://registry.local/charts/pulsar", versions: repo.ChartVersions{
+		chartVersion("3.3.2", ""),
+		chartVersion("not-a-version", ""),
+	}}
+
+	tests := []struct {
+		name        string
+		installed   string
+		sources     []*chartSource
+		devel       bool
+		wantLatest  string
+		wantSource  string
+		wantDist    string
+		wantBehind  int
+		wantChanges []string
+	}{
+		{
+			name:        "behind by two versions",
+			installed:   "3.2.0",
+			sources:     []*chartSource{stable},
+			wantLatest:  "3.3.1",
+			wantSource:  "apache",
+			wantDist:    distanceMinor,
+			wantBehind:  2,
+			wantChanges: []string{"fixed: broker probes", "TLS for the proxy"},
+		},
+		{
+			name:       "up to date",
+			installed:  "3.3.1",
+			sources:    []*chartSource{stable},
+			wantLatest: "3.3.1",
+			wantSource: "apache",
+			wantDist:   distanceUpToDate,
+		},
+		{
+			name:        "development versions",
+			installed:   "3.3.1",
+			sources:     []*chartSource{stable},
+			devel:       true,
+			wantLatest:  "3.4.0-rc.1",
+			wantSource:  "apache",
+			wantDist:    distanceMinor,
+			wantBehind:  1,
+			wantChanges: []string{"release candidate"},
+		},
+		{
+			name:       "newest version across sources",
+			installed:  "3.3.0",
+			sources:    []*chartSource{stable, mirror},
+			wantLatest: "3.3.2",
+			wantSource: "oci://registry.local/charts/pulsar",
+			wantDist:   distancePatch,
+			wantBehind: 1,
+		},
+		{
+			name:      "installed version is not semver",
+			installed: "latest",
+			sources:   []*chartSource{stable},
+			wantDist:  distanceUnknown,
+		},
+		{
+			name:      "no source",
+			installed: "3.3.0",
+			wantDist:  distanceUnknown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := compareRelease("pulsar", "pulsar", "pulsar", tt.installed, tt.sources, tt.devel)
+			if o.Latest != tt.wantLatest || o.Source != tt.wantSource || o.Distance != tt.wantDist || o.Behind != tt.wantBehind {
+				t.Errorf("expected latest %q from %q, %s, %d behind, got %q from %q, %s, %d behind",
+					tt.wantLatest, tt.wantSource, tt.wantDist, tt.wantBehind, o.Latest, o.Source, o.Distance, o.Behind)
+			}
+			if !slices.Equal(o.Changes, tt.wantChanges) {
+				t.Errorf("expected changes %q, got %q", tt.wantChanges, o.Changes)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"helm.sh/helm/v4/pkg/cli"
+	"io"
+	"os"
+	"os/exec"
+	"slices"
+
+	"github.com/huangxiaofeng10047/go-cli-example/cmd/i18n"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"helm.sh/helm/v4/cmd/helm/require"
+	"helm.sh/helm/v4/pkg/action"
+	"helm.sh/helm/v4/pkg/plugin"
+)
+
+// pluginAnnotation marks the commands added by loadPlugins. Its value is the
+// directory of the plugin.
+const pluginAnnotation = "plugin.dir"
+
+func newPluginCmd(settings *cli.EnvSettings, out io.Writer, debug action.DebugLog) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "plugin",
+		Short: i18n.T("plugin.short"),
+		Long:  i18n.T("plugin.long"),
+		Args:  require.NoArgs,
+	}
+	cmd.AddCommand(
+		newPluginInstallCmd(settings, out, debug),
+		newPluginListCmd(settings, out, debug),
+		newPluginUninstallCmd(settings, out, debug),
+		newPluginUpdateCmd(settings, out, debug),
+	)
+	return cmd
+}
+
+// runHook will execute a plugin hook.
+func runHook(settings *cli.EnvSettings, p *plugin.Plugin, event string, out io.Writer, debug action.DebugLog) error {
+	plugin.SetupPluginEnv(settings, p.Metadata.Name, p.Dir)
+
+	main, argv, ok := hookCommand(p, event)
+	if !ok {
+		return nil
+	}
+
+	prog := exec.Command(main, argv...)
+
+	debug("running %s hook: %s", event, prog)
+
+	prog.Stdout, prog.Stderr = out, os.Stderr
+	if err := prog.Run(); err != nil {
+		if eerr, ok := err.(*exec.ExitError); ok {
+			os.Stderr.Write(eerr.Stderr)
+			return errors.New(i18n.T("plugin.hookExited", event, p.Metadata.Name))
+		}
+		return err
+	}
+	return nil
+}
+
+// hookCommand returns the command of the hook of a plugin for event on this
+// platform. It reports false if the plugin has no such hook.
+func hookCommand(p *plugin.Plugin, event string) (string, []string, bool) {
+	cmds := p.Metadata.PlatformHooks[event]
+	expandArgs := true
+	if len(cmds) == 0 && len(p.Metadata.Hooks) > 0 {
+		cmd := p.Metadata.Hooks[event]
+		if len(cmd) > 0 {
+			cmds = []plugin.PlatformCommand{{Command: "sh", Args: []string{"-c", cmd}}}
+			expandArgs = false
+		}
+	}
+
+	main, argv, err := plugin.PrepareCommands(cmds, expandArgs, []string{})
+	if err != nil {
+		return "", nil, false
+	}
+	return main, argv, true
+}
+
+// cobraCommands are the commands cobra adds to the root command only when it
+// runs, so they are not yet among its commands when plugins are loaded.
+var cobraCommands = []string{"help", "completion"}
+
+// builtinCommand returns the built-in command of root called name, either by
+// its name or by an alias. Commands added for plugins are not built-in.
+func builtinCommand(root *cobra.Command, name string) *cobra.Command {
+	for _, c := range root.Commands() {
+		if _, ok := c.Annotations[pluginAnnotation]; ok {
+			continue
+		}
+		if c.Name() == name || c.HasAlias(name) {
+			return c
+		}
+	}
+	if slices.Contains(cobraCommands, name) {
+		return &cobra.Command{Use: name}
+	}
+	return nil
+}
+
+// findPlugin returns the plugin called name.
+func findPlugin(plugins []*plugin.Plugin, name string) *plugin.Plugin {
+	for _, p := range plugins {
+		if p.Metadata.Name == name {
+			return p
+		}
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"helm.sh/helm/v4/pkg/cli"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/huangxiaofeng10047/go-cli-example/cmd/i18n"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"helm.sh/helm/v4/cmd/helm/require"
+	"helm.sh/helm/v4/pkg/action"
+	"helm.sh/helm/v4/pkg/plugin"
+	"helm.sh/helm/v4/pkg/plugin/installer"
+)
+
+func newPluginInstallCmd(settings *cli.EnvSettings, out io.Writer, debug action.DebugLog) *cobra.Command {
+	var version string
+	cmd := &cobra.Command{
+		Use:     "install [options] <path|url>",
+		Short:   i18n.T("plugin.installShort"),
+		Long:    i18n.T("plugin.installLong"),
+		Aliases: []string{"add"},
+		Args:    require.ExactArgs(1),
+		ValidArgsFunction: func(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 0 {
+				// We do file completion, in case the plugin is local
+				return nil, cobra.ShellCompDirectiveDefault
+			}
+			// No more completion once the plugin path has been specified
+			return noMoreArgsComp()
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			installer.Debug = settings.Debug
+
+			i, err := newPluginInstaller(settings, args[0], version)
+			if err != nil {
+				return err
+			}
+			if err := installer.Install(i); err != nil {
+				return err
+			}
+
+			debug("loading plugin from %s", i.Path())
+			p, err := plugin.LoadDir(i.Path())
+			if err != nil {
+				return errors.Wrap(err, i18n.T("plugin.unusable"))
+			}
+			if c := builtinCommand(cmd.Root(), p.Metadata.Name); c != nil {
+				if err := os.RemoveAll(i.Path()); err != nil {
+					debug("unable to remove %s: %s", i.Path(), err)
+				}
+				return errors.New(i18n.T("plugin.conflict", p.Metadata.Name, c.Name()))
+			}
+
+			if err := runHook(settings, p, plugin.Install, out, debug); err != nil {
+				return err
+			}
+
+			fmt.Fprint(out, i18n.T("plugin.installed", p.Metadata.Name))
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&version, "version", "", i18n.T("plugin.flagVersion"))
+	return cmd
+}
+
+// newPluginInstaller returns the installer of source. Local archives are
+// extracted; other sources are handled by the installer package.
+func newPluginInstaller(settings *cli.EnvSettings, source, version string) (installer.Installer, error) {
+	if fi, err := os.Stat(source); err == nil && !fi.IsDir() && isPluginArchive(source) {
+		return newArchiveInstaller(settings, source)
+	}
+	return installer.NewForSource(source, version)
+}
+
+func isPluginArchive(source string) bool {
+	for suffix := range installer.Extractors {
+		if strings.HasSuffix(source, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// archiveInstaller installs a plugin from a local archive into a directory
+// named after the plugin.
+type archiveInstaller struct {
+	data      []byte
+	extractor installer.Extractor
+	// root is the directory of the archive that holds plugin.yaml.
+	root string
+	path string
+}
+
+func newArchiveInstaller(settings *cli.EnvSettings, source string) (*archiveInstaller, error) {
+	extractor, err := installer.NewExtractor(source)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(source)
+	if err != nil {
+		return nil, err
+	}
+
+	// The archive is extracted once to read the name of the plugin, which is
+	// where Install moves it.
+	dir, err := os.MkdirTemp("", "gce-plugin-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	if err := extractor.Extract(bytes.NewBuffer(data), dir); err != nil {
+		return nil, errors.Wrap(err, i18n.T("plugin.extract", source))
+	}
+	root, err := pluginRoot(dir)
+	if err != nil {
+		return nil, errors.Wrap(err, i18n.T("plugin.noPlugin", source))
+	}
+	p, err := plugin.LoadDir(filepath.Join(dir, root))
+	if err != nil {
+		return nil, errors.Wrap(err, i18n.T("plugin.noPlugin", source))
+	}
+
+	return &archiveInstaller{
+		data:      data,
+		extractor: extractor,
+		root:      root,
+		path:      filepath.Join(settings.PluginsDirectory, p.Metadata.Name),
+	}, nil
+}
+
+// pluginRoot returns the directory of dir, relative to it, that holds
+// plugin.yaml. Archives often wrap the plugin in a single top-level
+// directory, which is then the root.
+func pluginRoot(dir string) (string, error) {
+	if _, err := os.Stat(filepath.Join(dir, plugin.PluginFileName)); err == nil {
+		return ".", nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+	if len(entries) == 1 && entries[0].IsDir() {
+		if _, err := os.Stat(filepath.Join(dir, entries[0].Name(), plugin.PluginFileName)); err == nil {
+			return entries[0].Name(), nil
+		}
+	}
+	return "", installer.ErrMissingMetadata
+}
+
+// Install extracts the archive next to the plugin directory and moves the
+// root of the plugin into place.
+func (i *archiveInstaller) Install() error {
+	dir, err := os.MkdirTemp(filepath.Dir(i.path), ".install-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	if err := i.extractor.Extract(bytes.NewBuffer(i.data), dir); err != nil {
+		return err
+	}
+	return os.Rename(filepath.Join(dir, i.root), i.path)
+}
+
+func (i *archiveInstaller) Path() string {
+	return i.path
+}
+
+func (i *archiveInstaller) Update() error {
+	return errors.New(i18n.T("plugin.archiveUpdate"))
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"helm.sh/helm/v4/pkg/cli"
+	"helm.sh/helm/v4/pkg/plugin"
+)
+
+func TestPluginInstallArchive(t *testing.T) {
+	tests := []struct {
+		name    string
+		prefix  string
+		wantErr bool
+	}{
+		{name: "plugin.yaml at the top", prefix: ""},
+		{name: "plugin in a top-level directory", prefix: "smoke-0.1.0/"},
+		{name: "plugin nested too deep", prefix: "dist/smoke-0.1.0/", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			isolateEnv(t, "")
+			archive := filepath.Join(t.TempDir(), "smoke.tgz")
+			writePluginArchive(t, archive, tt.prefix, filepath.Join("testdata", "plugins", "smoke"))
+
+			out, err := executeCommand("plugin install " + archive)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %q", out)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(out, "smoke") {
+				t.Errorf("expected the installed plugin in the output, got %q", out)
+			}
+			p, err := plugin.LoadDir(filepath.Join(cli.New().PluginsDirectory, "smoke"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := os.Stat(filepath.Join(p.Dir, "check.sh")); err != nil {
+				t.Error(err)
+			}
+			if entries, _ := os.ReadDir(filepath.Dir(p.Dir)); len(entries) != 1 {
+				t.Errorf("expected only the plugin in the plugins directory, got %d entries", len(entries))
+			}
+		})
+	}
+}
+
+// writePluginArchive writes the files of dir to a gzipped tarball, with their
+// names prefixed by prefix.
+func writePluginArchive(t *testing.T, archive, prefix, dir string) {
+	t.Helper()
+	f, err := os.Create(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+
+	var dirs []string
+	for p := strings.TrimSuffix(prefix, "/"); p != "" && p != "."; p = filepath.Dir(p) {
+		dirs = append([]string{p + "/"}, dirs...)
+	}
+	for _, d := range dirs {
+		if err := tw.WriteHeader(&tar.Header{Name: d, Typeflag: tar.TypeDir, Mode: 0o755}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		b, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		hdr := &tar.Header{Name: prefix + e.Name(), Typeflag: tar.TypeReg, Mode: 0o755, Size: int64(len(b))}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write(b); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
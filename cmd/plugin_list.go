@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"fmt"
+	"helm.sh/helm/v4/pkg/cli"
+	"io"
+
+	"github.com/gosuri/uitable"
+	"github.com/huangxiaofeng10047/go-cli-example/cmd/i18n"
+	"github.com/spf13/cobra"
+
+	"helm.sh/helm/v4/cmd/helm/require"
+	"helm.sh/helm/v4/pkg/action"
+	"helm.sh/helm/v4/pkg/cli/output"
+	"helm.sh/helm/v4/pkg/plugin"
+)
+
+func newPluginListCmd(settings *cli.EnvSettings, out io.Writer, debug action.DebugLog) *cobra.Command {
+	var outfmt output.Format
+	cmd := &cobra.Command{
+		Use:               "list",
+		Aliases:           []string{"ls"},
+		Short:             i18n.T("plugin.listShort"),
+		Args:              require.NoArgs,
+		ValidArgsFunction: noMoreArgsCompFunc,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			debug("pluginDirs: %s", settings.PluginsDirectory)
+			plugins, err := plugin.FindPlugins(settings.PluginsDirectory)
+			if err != nil {
+				return err
+			}
+
+			elements := make([]pluginElement, 0, len(plugins))
+			for _, p := range plugins {
+				e := pluginElement{
+					Name:        p.Metadata.Name,
+					Version:     p.Metadata.Version,
+					Description: p.Metadata.Description,
+					Path:        p.Dir,
+				}
+				if c := builtinCommand(cmd.Root(), p.Metadata.Name); c != nil {
+					e.Conflict = c.Name()
+				}
+				elements = append(elements, e)
+			}
+			return outfmt.Write(out, &pluginListWriter{elements})
+		},
+	}
+	bindOutputFlag(cmd, &outfmt)
+	return cmd
+}
+
+type pluginElement struct {
+	Name        string `json:"name"`
+	Version     string `json:"version"`
+	Description string `json:"description"`
+	Path        string `json:"path"`
+	// Conflict is the built-in command that prevents the plugin from being
+	// loaded.
+	Conflict string `json:"conflict,omitempty"`
+}
+
+type pluginListWriter struct {
+	plugins []pluginElement
+}
+
+func (w *pluginListWriter) WriteTable(out io.Writer) error {
+	table := uitable.New()
+	table.AddRow("NAME", "VERSION", "DESCRIPTION")
+	for _, p := range w.plugins {
+		description := p.Description
+		if p.Conflict != "" {
+			description = i18n.T("plugin.notLoaded", p.Conflict, description)
+		}
+		table.AddRow(p.Name, p.Version, description)
+	}
+	return output.EncodeTable(out, table)
+}
+
+func (w *pluginListWriter) WriteJSON(out io.Writer) error {
+	return output.EncodeJSON(out, w.plugins)
+}
+
+func (w *pluginListWriter) WriteYAML(out io.Writer) error {
+	return output.EncodeYAML(out, w.plugins)
+}
+
+// Returns all plugins from plugins, except those with names matching ignoredPluginNames
+func filterPlugins(plugins []*plugin.Plugin, ignoredPluginNames []string) []*plugin.Plugin {
+	// if ignoredPluginNames is nil, just return plugins
+	if ignoredPluginNames == nil {
+		return plugins
+	}
+
+	var filteredPlugins []*plugin.Plugin
+	for _, plugin := range plugins {
+		found := false
+		for _, ignoredName := range ignoredPluginNames {
+			if plugin.Metadata.Name == ignoredName {
+				found = true
+				break
+			}
+		}
+		if !found {
+			filteredPlugins = append(filteredPlugins, plugin)
+		}
+	}
+
+	return filteredPlugins
+}
+
+// Provide dynamic auto-completion for plugin names
+func compListPlugins(settings *cli.EnvSettings, _ string, ignoredPluginNames []string) []string {
+	var pNames []string
+	plugins, err := plugin.FindPlugins(settings.PluginsDirectory)
+	if err == nil && len(plugins) > 0 {
+		filteredPlugins := filterPlugins(plugins, ignoredPluginNames)
+		for _, p := range filteredPlugins {
+			pNames = append(pNames, fmt.Sprintf("%s\t%s", p.Metadata.Name, p.Metadata.Usage))
+		}
+	}
+	return pNames
+}
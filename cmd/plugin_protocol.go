@@ -0,0 +1,312 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"helm.sh/helm/v4/pkg/cli"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gosuri/uitable"
+	"github.com/pkg/errors"
+
+	installevent "github.com/huangxiaofeng10047/go-cli-example/cmd/event"
+	"github.com/huangxiaofeng10047/go-cli-example/cmd/i18n"
+	"github.com/huangxiaofeng10047/go-cli-example/cmd/progress"
+	"helm.sh/helm/v4/pkg/action"
+	"helm.sh/helm/v4/pkg/cli/output"
+	"helm.sh/helm/v4/pkg/plugin"
+	"helm.sh/helm/v4/pkg/release"
+)
+
+// Events of the plugin protocol. A plugin takes part in install and upgrade
+// with a hook named after the event in its plugin.yaml; see 'gce plugin --help'.
+const (
+	pluginEventPostDeploy = "post-deploy"
+	pluginEventTest       = "test"
+
+	pluginAPIVersion = "gce.plugin/v1"
+)
+
+// Statuses of a pluginResult.
+const (
+	pluginResultPassed  = "passed"
+	pluginResultFailed  = "failed"
+	pluginResultSkipped = "skipped"
+)
+
+// pluginEventTimeout bounds a single run of a plugin hook.
+const pluginEventTimeout = 10 * time.Minute
+
+// testBackend is the plugin that runs the test cases instead of the test
+// service.
+var testBackend string
+
+// pluginRequest is written to the stdin of the hook.
+type pluginRequest struct {
+	APIVersion string        `json:"apiVersion"`
+	Event      string        `json:"event"`
+	Release    pluginRelease `json:"release"`
+	// Endpoints are the addresses of the release and of the test service.
+	Endpoints map[string]string `json:"endpoints"`
+	// Credentials are the secrets needed to reach the endpoints.
+	Credentials map[string]string `json:"credentials,omitempty"`
+}
+
+type pluginRelease struct {
+	Name         string `json:"name"`
+	Namespace    string `json:"namespace"`
+	Revision     int    `json:"revision"`
+	Status       string `json:"status"`
+	Chart        string `json:"chart,omitempty"`
+	ChartVersion string `json:"chartVersion,omitempty"`
+	AppVersion   string `json:"appVersion,omitempty"`
+	Manifest     string `json:"manifest,omitempty"`
+}
+
+// pluginResponse is read from the stdout of the hook.
+type pluginResponse struct {
+	Results []pluginResult `json:"results"`
+}
+
+type pluginResult struct {
+	// Plugin is set by the CLI to the name of the plugin.
+	Plugin  string `json:"plugin"`
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+// eventPlugins returns the installed plugins with a hook for event.
+func eventPlugins(settings *cli.EnvSettings, event string) ([]*plugin.Plugin, error) {
+	plugins, err := plugin.FindPlugins(settings.PluginsDirectory)
+	if err != nil {
+		return nil, err
+	}
+	var found []*plugin.Plugin
+	for _, p := range plugins {
+		if _, _, ok := hookCommand(p, event); ok {
+			found = append(found, p)
+		}
+	}
+	return found, nil
+}
+
+// newPluginRequest returns the request of event about rel. target may be nil
+// if the release has no proxy to test.
+func newPluginRequest(event string, rel *release.Release, target *installevent.Target) *pluginRequest {
+	req := &pluginRequest{
+		APIVersion: pluginAPIVersion,
+		Event:      event,
+		Release: pluginRelease{
+			Name:      rel.Name,
+			Namespace: rel.Namespace,
+			Revision:  rel.Version,
+			Manifest:  rel.Manifest,
+		},
+		Endpoints: map[string]string{
+			"testService": testConfig.BaseURL(),
+		},
+	}
+	if rel.Info != nil {
+		req.Release.Status = rel.Info.Status.String()
+	}
+	if rel.Chart != nil && rel.Chart.Metadata != nil {
+		req.Release.Chart = rel.Chart.Metadata.Name
+		req.Release.ChartVersion = rel.Chart.Metadata.Version
+		req.Release.AppVersion = rel.Chart.Metadata.AppVersion
+	}
+	if target != nil {
+		req.Endpoints["pulsarServiceUrl"] = "pulsar://" + target.ProxyIP + ":6650"
+		req.Endpoints["pulsarHttpServiceUrl"] = "http://" + target.ProxyIP
+		req.Credentials = map[string]string{"authToken": target.Token}
+	}
+	return req
+}
+
+// runPluginEvent runs the hook of p for the event of req and returns its
+// results. Results without a valid status are failed.
+func runPluginEvent(ctx context.Context, settings *cli.EnvSettings, p *plugin.Plugin, req *pluginRequest, debug action.DebugLog) ([]pluginResult, error) {
+	plugin.SetupPluginEnv(settings, p.Metadata.Name, p.Dir)
+	main, argv, ok := hookCommand(p, req.Event)
+	if !ok {
+		return nil, errors.New(i18n.T("plugin.noHook", p.Metadata.Name, req.Event))
+	}
+	in, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, pluginEventTimeout)
+	defer cancel()
+	prog := exec.CommandContext(ctx, main, argv...)
+	debug("running %s hook: %s", req.Event, prog)
+	var stdout bytes.Buffer
+	prog.Stdin, prog.Stdout, prog.Stderr = bytes.NewReader(in), &stdout, os.Stderr
+	if err := prog.Run(); err != nil {
+		return nil, errors.Wrap(err, i18n.T("plugin.hookFailed", req.Event, p.Metadata.Name))
+	}
+
+	var resp pluginResponse
+	if err := json.Unmarshal(stdout.Bytes(), &resp); err != nil {
+		return nil, errors.Wrap(err, i18n.T("plugin.invalidResponse", req.Event, p.Metadata.Name))
+	}
+	for i := range resp.Results {
+		r := &resp.Results[i]
+		r.Plugin = p.Metadata.Name
+		switch r.Status {
+		case pluginResultPassed, pluginResultFailed, pluginResultSkipped:
+		default:
+			r.Message = strings.TrimSpace(i18n.T("plugin.invalidStatus", r.Status, r.Message))
+			r.Status = pluginResultFailed
+		}
+	}
+	return resp.Results, nil
+}
+
+// runPostDeployChecks runs the post-deploy checks of all plugins against rel
+// and returns their results. A plugin that cannot be run fails with a single
+// result.
+func runPostDeployChecks(ctx context.Context, settings *cli.EnvSettings, cfg *action.Configuration, event *installevent.InstallEvent, rel *release.Release, p *progress.Progress, debug action.DebugLog) ([]pluginResult, error) {
+	plugins, err := eventPlugins(settings, pluginEventPostDeploy)
+	if err != nil || len(plugins) == 0 {
+		return nil, err
+	}
+	p.Phase(i18n.T("progress.checks"))
+
+	target, err := event.ResolveTarget(settings, cfg, rel.Name)
+	if err != nil {
+		debug("running post-deploy checks without endpoints of the release: %s", err)
+		target = nil
+	}
+	req := newPluginRequest(pluginEventPostDeploy, rel, target)
+
+	var results []pluginResult
+	for _, plug := range plugins {
+		res, err := runPluginEvent(ctx, settings, plug, req, debug)
+		if err != nil {
+			res = []pluginResult{{Plugin: plug.Metadata.Name, Name: plug.Metadata.Name, Status: pluginResultFailed, Message: err.Error()}}
+		}
+		results = append(results, res...)
+	}
+
+	if checksFailed(results) {
+		p.Finish(errors.New(i18n.T("plugin.checksFailed")))
+	} else {
+		p.Stop()
+	}
+	return results, nil
+}
+
+// checksFailed reports whether any of the results failed.
+func checksFailed(results []pluginResult) bool {
+	for _, r := range results {
+		if r.Status == pluginResultFailed {
+			return true
+		}
+	}
+	return false
+}
+
+// writeCheckResults writes the table of the post-deploy check results. In
+// JSON and YAML they are the checks of the deployReport.
+func writeCheckResults(out io.Writer, results []pluginResult) error {
+	fmt.Fprintln(out, "POST-DEPLOY CHECKS:")
+	table := uitable.New()
+	table.AddRow("PLUGIN", "CHECK", "STATUS", "MESSAGE")
+	for _, r := range results {
+		table.AddRow(r.Plugin, r.Name, r.Status, r.Message)
+	}
+	return output.EncodeTable(out, table)
+}
+
+// newInstallEvent returns the install event of a deploy of the release called
+// name. The test cases are run by the test backend plugin if one is set with
+// --test-backend.
+func newInstallEvent(settings *cli.EnvSettings, cfg *action.Configuration, name string, p *progress.Progress, debug action.DebugLog) (*installevent.InstallEvent, error) {
+	config := *testConfig
+	if testBackend != "" {
+		client, err := newPluginTestClient(settings, cfg, testBackend, name, debug)
+		if err != nil {
+			return nil, err
+		}
+		config.Client = client
+	}
+	return installevent.NewInstallEvent(&config, p)
+}
+
+// pluginTestClient runs the test cases with the test hook of a plugin instead
+// of the test service. It implements installevent.TestClient: Trigger runs the
+// hook and Trigger2 returns its results in the format of the test service.
+type pluginTestClient struct {
+	settings *cli.EnvSettings
+	cfg      *action.Configuration
+	plugin   *plugin.Plugin
+	release  string
+	debug    action.DebugLog
+
+	mu      sync.Mutex
+	results []pluginResult
+}
+
+// newPluginTestClient returns the test client of the plugin called name for
+// the release called release.
+func newPluginTestClient(settings *cli.EnvSettings, cfg *action.Configuration, name, release string, debug action.DebugLog) (*pluginTestClient, error) {
+	plugins, err := eventPlugins(settings, pluginEventTest)
+	if err != nil {
+		return nil, err
+	}
+	p := findPlugin(plugins, name)
+	if p == nil {
+		return nil, errors.New(i18n.T("plugin.noBackend", name, pluginEventTest))
+	}
+	return &pluginTestClient{settings: settings, cfg: cfg, plugin: p, release: release, debug: debug}, nil
+}
+
+func (c *pluginTestClient) Trigger(ctx context.Context, ip string, token string) ([]byte, error) {
+	rel, err := c.cfg.Releases.Last(c.release)
+	if err != nil {
+		return nil, err
+	}
+	req := newPluginRequest(pluginEventTest, rel, &installevent.Target{ProxyIP: ip, Token: token})
+	results, err := runPluginEvent(ctx, c.settings, c.plugin, req, c.debug)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.results = results
+	c.mu.Unlock()
+	return json.Marshal(installevent.TestCaseResponse{Code: 200, Message: "ok", Data: c.plugin.Metadata.Name})
+}
+
+func (c *pluginTestClient) Trigger2(_ context.Context, _ string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	resp := installevent.TaskStatusResponse{Code: 200, Message: "ok", Data: []installevent.TestDataItem{}}
+	for _, r := range c.results {
+		info := installevent.ErrorInfo{}
+		if r.Status == pluginResultFailed {
+			info.ErrorMsg = r.Message
+			if info.ErrorMsg == "" {
+				info.ErrorMsg = pluginResultFailed
+			}
+		}
+		msg, err := json.Marshal(info)
+		if err != nil {
+			return "", err
+		}
+		resp.Data = append(resp.Data, installevent.TestDataItem{Name: r.Name, Message: string(msg)})
+	}
+	if len(resp.Data) == 0 {
+		// The task is done even if the plugin ran no test.
+		resp.Data = append(resp.Data, installevent.TestDataItem{Name: c.plugin.Metadata.Name, Message: "{}"})
+	}
+	b, err := json.Marshal(resp)
+	return string(b), err
+}
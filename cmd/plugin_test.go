@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"io"
+	"testing"
+
+	"helm.sh/helm/v4/pkg/action"
+	"helm.sh/helm/v4/pkg/cli"
+)
+
+func TestBuiltinCommand(t *testing.T) {
+	isolateEnv(t, "testdata/plugins")
+	root, err := NewRootCmd(cli.New(), new(action.Configuration), io.Discard, nil, func(string, ...interface{}) {})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"install", "install"},
+		{"ls", "list"},
+		{"help", "help"},
+		{"completion", "completion"},
+		{"smoke", ""},
+		{"missing", ""},
+	}
+	for _, tt := range tests {
+		got := ""
+		if c := builtinCommand(root, tt.name); c != nil {
+			got = c.Name()
+		}
+		if got != tt.want {
+			t.Errorf("%s: expected built-in command %q, got %q", tt.name, tt.want, got)
+		}
+	}
+}
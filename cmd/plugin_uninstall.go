@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"fmt"
+	"helm.sh/helm/v4/pkg/cli"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/huangxiaofeng10047/go-cli-example/cmd/i18n"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"helm.sh/helm/v4/pkg/action"
+	"helm.sh/helm/v4/pkg/plugin"
+)
+
+func newPluginUninstallCmd(settings *cli.EnvSettings, out io.Writer, debug action.DebugLog) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "uninstall <plugin>...",
+		Aliases: []string{"rm", "remove"},
+		Short:   i18n.T("plugin.uninstallShort"),
+		ValidArgsFunction: func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return compListPlugins(settings, toComplete, args), cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(_ *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return errors.New(i18n.T("plugin.uninstallNoName"))
+			}
+
+			debug("loading installed plugins from %s", settings.PluginsDirectory)
+			plugins, err := plugin.FindPlugins(settings.PluginsDirectory)
+			if err != nil {
+				return err
+			}
+			var errorPlugins []string
+			for _, name := range args {
+				if found := findPlugin(plugins, name); found != nil {
+					if err := uninstallPlugin(settings, found, out, debug); err != nil {
+						errorPlugins = append(errorPlugins, i18n.T("plugin.uninstallFailed", name, err))
+					} else {
+						fmt.Fprint(out, i18n.T("plugin.uninstalled", name))
+					}
+				} else {
+					errorPlugins = append(errorPlugins, i18n.T("plugin.notFound", name))
+				}
+			}
+			if len(errorPlugins) > 0 {
+				return errors.New(strings.Join(errorPlugins, "\n"))
+			}
+			return nil
+		},
+	}
+	return cmd
+}
+
+func uninstallPlugin(settings *cli.EnvSettings, p *plugin.Plugin, out io.Writer, debug action.DebugLog) error {
+	if err := os.RemoveAll(p.Dir); err != nil {
+		return err
+	}
+	return runHook(settings, p, plugin.Delete, out, debug)
+}
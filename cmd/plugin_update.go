@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+	"helm.sh/helm/v4/pkg/cli"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/huangxiaofeng10047/go-cli-example/cmd/i18n"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"helm.sh/helm/v4/pkg/action"
+	"helm.sh/helm/v4/pkg/plugin"
+	"helm.sh/helm/v4/pkg/plugin/installer"
+)
+
+func newPluginUpdateCmd(settings *cli.EnvSettings, out io.Writer, debug action.DebugLog) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "update <plugin>...",
+		Aliases: []string{"up"},
+		Short:   i18n.T("plugin.updateShort"),
+		Long:    i18n.T("plugin.updateLong"),
+		ValidArgsFunction: func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return compListPlugins(settings, toComplete, args), cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(_ *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return errors.New(i18n.T("plugin.updateNoName"))
+			}
+
+			installer.Debug = settings.Debug
+			debug("loading installed plugins from %s", settings.PluginsDirectory)
+			plugins, err := plugin.FindPlugins(settings.PluginsDirectory)
+			if err != nil {
+				return err
+			}
+			var errorPlugins []string
+
+			for _, name := range args {
+				if found := findPlugin(plugins, name); found != nil {
+					if err := updatePlugin(settings, found, out, debug); err != nil {
+						errorPlugins = append(errorPlugins, i18n.T("plugin.updateFailed", name, err))
+					} else {
+						fmt.Fprint(out, i18n.T("plugin.updated", name))
+					}
+				} else {
+					errorPlugins = append(errorPlugins, i18n.T("plugin.notFound", name))
+				}
+			}
+			if len(errorPlugins) > 0 {
+				return errors.New(strings.Join(errorPlugins, "\n"))
+			}
+			return nil
+		},
+	}
+	return cmd
+}
+
+func updatePlugin(settings *cli.EnvSettings, p *plugin.Plugin, out io.Writer, debug action.DebugLog) error {
+	if fi, err := os.Lstat(p.Dir); err == nil && fi.Mode()&os.ModeSymlink != 0 {
+		debug("%s links to a local directory, which is always up to date", p.Dir)
+		return runHook(settings, p, plugin.Update, out, debug)
+	}
+
+	exactLocation, err := filepath.EvalSymlinks(p.Dir)
+	if err != nil {
+		return err
+	}
+	absExactLocation, err := filepath.Abs(exactLocation)
+	if err != nil {
+		return err
+	}
+
+	i, err := installer.FindSource(absExactLocation)
+	if err != nil {
+		return err
+	}
+	if err := installer.Update(i); err != nil {
+		return err
+	}
+
+	debug("loading plugin from %s", i.Path())
+	updatedPlugin, err := plugin.LoadDir(i.Path())
+	if err != nil {
+		return err
+	}
+
+	return runHook(settings, updatedPlugin, plugin.Update, out, debug)
+}
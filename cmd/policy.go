@@ -0,0 +1,364 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/gosuri/uitable"
+	"github.com/huangxiaofeng10047/go-cli-example/cmd/i18n"
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/yaml"
+
+	"helm.sh/helm/v4/pkg/action"
+	chart "helm.sh/helm/v4/pkg/chart/v2"
+	chartutil "helm.sh/helm/v4/pkg/chart/v2/util"
+	"helm.sh/helm/v4/pkg/cli/output"
+	"helm.sh/helm/v4/pkg/postrender"
+	"helm.sh/helm/v4/pkg/release"
+)
+
+// Formats of the policy report.
+const (
+	policyFormatTable = "table"
+	policyFormatJSON  = "json"
+	policyFormatSARIF = "sarif"
+)
+
+var policyFormats = []string{policyFormatTable, policyFormatJSON, policyFormatSARIF}
+
+// offlineKubeVersion is the Kubernetes version charts are rendered for when
+// they are rendered without a cluster, outside of 'template'.
+const offlineKubeVersion = "v1.30.0"
+
+var (
+	// policyDir is set by --policy on install, upgrade and template.
+	policyDir string
+	// policyFormat is set by --policy-output.
+	policyFormat string
+)
+
+// policyFile is the format of the files in the policy directory:
+//
+//	rules:
+//	  - name: no-latest-images
+//	    rule: disallowLatestTag
+//	  - name: storage-classes
+//	    rule: allowedStorageClasses
+//	    severity: warning
+//	    exclude: ["StatefulSet/*-zookeeper"]
+//	    params:
+//	      classes: [local-path, ceph-rbd]
+type policyFile struct {
+	Rules []*policyRule `json:"rules"`
+}
+
+// policyRule configures one check.
+type policyRule struct {
+	Name     string                 `json:"name"`
+	Rule     string                 `json:"rule"`
+	Severity string                 `json:"severity,omitempty"`
+	Exclude  []string               `json:"exclude,omitempty"`
+	Params   map[string]interface{} `json:"params,omitempty"`
+}
+
+// policyViolation is a rendered object that does not follow a rule.
+type policyViolation struct {
+	Policy    string `json:"policy"`
+	Rule      string `json:"rule"`
+	Severity  string `json:"severity"`
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	Source    string `json:"source,omitempty"`
+	Message   string `json:"message"`
+}
+
+func addPolicyFlags(f *pflag.FlagSet) {
+	f.StringVar(&policyDir, "policy", "", i18n.T("policy.flagPolicy"))
+	f.StringVar(&policyFormat, "policy-output", policyFormatTable, i18n.T("policy.flagOutput", strings.Join(policyFormats, ", ")))
+}
+
+// loadPolicies reads every YAML file in dir, in name order.
+func loadPolicies(dir string) ([]*policyRule, error) {
+	var files []string
+	for _, pattern := range []string{"*.yaml", "*.yml"} {
+		m, err := filepath.Glob(filepath.Join(dir, pattern))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, m...)
+	}
+	sort.Strings(files)
+
+	var rules []*policyRule
+	for _, file := range files {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		pf := &policyFile{}
+		if err := yaml.UnmarshalStrict(b, pf); err != nil {
+			return nil, errors.Wrap(err, i18n.T("policy.parseFile", file))
+		}
+		for i, r := range pf.Rules {
+			if _, ok := policyChecks[r.Rule]; !ok {
+				return nil, errors.New(i18n.T("policy.unknownRule", file, i+1, r.Rule))
+			}
+			if r.Name == "" {
+				r.Name = r.Rule
+			}
+			switch r.Severity {
+			case "":
+				r.Severity = severityError
+			case severityError, severityWarning:
+			default:
+				return nil, errors.New(i18n.T("policy.badSeverity", file, r.Name, severityError, severityWarning))
+			}
+			rules = append(rules, r)
+		}
+	}
+	if len(rules) == 0 {
+		return nil, errors.New(i18n.T("policy.none", dir))
+	}
+	return rules, nil
+}
+
+// evaluatePolicies runs every rule against the rendered objects.
+func evaluatePolicies(rules []*policyRule, objs []*renderedObject) ([]policyViolation, error) {
+	violations := []policyViolation{}
+	for _, r := range rules {
+		v, err := policyChecks[r.Rule](r, objs)
+		if err != nil {
+			return nil, errors.Wrap(err, i18n.T("policy.failed", r.Name))
+		}
+		violations = append(violations, v...)
+	}
+	return violations, nil
+}
+
+// checkManifestPolicy evaluates --policy against rendered manifests. The
+// report goes to stderr so it does not mix with the manifests or release
+// output; an error is returned when a rule with severity error is violated.
+func checkManifestPolicy(manifests []string) error {
+	if policyDir == "" {
+		return nil
+	}
+	if !slices.Contains(policyFormats, policyFormat) {
+		return errors.New(i18n.T("policy.badOutput", policyFormat, strings.Join(policyFormats, ", ")))
+	}
+	rules, err := loadPolicies(policyDir)
+	if err != nil {
+		return err
+	}
+	objs, err := parseRenderedManifests(manifests)
+	if err != nil {
+		return err
+	}
+	violations, err := evaluatePolicies(rules, objs)
+	if err != nil {
+		return err
+	}
+
+	if err := writePolicyReport(os.Stderr, policyFormat, rules, violations); err != nil {
+		return err
+	}
+	errs := 0
+	for _, v := range violations {
+		if v.Severity == severityError {
+			errs++
+		}
+	}
+	if errs > 0 {
+		return errors.New(i18n.T("policy.violations", errs))
+	}
+	return nil
+}
+
+// checkChartPolicy renders ch offline, the way 'template' does, and checks
+// the result against --policy. It is used by install and upgrade before they
+// reach the cluster.
+func checkChartPolicy(ch *chart.Chart, vals map[string]interface{}, releaseName, namespace string, isUpgrade bool, pr postrender.PostRenderer, debug action.DebugLog) error {
+	if policyDir == "" {
+		return nil
+	}
+	manifests, err := renderChartOffline(ch, vals, offlineRender{
+		releaseName:  releaseName,
+		namespace:    namespace,
+		isUpgrade:    isUpgrade,
+		postRenderer: pr,
+	}, debug)
+	if err != nil {
+		return errors.Wrap(err, i18n.T("policy.render"))
+	}
+	return checkManifestPolicy(manifests)
+}
+
+// offlineRender describes how a chart is rendered without a cluster.
+type offlineRender struct {
+	releaseName string
+	namespace   string
+	isUpgrade   bool
+	// kubeVersion defaults to offlineKubeVersion.
+	kubeVersion  string
+	apiVersions  []string
+	postRenderer postrender.PostRenderer
+}
+
+// renderChartOffline renders ch client side, including hooks, and returns
+// the manifests in render order.
+func renderChartOffline(ch *chart.Chart, vals map[string]interface{}, opts offlineRender, debug action.DebugLog) ([]string, error) {
+	if opts.kubeVersion == "" {
+		opts.kubeVersion = offlineKubeVersion
+	}
+	kubeVersion, err := chartutil.ParseKubeVersion(opts.kubeVersion)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", i18n.T("error.badKubeVersion", opts.kubeVersion), err)
+	}
+
+	// A separate configuration, as a client only install replaces the
+	// kube client and release storage of the one it is given.
+	client := action.NewInstall(&action.Configuration{Log: debug})
+	client.DryRun = true
+	client.DryRunOption = "client"
+	client.ClientOnly = true
+	client.Replace = true
+	client.ReleaseName = opts.releaseName
+	client.Namespace = opts.namespace
+	client.IsUpgrade = opts.isUpgrade
+	client.KubeVersion = kubeVersion
+	client.APIVersions = chartutil.VersionSet(opts.apiVersions)
+	client.PostRenderer = opts.postRenderer
+
+	rel, err := client.RunWithContext(context.Background(), ch, vals)
+	if err != nil {
+		return nil, err
+	}
+	return releaseManifests(rel), nil
+}
+
+// releaseManifests returns the manifests and hooks of rel in render order.
+func releaseManifests(rel *release.Release) []string {
+	manifests := []string{rel.Manifest}
+	for _, h := range rel.Hooks {
+		manifests = append(manifests, fmt.Sprintf("# Source: %s\n%s", h.Path, h.Manifest))
+	}
+	return sortedManifests(strings.Join(manifests, "\n---\n"))
+}
+
+func writePolicyReport(out io.Writer, format string, rules []*policyRule, violations []policyViolation) error {
+	switch format {
+	case policyFormatJSON:
+		return output.EncodeJSON(out, violations)
+	case policyFormatSARIF:
+		return output.EncodeJSON(out, newSARIFReport(rules, violations))
+	}
+
+	if len(violations) == 0 {
+		_, err := fmt.Fprintln(out, i18n.T("policy.noViolations"))
+		return err
+	}
+	table := uitable.New()
+	table.AddRow("SEVERITY", "POLICY", "OBJECT", "SOURCE", "MESSAGE")
+	for _, v := range violations {
+		table.AddRow(v.Severity, v.Policy, v.Kind+"/"+v.Name, v.Source, v.Message)
+	}
+	return output.EncodeTable(out, table)
+}
+
+// The subset of SARIF 2.1.0 needed to report policy violations, so that they
+// can be uploaded to code scanning dashboards.
+type sarifReport struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name  string      `json:"name"`
+	Rules []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+func newSARIFReport(rules []*policyRule, violations []policyViolation) *sarifReport {
+	run := sarifRun{
+		Tool:    sarifTool{Driver: sarifDriver{Name: "go-cli-example policy"}},
+		Results: []sarifResult{},
+	}
+	for _, r := range rules {
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{
+			ID:               r.Name,
+			ShortDescription: sarifMessage{Text: r.Rule},
+		})
+	}
+	for _, v := range violations {
+		loc := sarifLocation{
+			LogicalLocations: []sarifLogicalLocation{{
+				FullyQualifiedName: v.Kind + "/" + v.Name,
+				Kind:               "object",
+			}},
+		}
+		if v.Source != "" {
+			loc.PhysicalLocation = &sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: v.Source}}
+		}
+		run.Results = append(run.Results, sarifResult{
+			RuleID:    v.Policy,
+			Level:     v.Severity,
+			Message:   sarifMessage{Text: v.Message},
+			Locations: []sarifLocation{loc},
+		})
+	}
+	return &sarifReport{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	}
+}
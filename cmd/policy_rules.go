@@ -0,0 +1,310 @@
+package cmd
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/huangxiaofeng10047/go-cli-example/cmd/i18n"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/yaml"
+)
+
+// Rule types that can be used in a policy file.
+const (
+	ruleDisallowLatestTag     = "disallowLatestTag"
+	ruleRequireResources      = "requireResources"
+	ruleDisallowPrivileged    = "disallowPrivileged"
+	ruleRequirePDB            = "requirePodDisruptionBudget"
+	ruleAllowedStorageClasses = "allowedStorageClasses"
+)
+
+// policyCheck evaluates one rule type against all rendered objects.
+type policyCheck func(r *policyRule, objs []*renderedObject) ([]policyViolation, error)
+
+var policyChecks = map[string]policyCheck{
+	ruleDisallowLatestTag:     checkLatestTag,
+	ruleRequireResources:      checkResources,
+	ruleDisallowPrivileged:    checkPrivileged,
+	ruleRequirePDB:            checkPodDisruptionBudgets,
+	ruleAllowedStorageClasses: checkStorageClasses,
+}
+
+// podSpecOf returns the pod spec of workload objects, nil for anything else.
+func podSpecOf(o *renderedObject) (*corev1.PodSpec, error) {
+	switch o.Kind {
+	case "Pod":
+		pod := &corev1.Pod{}
+		if err := yaml.Unmarshal([]byte(o.body), pod); err != nil {
+			return nil, err
+		}
+		return &pod.Spec, nil
+	case "Deployment":
+		d := &appsv1.Deployment{}
+		if err := yaml.Unmarshal([]byte(o.body), d); err != nil {
+			return nil, err
+		}
+		return &d.Spec.Template.Spec, nil
+	case "StatefulSet":
+		s := &appsv1.StatefulSet{}
+		if err := yaml.Unmarshal([]byte(o.body), s); err != nil {
+			return nil, err
+		}
+		return &s.Spec.Template.Spec, nil
+	case "DaemonSet":
+		d := &appsv1.DaemonSet{}
+		if err := yaml.Unmarshal([]byte(o.body), d); err != nil {
+			return nil, err
+		}
+		return &d.Spec.Template.Spec, nil
+	case "ReplicaSet":
+		r := &appsv1.ReplicaSet{}
+		if err := yaml.Unmarshal([]byte(o.body), r); err != nil {
+			return nil, err
+		}
+		return &r.Spec.Template.Spec, nil
+	case "Job":
+		j := &batchv1.Job{}
+		if err := yaml.Unmarshal([]byte(o.body), j); err != nil {
+			return nil, err
+		}
+		return &j.Spec.Template.Spec, nil
+	case "CronJob":
+		c := &batchv1.CronJob{}
+		if err := yaml.Unmarshal([]byte(o.body), c); err != nil {
+			return nil, err
+		}
+		return &c.Spec.JobTemplate.Spec.Template.Spec, nil
+	}
+	return nil, nil
+}
+
+// allContainers returns the init and regular containers of spec.
+func allContainers(spec *corev1.PodSpec) []corev1.Container {
+	return append(append([]corev1.Container{}, spec.InitContainers...), spec.Containers...)
+}
+
+// forEachContainer calls fn for every container of every workload that is
+// not excluded by the rule.
+func forEachContainer(r *policyRule, objs []*renderedObject, fn func(o *renderedObject, c corev1.Container) string) ([]policyViolation, error) {
+	var violations []policyViolation
+	for _, o := range objs {
+		if r.excluded(o) {
+			continue
+		}
+		spec, err := podSpecOf(o)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", i18n.T("policy.parseObject", o.Kind, o.Metadata.Name), err)
+		}
+		if spec == nil {
+			continue
+		}
+		for _, c := range allContainers(spec) {
+			if msg := fn(o, c); msg != "" {
+				violations = append(violations, r.violation(o, msg))
+			}
+		}
+	}
+	return violations, nil
+}
+
+// imageTag returns the tag of an image reference, empty when it has none.
+// Digests are treated as pinned.
+func imageTag(image string) (tag string, pinned bool) {
+	if strings.Contains(image, "@") {
+		return "", true
+	}
+	name := image[strings.LastIndex(image, "/")+1:]
+	if i := strings.LastIndex(name, ":"); i >= 0 {
+		return name[i+1:], false
+	}
+	return "", false
+}
+
+func checkLatestTag(r *policyRule, objs []*renderedObject) ([]policyViolation, error) {
+	return forEachContainer(r, objs, func(_ *renderedObject, c corev1.Container) string {
+		tag, pinned := imageTag(c.Image)
+		switch {
+		case pinned:
+			return ""
+		case tag == "":
+			return i18n.T("policy.noTag", c.Name, c.Image)
+		case tag == "latest":
+			return i18n.T("policy.latestTag", c.Name, c.Image)
+		}
+		return ""
+	})
+}
+
+// checkResources requires requests and limits for the resources listed in
+// the 'resources' parameter (cpu and memory by default). Set 'requests' or
+// 'limits' to false to only check the other.
+func checkResources(r *policyRule, objs []*renderedObject) ([]policyViolation, error) {
+	names := r.stringsParam("resources", []string{"cpu", "memory"})
+	requests := r.boolParam("requests", true)
+	limits := r.boolParam("limits", true)
+	return forEachContainer(r, objs, func(_ *renderedObject, c corev1.Container) string {
+		var missing []string
+		for _, n := range names {
+			if _, ok := c.Resources.Requests[corev1.ResourceName(n)]; requests && !ok {
+				missing = append(missing, "requests."+n)
+			}
+			if _, ok := c.Resources.Limits[corev1.ResourceName(n)]; limits && !ok {
+				missing = append(missing, "limits."+n)
+			}
+		}
+		if len(missing) == 0 {
+			return ""
+		}
+		return i18n.T("policy.noResources", c.Name, strings.Join(missing, ", "))
+	})
+}
+
+func checkPrivileged(r *policyRule, objs []*renderedObject) ([]policyViolation, error) {
+	return forEachContainer(r, objs, func(_ *renderedObject, c corev1.Container) string {
+		if sc := c.SecurityContext; sc != nil && sc.Privileged != nil && *sc.Privileged {
+			return i18n.T("policy.privileged", c.Name)
+		}
+		return ""
+	})
+}
+
+// checkPodDisruptionBudgets requires every StatefulSet to be selected by a
+// PodDisruptionBudget in the same namespace.
+func checkPodDisruptionBudgets(r *policyRule, objs []*renderedObject) ([]policyViolation, error) {
+	type pdb struct {
+		namespace string
+		selector  labels.Selector
+	}
+	var pdbs []pdb
+	for _, o := range objs {
+		if o.Kind != "PodDisruptionBudget" {
+			continue
+		}
+		p := &policyv1.PodDisruptionBudget{}
+		if err := yaml.Unmarshal([]byte(o.body), p); err != nil {
+			return nil, fmt.Errorf("%s: %w", i18n.T("policy.parseObject", o.Kind, o.Metadata.Name), err)
+		}
+		if p.Spec.Selector == nil {
+			continue
+		}
+		sel, err := metav1.LabelSelectorAsSelector(p.Spec.Selector)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", i18n.T("policy.badSelector", o.Kind, o.Metadata.Name), err)
+		}
+		pdbs = append(pdbs, pdb{namespace: o.Metadata.Namespace, selector: sel})
+	}
+
+	var violations []policyViolation
+	for _, o := range objs {
+		if o.Kind != "StatefulSet" || r.excluded(o) {
+			continue
+		}
+		s := &appsv1.StatefulSet{}
+		if err := yaml.Unmarshal([]byte(o.body), s); err != nil {
+			return nil, fmt.Errorf("%s: %w", i18n.T("policy.parseObject", o.Kind, o.Metadata.Name), err)
+		}
+		podLabels := labels.Set(s.Spec.Template.Labels)
+		covered := slices.ContainsFunc(pdbs, func(p pdb) bool {
+			return p.namespace == o.Metadata.Namespace && !p.selector.Empty() && p.selector.Matches(podLabels)
+		})
+		if !covered {
+			violations = append(violations, r.violation(o, i18n.T("policy.noPDB")))
+		}
+	}
+	return violations, nil
+}
+
+// checkStorageClasses requires PersistentVolumeClaims, including the claim
+// templates of StatefulSets, to use a storage class from the 'classes'
+// parameter. Claims without a storage class are allowed with 'allowDefault'.
+func checkStorageClasses(r *policyRule, objs []*renderedObject) ([]policyViolation, error) {
+	allowed := r.stringsParam("classes", nil)
+	allowDefault := r.boolParam("allowDefault", false)
+
+	check := func(o *renderedObject, claim string, class *string) []policyViolation {
+		switch {
+		case class == nil || *class == "":
+			if !allowDefault {
+				return []policyViolation{r.violation(o, i18n.T("policy.noStorageClass", claim))}
+			}
+		case !slices.Contains(allowed, *class):
+			return []policyViolation{r.violation(o, i18n.T("policy.storageClass", claim, *class, strings.Join(allowed, ", ")))}
+		}
+		return nil
+	}
+
+	var violations []policyViolation
+	for _, o := range objs {
+		if r.excluded(o) {
+			continue
+		}
+		switch o.Kind {
+		case "PersistentVolumeClaim":
+			pvc := &corev1.PersistentVolumeClaim{}
+			if err := yaml.Unmarshal([]byte(o.body), pvc); err != nil {
+				return nil, fmt.Errorf("%s: %w", i18n.T("policy.parseObject", o.Kind, o.Metadata.Name), err)
+			}
+			violations = append(violations, check(o, pvc.Name, pvc.Spec.StorageClassName)...)
+		case "StatefulSet":
+			s := &appsv1.StatefulSet{}
+			if err := yaml.Unmarshal([]byte(o.body), s); err != nil {
+				return nil, fmt.Errorf("%s: %w", i18n.T("policy.parseObject", o.Kind, o.Metadata.Name), err)
+			}
+			for _, t := range s.Spec.VolumeClaimTemplates {
+				violations = append(violations, check(o, t.Name, t.Spec.StorageClassName)...)
+			}
+		}
+	}
+	return violations, nil
+}
+
+// excluded reports whether the object matches one of the 'exclude' patterns
+// of the rule. Patterns are matched against KIND/NAME, e.g. 'Job/*'.
+func (r *policyRule) excluded(o *renderedObject) bool {
+	ref := o.Kind + "/" + o.Metadata.Name
+	for _, p := range r.Exclude {
+		if ok, _ := path.Match(p, ref); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *policyRule) violation(o *renderedObject, msg string) policyViolation {
+	return policyViolation{
+		Policy:    r.Name,
+		Rule:      r.Rule,
+		Severity:  r.Severity,
+		Kind:      o.Kind,
+		Name:      o.Metadata.Name,
+		Namespace: o.Metadata.Namespace,
+		Source:    o.source,
+		Message:   msg,
+	}
+}
+
+func (r *policyRule) stringsParam(name string, def []string) []string {
+	v, ok := r.Params[name].([]interface{})
+	if !ok {
+		return def
+	}
+	res := make([]string, 0, len(v))
+	for _, s := range v {
+		res = append(res, fmt.Sprint(s))
+	}
+	return res
+}
+
+func (r *policyRule) boolParam(name string, def bool) bool {
+	if v, ok := r.Params[name].(bool); ok {
+		return v
+	}
+	return def
+}
@@ -0,0 +1,216 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+const policyTestManifests = `# Source: pulsar/templates/broker.yaml
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: pulsar-broker
+spec:
+  template:
+    metadata:
+      labels: {component: broker}
+    spec:
+      initContainers:
+        - name: wait
+          image: busybox
+      containers:
+        - name: broker
+          image: apachepulsar/pulsar:3.3.1
+          resources:
+            requests: {cpu: 500m, memory: 1Gi}
+            limits: {cpu: "1", memory: 2Gi}
+  volumeClaimTemplates:
+    - metadata: {name: data}
+      spec: {storageClassName: local-path}
+---
+# Source: pulsar/templates/bookie.yaml
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: pulsar-bookie
+spec:
+  template:
+    metadata:
+      labels: {component: bookie}
+    spec:
+      containers:
+        - name: bookie
+          image: apachepulsar/pulsar:latest
+          securityContext: {privileged: true}
+          resources:
+            requests: {cpu: 500m}
+  volumeClaimTemplates:
+    - metadata: {name: journal}
+      spec: {storageClassName: standard}
+    - metadata: {name: ledgers}
+      spec: {}
+---
+# Source: pulsar/templates/broker-pdb.yaml
+apiVersion: policy/v1
+kind: PodDisruptionBudget
+metadata:
+  name: pulsar-broker
+spec:
+  selector:
+    matchLabels: {component: broker}
+---
+# Source: pulsar/templates/init-job.yaml
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: pulsar-init
+spec:
+  template:
+    spec:
+      containers:
+        - name: init
+          image: registry.local:5000/pulsar@sha256:0123456789abcdef
+---
+# Source: pulsar/templates/toolset-pvc.yaml
+apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: toolset
+spec:
+  storageClassName: local-path
+`
+
+func TestPolicyRules(t *testing.T) {
+	objs, err := parseRenderedManifests(sortedManifests(policyTestManifests))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		rule policyRule
+		want []string
+	}{
+		{
+			name: "latest and missing tags",
+			rule: policyRule{Rule: ruleDisallowLatestTag},
+			want: []string{"StatefulSet/pulsar-bookie", "StatefulSet/pulsar-broker"},
+		},
+		{
+			name: "excluded objects",
+			rule: policyRule{Rule: ruleDisallowLatestTag, Exclude: []string{"StatefulSet/*-broker"}},
+			want: []string{"StatefulSet/pulsar-bookie"},
+		},
+		{
+			name: "requests and limits",
+			rule: policyRule{Rule: ruleRequireResources},
+			want: []string{"Job/pulsar-init", "StatefulSet/pulsar-bookie", "StatefulSet/pulsar-broker"},
+		},
+		{
+			name: "requests only",
+			rule: policyRule{Rule: ruleRequireResources, Exclude: []string{"Job/*"}, Params: map[string]interface{}{
+				"resources": []interface{}{"cpu"},
+				"limits":    false,
+			}},
+			want: []string{"StatefulSet/pulsar-broker"},
+		},
+		{
+			name: "privileged containers",
+			rule: policyRule{Rule: ruleDisallowPrivileged},
+			want: []string{"StatefulSet/pulsar-bookie"},
+		},
+		{
+			name: "statefulsets without a disruption budget",
+			rule: policyRule{Rule: ruleRequirePDB},
+			want: []string{"StatefulSet/pulsar-bookie"},
+		},
+		{
+			name: "storage classes",
+			rule: policyRule{Rule: ruleAllowedStorageClasses, Params: map[string]interface{}{
+				"classes": []interface{}{"local-path"},
+			}},
+			want: []string{"StatefulSet/pulsar-bookie", "StatefulSet/pulsar-bookie"},
+		},
+		{
+			name: "storage classes with the default class",
+			rule: policyRule{Rule: ruleAllowedStorageClasses, Params: map[string]interface{}{
+				"classes":      []interface{}{"local-path", "standard"},
+				"allowDefault": true,
+			}},
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.rule.Name, tt.rule.Severity = tt.rule.Rule, severityError
+			violations, err := evaluatePolicies([]*policyRule{&tt.rule}, objs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, v := range violations {
+				got = append(got, v.Kind+"/"+v.Name)
+			}
+			slices.Sort(got)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("expected violations %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestImageTag(t *testing.T) {
+	tests := []struct {
+		image  string
+		tag    string
+		pinned bool
+	}{
+		{"busybox", "", false},
+		{"busybox:1.36", "1.36", false},
+		{"apachepulsar/pulsar:latest", "latest", false},
+		{"registry.local:5000/pulsar", "", false},
+		{"registry.local:5000/pulsar:3.3.1", "3.3.1", false},
+		{"registry.local:5000/pulsar@sha256:0123456789abcdef", "", true},
+	}
+	for _, tt := range tests {
+		tag, pinned := imageTag(tt.image)
+		if tag != tt.tag || pinned != tt.pinned {
+			t.Errorf("%s: expected tag %q pinned %t, got %q %t", tt.image, tt.tag, tt.pinned, tag, pinned)
+		}
+	}
+}
+
+func TestLoadPolicies(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  string
+		wantErr bool
+	}{
+		{"defaults", "rules:\n  - rule: disallowLatestTag\n", false},
+		{"warning", "rules:\n  - name: tags\n    rule: disallowLatestTag\n    severity: warning\n", false},
+		{"unknown rule", "rules:\n  - rule: requireLabels\n", true},
+		{"unknown severity", "rules:\n  - rule: disallowLatestTag\n    severity: fatal\n", true},
+		{"unknown field", "rules:\n  - rule: disallowLatestTag\n    excludes: [Job/*]\n", true},
+		{"no rules", "rules: []\n", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, "policy.yaml"), []byte(tt.policy), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			rules, err := loadPolicies(dir)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %t, got '%v'", tt.wantErr, err)
+			}
+			for _, r := range rules {
+				if r.Name == "" || r.Severity == "" {
+					t.Errorf("expected a default name and severity, got %+v", r)
+				}
+			}
+		})
+	}
+}
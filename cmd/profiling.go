@@ -20,25 +20,59 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"runtime"
 	"runtime/pprof"
+	"runtime/trace"
 )
 
 var (
 	cpuProfileFile *os.File
 	cpuProfilePath string
 	memProfilePath string
+
+	// pprofDir is set with --pprof-dir.
+	pprofDir  string
+	traceFile *os.File
 )
 
+// Profiles written to --pprof-dir when the command ends, in addition to
+// cpu.pprof, trace.out and spans.json.
+var dirProfiles = []string{"heap", "goroutine", "block", "mutex"}
+
 func init() {
 	cpuProfilePath = os.Getenv("HELM_PPROF_CPU_PROFILE")
 	memProfilePath = os.Getenv("HELM_PPROF_MEM_PROFILE")
 }
 
 // startProfiling starts profiling CPU usage if HELM_PPROF_CPU_PROFILE is set
-// to a file path. It returns an error if the file could not be created or
-// CPU profiling could not be started.
-func startProfiling() error {
+// to a file path. With --pprof-dir it also enables block and mutex
+// profiling, starts an execution trace and records the spans of command, the
+// name of the running command. It returns an error if a file could not be
+// created or profiling could not be started.
+func startProfiling(command string) error {
+	if pprofDir != "" {
+		if err := os.MkdirAll(pprofDir, 0755); err != nil {
+			return fmt.Errorf("could not create profile directory: %w", err)
+		}
+		if cpuProfilePath == "" {
+			cpuProfilePath = filepath.Join(pprofDir, "cpu.pprof")
+		}
+		runtime.SetBlockProfileRate(1)
+		runtime.SetMutexProfileFraction(1)
+		tracer = newSpanRecorder(command)
+
+		var err error
+		if traceFile, err = os.Create(filepath.Join(pprofDir, "trace.out")); err != nil {
+			return fmt.Errorf("could not create trace: %w", err)
+		}
+		if err := trace.Start(traceFile); err != nil {
+			traceFile.Close()
+			traceFile = nil
+			return fmt.Errorf("could not start trace: %w", err)
+		}
+	}
+
 	if cpuProfilePath != "" {
 		var err error
 		cpuProfileFile, err = os.Create(cpuProfilePath)
@@ -56,7 +90,7 @@ func startProfiling() error {
 
 // stopProfiling stops profiling CPU and memory usage.
 // It writes memory profile to the file path specified in HELM_PPROF_MEM_PROFILE
-// environment variable.
+// environment variable, and the profiles, trace and spans to --pprof-dir.
 func stopProfiling() error {
 	errs := []error{}
 
@@ -70,22 +104,46 @@ func stopProfiling() error {
 		cpuProfileFile = nil
 	}
 
-	if memProfilePath != "" {
-		f, err := os.Create(memProfilePath)
-		if err != nil {
+	if traceFile != nil {
+		trace.Stop()
+		if err := traceFile.Close(); err != nil {
 			errs = append(errs, err)
 		}
-		defer f.Close()
+		traceFile = nil
+	}
 
-		runtime.GC() // get up-to-date statistics
-		if err := pprof.WriteHeapProfile(f); err != nil {
-			errs = append(errs, err)
+	if memProfilePath != "" {
+		errs = append(errs, writeProfile("heap", memProfilePath))
+	}
+
+	if pprofDir != "" {
+		for _, name := range dirProfiles {
+			errs = append(errs, writeProfile(name, filepath.Join(pprofDir, name+".pprof")))
 		}
 	}
 
+	if tracer != nil {
+		errs = append(errs, tracer.writeOTLP(filepath.Join(pprofDir, "spans.json")))
+		tracer = nil
+	}
+
 	if err := errors.Join(errs...); err != nil {
 		return fmt.Errorf("error(s) while stopping profiling: %w", err)
 	}
 
 	return nil
 }
+
+// writeProfile writes the named runtime profile to path.
+func writeProfile(name, path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if name == "heap" {
+		runtime.GC() // get up-to-date statistics
+	}
+	return pprof.Lookup(name).WriteTo(f, 0)
+}
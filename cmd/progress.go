@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"io"
+	"os"
+
+	"helm.sh/helm/v4/pkg/action"
+	"helm.sh/helm/v4/pkg/cli/output"
+	"helm.sh/helm/v4/pkg/kube"
+
+	"github.com/huangxiaofeng10047/go-cli-example/cmd/progress"
+)
+
+var noProgress bool
+
+// newProgress returns the progress display of a deploy command writing to
+// out. Bars are drawn on stderr, and only when both out and stderr are
+// terminals and the output is a table; otherwise it returns nil, which reports
+// nothing.
+func newProgress(out io.Writer, outfmt output.Format) *progress.Progress {
+	if noProgress || outfmt != output.Table || !isTerminal(out) || !isTerminal(os.Stderr) {
+		return nil
+	}
+	return progress.New(os.Stderr)
+}
+
+// reportKubeProgress makes the Kubernetes client of cfg start the apply, wait
+// and hook phases of p.
+func reportKubeProgress(cfg *action.Configuration, p *progress.Progress) {
+	if p == nil {
+		return
+	}
+	switch c := cfg.KubeClient.(type) {
+	case *tracingKubeClient:
+		c.progress = p
+	case *kube.Client:
+		cfg.KubeClient = &tracingKubeClient{Client: c, progress: p}
+	}
+}
@@ -0,0 +1,142 @@
+// Package progress shows the phases of a deploy and the readiness of its
+// components as progress bars on a terminal.
+package progress
+
+import (
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/cheggaaa/pb/v3"
+)
+
+const (
+	// phaseTemplate shows a spinner while the phase runs, then its result.
+	phaseTemplate = `{{with string . "mark"}}{{.}}{{else}}{{cycle . "⠋" "⠙" "⠹" "⠸" "⠼" "⠴" "⠦" "⠧" "⠇" "⠏"}}{{end}} {{string . "name"}} {{etime . "(%s)"}}`
+	// componentTemplate shows the ready and total replicas of a component.
+	componentTemplate = `  {{string . "name"}} {{counters . "%s/%s"}} {{bar . "[" "=" ">" " " "]"}} {{percent .}}`
+
+	markDone   = "✓"
+	markFailed = "✗"
+
+	// componentWidth aligns the names of components.
+	componentWidth = 24
+)
+
+// Progress draws the running phase and the components it waits for. The
+// methods of a nil Progress do nothing, so commands pass nil when the output is
+// not a terminal.
+type Progress struct {
+	w io.Writer
+
+	mu         sync.Mutex
+	pool       *pb.Pool
+	phase      *pb.ProgressBar
+	phaseName  string
+	components map[string]*pb.ProgressBar
+}
+
+// New returns a Progress drawing on w, which must be a terminal.
+func New(w io.Writer) *Progress {
+	return &Progress{w: w}
+}
+
+// Phase ends the running phase and starts the phase name. Starting the
+// running phase again does nothing.
+func (p *Progress) Phase(name string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.phase != nil && p.phaseName == name {
+		return
+	}
+	bar := pb.ProgressBarTemplate(phaseTemplate).New(1).Set("name", name)
+	// The new phase is added before the old one ends, as the pool stops
+	// drawing once all of its bars are finished.
+	if !p.add(bar) {
+		return
+	}
+	p.end(markDone)
+	p.phase, p.phaseName = bar, name
+}
+
+// Component sets the ready and total replicas of a component of the running
+// phase. Its bar is added on the first call.
+func (p *Progress) Component(name string, ready, total int) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	bar, ok := p.components[name]
+	if !ok {
+		bar = pb.ProgressBarTemplate(componentTemplate).New(total).Set("name", fmt.Sprintf("%-*s", componentWidth, name))
+		if !p.add(bar) {
+			return
+		}
+		if p.components == nil {
+			p.components = map[string]*pb.ProgressBar{}
+		}
+		p.components[name] = bar
+	}
+	bar.SetTotal(int64(total)).SetCurrent(int64(ready))
+}
+
+// Finish stops drawing at the end of a command. The running phase is marked
+// as failed if err is not nil.
+func (p *Progress) Finish(err error) {
+	if err != nil {
+		p.stop(markFailed)
+		return
+	}
+	p.stop(markDone)
+}
+
+// Stop marks the running phase as done and stops drawing, so that the
+// command can print to the terminal. A later phase draws below the bars drawn
+// so far.
+func (p *Progress) Stop() {
+	p.stop(markDone)
+}
+
+func (p *Progress) stop(mark string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.end(mark)
+	if p.pool != nil {
+		_ = p.pool.Stop()
+		p.pool = nil
+	}
+}
+
+// add draws bar, starting the pool if needed. It reports false if the
+// terminal cannot be drawn on.
+func (p *Progress) add(bar *pb.ProgressBar) bool {
+	if p.pool == nil {
+		pool := pb.NewPool()
+		pool.Output = p.w
+		if err := pool.Start(); err != nil {
+			return false
+		}
+		p.pool = pool
+	}
+	p.pool.Add(bar)
+	return true
+}
+
+// end finishes the running phase with mark and the bars of its components.
+func (p *Progress) end(mark string) {
+	if p.phase == nil {
+		return
+	}
+	p.phase.Set("mark", mark).SetCurrent(1).Finish()
+	for _, bar := range p.components {
+		bar.Finish()
+	}
+	p.phase, p.phaseName, p.components = nil, "", nil
+}
@@ -0,0 +1,290 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/huangxiaofeng10047/go-cli-example/cmd/i18n"
+	"helm.sh/helm/v4/pkg/cli"
+	"io"
+	"os"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gosuri/uitable"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+
+	"helm.sh/helm/v4/cmd/helm/require"
+	"helm.sh/helm/v4/pkg/action"
+	"helm.sh/helm/v4/pkg/cli/output"
+	"helm.sh/helm/v4/pkg/release"
+	helmtime "helm.sh/helm/v4/pkg/time"
+)
+
+// Actions of the recover command.
+const (
+	recoverReport     = "report"
+	recoverMarkFailed = "mark-failed"
+	recoverRollback   = "rollback"
+)
+
+// States of a manifest object compared with the cluster.
+const (
+	objectPresent = "present"
+	objectMissing = "missing"
+	objectForeign = "not-owned"
+	objectUnknown = "unknown"
+)
+
+// manifestObject is an object of the manifest of the pending revision.
+type manifestObject struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	State     string `json:"state"`
+	Message   string `json:"message,omitempty"`
+}
+
+type recoveryReport struct {
+	Name      string           `json:"name"`
+	Namespace string           `json:"namespace"`
+	Revision  int              `json:"revision"`
+	Status    string           `json:"status"`
+	Since     helmtime.Time    `json:"since"`
+	Stuck     bool             `json:"stuck"`
+	Objects   []manifestObject `json:"objects,omitempty"`
+	Action    string           `json:"action"`
+	Result    string           `json:"result"`
+}
+
+func newRecoverCmd(settings *cli.EnvSettings, cfg *action.Configuration, out io.Writer) *cobra.Command {
+	client := action.NewRollback(cfg)
+	var outfmt output.Format
+	var recoverAction string
+	var stuckAfter time.Duration
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "recover RELEASE_NAME",
+		Short: i18n.T("recover.short"),
+		Long:  i18n.T("recover.long"),
+		Args:  require.ExactArgs(1),
+		ValidArgsFunction: func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) != 0 {
+				return noMoreArgsComp()
+			}
+			return compListReleases(settings, toComplete, args, cfg)
+		},
+		RunE: func(_ *cobra.Command, args []string) error {
+			switch recoverAction {
+			case "", recoverReport, recoverMarkFailed, recoverRollback:
+			default:
+				return errors.New(i18n.T("recover.badAction", recoverAction, recoverReport, recoverMarkFailed, recoverRollback))
+			}
+
+			rel, err := cfg.Releases.Last(args[0])
+			if err != nil {
+				return err
+			}
+			report := &recoveryReport{
+				Name:      rel.Name,
+				Namespace: rel.Namespace,
+				Revision:  rel.Version,
+				Status:    rel.Info.Status.String(),
+				Since:     rel.Info.LastDeployed,
+				Action:    recoverReport,
+			}
+			if !rel.Info.Status.IsPending() {
+				report.Result = i18n.T("recover.notPending", rel.Version, rel.Info.Status)
+				return outfmt.Write(out, &recoverWriter{report})
+			}
+			report.Stuck = force || time.Since(rel.Info.LastDeployed.Time) >= stuckAfter
+			report.Objects = compareManifest(cfg, rel)
+
+			if !report.Stuck {
+				report.Result = i18n.T("recover.notStuck", rel.Version, rel.Info.Status, stuckAfter)
+				return outfmt.Write(out, &recoverWriter{report})
+			}
+
+			// The report is printed before asking, so only the result is
+			// printed afterwards.
+			asked := false
+			if recoverAction == "" && outfmt == output.Table && isTerminal(os.Stdin) && isTerminal(out) {
+				if err := outfmt.Write(out, &recoverWriter{report}); err != nil {
+					return err
+				}
+				if recoverAction, err = askRecoverAction(out, os.Stdin); err != nil {
+					return err
+				}
+				asked = true
+			}
+
+			switch recoverAction {
+			case recoverMarkFailed, recoverRollback:
+				report.Action = recoverAction
+				if err := markFailed(cfg, rel); err != nil {
+					return err
+				}
+				report.Result = i18n.T("recover.markedFailed", rel.Version)
+			default:
+				report.Result = i18n.T("recover.stuck", rel.Version, rel.Info.Status)
+			}
+
+			if recoverAction == recoverRollback {
+				if client.Version == 0 {
+					if client.Version, err = lastSuccessfulRevision(cfg, rel); err != nil {
+						return err
+					}
+				}
+				if err := client.Run(rel.Name); err != nil {
+					return errors.Wrap(err, i18n.T("recover.rollbackFailed", rel.Version, client.Version))
+				}
+				report.Result = i18n.T("recover.rolledBack", rel.Version, client.Version)
+			}
+			if asked {
+				_, err := fmt.Fprintln(out, report.summary())
+				return err
+			}
+			return outfmt.Write(out, &recoverWriter{report})
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVar(&recoverAction, "action", "", i18n.T("recover.flagAction", recoverReport, recoverMarkFailed, recoverRollback))
+	f.DurationVar(&stuckAfter, "stuck-after", 5*time.Minute, i18n.T("recover.flagStuckAfter"))
+	f.BoolVar(&force, "force", false, i18n.T("recover.flagForce"))
+	f.IntVar(&client.Version, "to-revision", 0, i18n.T("recover.flagToRevision"))
+	f.BoolVar(&client.Wait, "wait", false, i18n.T("recover.flagWait"))
+	f.DurationVar(&client.Timeout, "timeout", 300*time.Second, i18n.T("recover.flagTimeout"))
+	bindOutputFlag(cmd, &outfmt)
+
+	return cmd
+}
+
+// compareManifest looks up every object of the manifest of rel in the cluster.
+func compareManifest(cfg *action.Configuration, rel *release.Release) []manifestObject {
+	resources, err := cfg.KubeClient.Build(strings.NewReader(rel.Manifest), false)
+	if err != nil {
+		return []manifestObject{{Kind: "-", Name: "-", State: objectUnknown, Message: err.Error()}}
+	}
+
+	var objects []manifestObject
+	for _, info := range resources {
+		obj := manifestObject{
+			Kind:      info.Mapping.GroupVersionKind.Kind,
+			Name:      info.Name,
+			Namespace: info.Namespace,
+			State:     objectPresent,
+		}
+		if err := info.Get(); err != nil {
+			obj.State = objectUnknown
+			if apierrors.IsNotFound(err) {
+				obj.State = objectMissing
+			} else {
+				obj.Message = err.Error()
+			}
+		} else if accessor, err := meta.Accessor(info.Object); err == nil {
+			if owner := accessor.GetAnnotations()["meta.helm.sh/release-name"]; owner != rel.Name {
+				obj.State = objectForeign
+				if owner != "" {
+					obj.Message = i18n.T("recover.ownedBy", owner)
+				}
+			}
+		}
+		objects = append(objects, obj)
+	}
+	return objects
+}
+
+func markFailed(cfg *action.Configuration, rel *release.Release) error {
+	rel.SetStatus(release.StatusFailed, fmt.Sprintf("Marked as failed by recover: %s was interrupted", rel.Info.Status))
+	if err := cfg.Releases.Update(rel); err != nil {
+		return errors.Wrap(err, i18n.T("recover.markFailed", rel.Version))
+	}
+	return nil
+}
+
+// lastSuccessfulRevision returns the newest revision before rel that was
+// deployed.
+func lastSuccessfulRevision(cfg *action.Configuration, rel *release.Release) (int, error) {
+	hist, err := cfg.Releases.History(rel.Name)
+	if err != nil {
+		return 0, err
+	}
+	revision := 0
+	for _, r := range hist {
+		if r.Version < rel.Version && r.Version > revision && (r.Info.Status == release.StatusDeployed || r.Info.Status == release.StatusSuperseded) {
+			revision = r.Version
+		}
+	}
+	if revision == 0 {
+		return 0, errors.New(i18n.T("recover.noSuccessful", rel.Name))
+	}
+	return revision, nil
+}
+
+func askRecoverAction(out io.Writer, in io.Reader) (string, error) {
+	fmt.Fprint(out, "\n"+i18n.T("recover.ask"))
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "f":
+		return recoverMarkFailed, nil
+	case "r":
+		return recoverRollback, nil
+	}
+	return recoverReport, nil
+}
+
+// summary returns the result as a sentence.
+func (r *recoveryReport) summary() string {
+	if r.Result == "" {
+		return ""
+	}
+	first, size := utf8.DecodeRuneInString(r.Result)
+	return string(unicode.ToUpper(first)) + r.Result[size:]
+}
+
+type recoverWriter struct {
+	report *recoveryReport
+}
+
+func (w *recoverWriter) WriteTable(out io.Writer) error {
+	r := w.report
+	fmt.Fprintf(out, "NAME: %s\n", r.Name)
+	fmt.Fprintf(out, "NAMESPACE: %s\n", r.Namespace)
+	fmt.Fprintf(out, "REVISION: %d\n", r.Revision)
+	fmt.Fprintf(out, "STATUS: %s\n", r.Status)
+	if !r.Since.IsZero() {
+		fmt.Fprintf(out, "SINCE: %s (%s ago)\n", r.Since.Format(time.ANSIC), time.Since(r.Since.Time).Round(time.Second))
+	}
+	if len(r.Objects) > 0 {
+		fmt.Fprintln(out)
+		table := uitable.New()
+		table.AddRow("KIND", "NAME", "NAMESPACE", "STATE", "MESSAGE")
+		for _, o := range r.Objects {
+			table.AddRow(o.Kind, o.Name, orDash(o.Namespace), o.State, o.Message)
+		}
+		if err := output.EncodeTable(out, table); err != nil {
+			return err
+		}
+	}
+	if r.Result != "" {
+		fmt.Fprintf(out, "\n%s\n", r.summary())
+	}
+	return nil
+}
+
+func (w *recoverWriter) WriteJSON(out io.Writer) error {
+	return output.EncodeJSON(out, w.report)
+}
+
+func (w *recoverWriter) WriteYAML(out io.Writer) error {
+	return output.EncodeYAML(out, w.report)
+}
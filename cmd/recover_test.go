@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"helm.sh/helm/v4/pkg/action"
+	"helm.sh/helm/v4/pkg/release"
+	"helm.sh/helm/v4/pkg/storage"
+	"helm.sh/helm/v4/pkg/storage/driver"
+)
+
+func TestLastSuccessfulRevision(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []release.Status
+		wantRev  int
+		wantErr  bool
+	}{
+		{
+			name:     "previous revision deployed",
+			statuses: []release.Status{release.StatusSuperseded, release.StatusDeployed, release.StatusPendingUpgrade},
+			wantRev:  2,
+		},
+		{
+			name:     "skip failed revisions",
+			statuses: []release.Status{release.StatusSuperseded, release.StatusFailed, release.StatusFailed, release.StatusPendingUpgrade},
+			wantRev:  1,
+		},
+		{
+			name:     "skip rolled back and uninstalled revisions",
+			statuses: []release.Status{release.StatusSuperseded, release.StatusUninstalled, release.StatusPendingRollback, release.StatusPendingRollback},
+			wantRev:  1,
+		},
+		{
+			name:     "first install stuck",
+			statuses: []release.Status{release.StatusPendingInstall},
+			wantErr:  true,
+		},
+		{
+			name:     "no successful revision",
+			statuses: []release.Status{release.StatusFailed, release.StatusPendingUpgrade},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &action.Configuration{Releases: storage.Init(driver.NewMemory())}
+			var latest *release.Release
+			for i, status := range tt.statuses {
+				latest = release.Mock(&release.MockReleaseOptions{Name: "pulsar", Version: i + 1, Status: status})
+				if err := cfg.Releases.Create(latest); err != nil {
+					t.Fatal(err)
+				}
+			}
+			// A release of another name must not be picked.
+			other := release.Mock(&release.MockReleaseOptions{Name: "kafka", Version: 1, Status: release.StatusDeployed})
+			if err := cfg.Releases.Create(other); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := lastSuccessfulRevision(cfg, latest)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %t, got '%v'", tt.wantErr, err)
+			}
+			if got != tt.wantRev {
+				t.Errorf("expected revision %d, got %d", tt.wantRev, got)
+			}
+		})
+	}
+}
+
+func TestAskRecoverAction(t *testing.T) {
+	tests := []struct {
+		answer string
+		want   string
+	}{
+		{"f\n", recoverMarkFailed},
+		{" R \n", recoverRollback},
+		{"\n", recoverReport},
+		{"yes\n", recoverReport},
+		{"r", recoverRollback},
+		{"", recoverReport},
+	}
+	for _, tt := range tests {
+		got, err := askRecoverAction(io.Discard, strings.NewReader(tt.answer))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("%q: expected %s, got %s", tt.answer, tt.want, got)
+		}
+	}
+}
@@ -9,27 +9,30 @@ import (
 	"github.com/spf13/cobra"
 
 	"helm.sh/helm/v4/cmd/helm/require"
+	"helm.sh/helm/v4/pkg/action"
 )
 
 var repoHelm = `
 This command consists of multiple subcommands to interact with chart repositories.
 
-It can be used to add, remove, list, and index chart repositories.
+It can be used to add, remove, list, and index chart repositories, and to serve
+local chart archives as a repository.
 `
 
-func newRepoCmd(settings *cli.EnvSettings, out io.Writer) *cobra.Command {
+func newRepoCmd(settings *cli.EnvSettings, out io.Writer, debug action.DebugLog) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "repo add|remove|list|index|update [ARGS]",
-		Short: "add, list, remove, update, and index chart repositories",
+		Use:   "repo add|remove|list|index|update|serve [ARGS]",
+		Short: "add, list, remove, update, index, and serve chart repositories",
 		Long:  repoHelm,
 		Args:  require.NoArgs,
 	}
 
-	//cmd.AddCommand(newRepoAddCmd(out))
+	cmd.AddCommand(newRepoAddCmd(settings, out))
 	cmd.AddCommand(newRepoListCmd(settings, out))
 	//cmd.AddCommand(newRepoRemoveCmd(out))
 	//cmd.AddCommand(newRepoIndexCmd(out))
-	//cmd.AddCommand(newRepoUpdateCmd(out))
+	cmd.AddCommand(newRepoUpdateCmd(settings, out))
+	cmd.AddCommand(newRepoServeCmd(out, debug))
 
 	return cmd
 }
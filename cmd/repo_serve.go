@@ -0,0 +1,398 @@
+package cmd
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	tlsutil "github.com/huangxiaofeng10047/go-cli-example/cmd/helm"
+	"github.com/huangxiaofeng10047/go-cli-example/cmd/i18n"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+	"github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+
+	"helm.sh/helm/v4/cmd/helm/require"
+	"helm.sh/helm/v4/pkg/action"
+	"helm.sh/helm/v4/pkg/provenance"
+	"helm.sh/helm/v4/pkg/registry"
+	"helm.sh/helm/v4/pkg/repo"
+)
+
+func newRepoServeCmd(out io.Writer, debug action.DebugLog) *cobra.Command {
+	var address, url, certFile, keyFile, caFile string
+
+	cmd := &cobra.Command{
+		Use:   "serve DIR",
+		Short: i18n.T("repoServe.short"),
+		Long:  i18n.T("repoServe.long"),
+		Args:  require.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			dir := filepath.Clean(args[0])
+			if fi, err := os.Stat(dir); err != nil {
+				return err
+			} else if !fi.IsDir() {
+				return errors.New(i18n.T("repoServe.notDir", dir))
+			}
+
+			tlsEnabled := certFile != "" || keyFile != ""
+			if tlsEnabled && (certFile == "" || keyFile == "") {
+				return errors.New(i18n.T("repoServe.certKey"))
+			}
+			if caFile != "" && !tlsEnabled {
+				return errors.New(i18n.T("repoServe.caFile"))
+			}
+
+			l, err := net.Listen("tcp", address)
+			if err != nil {
+				return err
+			}
+			scheme := "http"
+			if tlsEnabled {
+				scheme = "https"
+			}
+			if url == "" {
+				url = scheme + "://" + l.Addr().String()
+			}
+
+			srv := &http.Server{
+				Handler:           newChartRepoServer(dir, url, debug),
+				ReadHeaderTimeout: 10 * time.Second,
+			}
+			if tlsEnabled {
+				conf, err := tlsutil.NewTLSConfig(
+					tlsutil.WithCertKeyPairFiles(certFile, keyFile),
+					tlsutil.WithCAFile(caFile),
+				)
+				if err != nil {
+					l.Close()
+					return fmt.Errorf("%s: %w", i18n.T("repoServe.tlsConfig"), err)
+				}
+				// The CA verifies clients here, not the servers we connect to.
+				if conf.RootCAs != nil {
+					conf.ClientCAs, conf.RootCAs = conf.RootCAs, nil
+					conf.ClientAuth = tls.RequireAndVerifyClientCert
+				}
+				srv.TLSConfig = conf
+			}
+
+			fmt.Fprintln(out, i18n.T("repoServe.serving", dir))
+			fmt.Fprintln(out, i18n.T("repoServe.repository", url))
+			fmt.Fprintln(out, i18n.T("repoServe.registry", l.Addr().String()))
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			go func() {
+				<-ctx.Done()
+				shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+				srv.Shutdown(shutdown)
+			}()
+
+			if tlsEnabled {
+				err = srv.ServeTLS(l, "", "")
+			} else {
+				err = srv.Serve(l)
+			}
+			if errors.Is(err, http.ErrServerClosed) {
+				return nil
+			}
+			return err
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVar(&address, "address", "127.0.0.1:8879", i18n.T("repoServe.flagAddress"))
+	f.StringVar(&url, "url", "", i18n.T("repoServe.flagURL"))
+	f.StringVar(&certFile, "cert-file", "", i18n.T("repoServe.flagCertFile"))
+	f.StringVar(&keyFile, "key-file", "", i18n.T("repoServe.flagKeyFile"))
+	f.StringVar(&caFile, "ca-file", "", i18n.T("repoServe.flagCAFile"))
+
+	return cmd
+}
+
+// ociBlob is content served by the OCI endpoint, either from memory or from
+// a chart archive on disk.
+type ociBlob struct {
+	mediaType string
+	size      int64
+	data      []byte
+	path      string
+}
+
+// chartRepoServer serves a directory of chart archives as a chart repository
+// and as a read-only OCI registry.
+type chartRepoServer struct {
+	dir     string
+	baseURL string
+	debug   action.DebugLog
+	files   http.Handler
+
+	mu          sync.Mutex
+	fingerprint string
+	indexYAML   []byte
+	// tags maps chart names to tags to manifest digests.
+	tags  map[string]map[string]digest.Digest
+	blobs map[digest.Digest]*ociBlob
+}
+
+func newChartRepoServer(dir, baseURL string, debug action.DebugLog) *chartRepoServer {
+	return &chartRepoServer{
+		dir:     dir,
+		baseURL: baseURL,
+		debug:   debug,
+		files:   http.FileServer(http.Dir(dir)),
+	}
+}
+
+func (s *chartRepoServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.debug("%s %s", r.Method, r.URL.Path)
+	switch {
+	case r.URL.Path == "/index.yaml":
+		if err := s.refresh(); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		s.mu.Lock()
+		b := s.indexYAML
+		s.mu.Unlock()
+		w.Header().Set("Content-Type", "application/x-yaml")
+		w.Write(b)
+	case r.URL.Path == "/v2" || strings.HasPrefix(r.URL.Path, "/v2/"):
+		s.serveOCI(w, r)
+	default:
+		s.files.ServeHTTP(w, r)
+	}
+}
+
+// chartArchives returns the archives that 'repo.IndexDirectory' indexes.
+func chartArchives(dir string) ([]string, error) {
+	archives, err := filepath.Glob(filepath.Join(dir, "*.tgz"))
+	if err != nil {
+		return nil, err
+	}
+	more, err := filepath.Glob(filepath.Join(dir, "*", "*.tgz"))
+	if err != nil {
+		return nil, err
+	}
+	return append(archives, more...), nil
+}
+
+// refresh rebuilds the index and the OCI artifacts when the archives changed.
+func (s *chartRepoServer) refresh() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	archives, err := chartArchives(s.dir)
+	if err != nil {
+		return err
+	}
+	var fp strings.Builder
+	for _, a := range archives {
+		fi, err := os.Stat(a)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&fp, "%s:%d:%d\n", a, fi.Size(), fi.ModTime().UnixNano())
+	}
+	if s.indexYAML != nil && fp.String() == s.fingerprint {
+		return nil
+	}
+
+	idx, err := repo.IndexDirectory(s.dir, s.baseURL)
+	if err != nil {
+		return errors.Wrap(err, i18n.T("repoServe.index", s.dir))
+	}
+	idx.SortEntries()
+	indexYAML, err := yaml.Marshal(idx)
+	if err != nil {
+		return err
+	}
+
+	// The index digest of a chart is the sha256 of its archive, which is
+	// also the digest of its OCI layer.
+	paths := map[string]string{}
+	for _, a := range archives {
+		d, err := provenance.DigestFile(a)
+		if err != nil {
+			return err
+		}
+		paths[d] = a
+	}
+	tags := map[string]map[string]digest.Digest{}
+	blobs := map[digest.Digest]*ociBlob{}
+	for name, versions := range idx.Entries {
+		tags[name] = map[string]digest.Digest{}
+		for _, cv := range versions {
+			archive, ok := paths[cv.Digest]
+			if !ok {
+				continue
+			}
+			m, err := ociChartArtifact(cv, archive, blobs)
+			if err != nil {
+				return errors.Wrap(err, i18n.T("repoServe.artifact", archive))
+			}
+			tags[name][strings.ReplaceAll(cv.Version, "+", "_")] = m
+		}
+	}
+
+	s.fingerprint, s.indexYAML, s.tags, s.blobs = fp.String(), indexYAML, tags, blobs
+	s.debug("indexed %d chart archive(s) in %s", len(archives), s.dir)
+	return nil
+}
+
+// ociChartArtifact adds the config, layers and manifest of a chart to blobs,
+// laid out the way 'helm push' does, and returns the manifest digest.
+func ociChartArtifact(cv *repo.ChartVersion, archive string, blobs map[digest.Digest]*ociBlob) (digest.Digest, error) {
+	config, err := json.Marshal(cv.Metadata)
+	if err != nil {
+		return "", err
+	}
+	configDesc := addBlob(blobs, &ociBlob{mediaType: registry.ConfigMediaType, size: int64(len(config)), data: config})
+
+	fi, err := os.Stat(archive)
+	if err != nil {
+		return "", err
+	}
+	chartDigest := digest.NewDigestFromEncoded(digest.SHA256, cv.Digest)
+	blobs[chartDigest] = &ociBlob{mediaType: registry.ChartLayerMediaType, size: fi.Size(), path: archive}
+	layers := []ocispec.Descriptor{{MediaType: registry.ChartLayerMediaType, Digest: chartDigest, Size: fi.Size()}}
+
+	if prov, err := os.ReadFile(archive + ".prov"); err == nil {
+		layers = append(layers, addBlob(blobs, &ociBlob{mediaType: registry.ProvLayerMediaType, size: int64(len(prov)), data: prov}))
+	}
+
+	manifest, err := json.Marshal(ocispec.Manifest{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageManifest,
+		Config:    configDesc,
+		Layers:    layers,
+		Annotations: map[string]string{
+			ocispec.AnnotationTitle:   cv.Name,
+			ocispec.AnnotationVersion: cv.Version,
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	return addBlob(blobs, &ociBlob{mediaType: ocispec.MediaTypeImageManifest, size: int64(len(manifest)), data: manifest}).Digest, nil
+}
+
+func addBlob(blobs map[digest.Digest]*ociBlob, b *ociBlob) ocispec.Descriptor {
+	d := digest.FromBytes(b.data)
+	blobs[d] = b
+	return ocispec.Descriptor{MediaType: b.mediaType, Digest: d, Size: b.size}
+}
+
+// serveOCI implements the pull side of the OCI distribution API: the
+// version check, tag listing, and manifest and blob downloads.
+func (s *chartRepoServer) serveOCI(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		ociError(w, http.StatusMethodNotAllowed, "UNSUPPORTED", "the registry is read-only")
+		return
+	}
+	p := strings.Trim(strings.TrimPrefix(r.URL.Path, "/v2"), "/")
+	if p == "" {
+		w.Header().Set("Docker-Distribution-API-Version", "registry/2.0")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte("{}"))
+		return
+	}
+	if err := s.refresh(); err != nil {
+		ociError(w, http.StatusInternalServerError, "UNKNOWN", err.Error())
+		return
+	}
+	s.mu.Lock()
+	tags, blobs := s.tags, s.blobs
+	s.mu.Unlock()
+
+	switch {
+	case strings.HasSuffix(p, "/tags/list"):
+		name := strings.TrimSuffix(p, "/tags/list")
+		versions, ok := tags[path.Base(name)]
+		if !ok {
+			ociError(w, http.StatusNotFound, "NAME_UNKNOWN", fmt.Sprintf("repository %s not found", name))
+			return
+		}
+		list := make([]string, 0, len(versions))
+		for t := range versions {
+			list = append(list, t)
+		}
+		sort.Strings(list)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"name": name, "tags": list})
+	case strings.Contains(p, "/manifests/"):
+		i := strings.LastIndex(p, "/manifests/")
+		name, ref := p[:i], p[i+len("/manifests/"):]
+		d, err := digest.Parse(ref)
+		if err != nil {
+			var ok bool
+			if d, ok = tags[path.Base(name)][ref]; !ok {
+				ociError(w, http.StatusNotFound, "MANIFEST_UNKNOWN", fmt.Sprintf("manifest %s:%s not found", name, ref))
+				return
+			}
+		}
+		b, ok := blobs[d]
+		if !ok || b.mediaType != ocispec.MediaTypeImageManifest {
+			ociError(w, http.StatusNotFound, "MANIFEST_UNKNOWN", fmt.Sprintf("manifest %s@%s not found", name, ref))
+			return
+		}
+		s.writeBlob(w, r, d, b)
+	case strings.Contains(p, "/blobs/"):
+		ref := p[strings.LastIndex(p, "/blobs/")+len("/blobs/"):]
+		d, err := digest.Parse(ref)
+		b, ok := blobs[d]
+		if err != nil || !ok {
+			ociError(w, http.StatusNotFound, "BLOB_UNKNOWN", fmt.Sprintf("blob %s not found", ref))
+			return
+		}
+		s.writeBlob(w, r, d, b)
+	default:
+		ociError(w, http.StatusNotFound, "UNSUPPORTED", fmt.Sprintf("unsupported endpoint %s", r.URL.Path))
+	}
+}
+
+func (s *chartRepoServer) writeBlob(w http.ResponseWriter, r *http.Request, d digest.Digest, b *ociBlob) {
+	w.Header().Set("Content-Type", b.mediaType)
+	w.Header().Set("Content-Length", strconv.FormatInt(b.size, 10))
+	w.Header().Set("Docker-Content-Digest", d.String())
+	if r.Method == http.MethodHead {
+		return
+	}
+	if b.path == "" {
+		w.Write(b.data)
+		return
+	}
+	f, err := os.Open(b.path)
+	if err != nil {
+		s.debug("unable to open %s: %s", b.path, err)
+		return
+	}
+	defer f.Close()
+	io.Copy(w, f)
+}
+
+// ociError writes an error response in the format of the distribution API.
+func ociError(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"errors": []map[string]string{{"code": code, "message": message}},
+	})
+}
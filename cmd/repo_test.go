@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"helm.sh/helm/v4/pkg/action"
+	"helm.sh/helm/v4/pkg/chart/v2/loader"
+	chartutil "helm.sh/helm/v4/pkg/chart/v2/util"
+	"helm.sh/helm/v4/pkg/cli"
+	"helm.sh/helm/v4/pkg/helmpath"
+	"helm.sh/helm/v4/pkg/repo"
+)
+
+// TestRepoAddServed adds a repository served by 'repo serve' and checks that
+// it lands in the repositories file and its index in the cache.
+func TestRepoAddServed(t *testing.T) {
+	isolateEnv(t, "")
+
+	dir := t.TempDir()
+	ch, err := loader.Load(filepath.Join("testdata", "testcharts", "mini"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := chartutil.Save(ch, dir); err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewUnstartedServer(nil)
+	srv.Config.Handler = newChartRepoServer(dir, "http://"+srv.Listener.Addr().String(), func(string, ...interface{}) {})
+	srv.Start()
+	defer srv.Close()
+
+	tests := []struct {
+		cmd  string
+		want string
+	}{
+		{"repo add local " + srv.URL, `"local" has been added to your repositories`},
+		{"repo add local " + srv.URL, `"local" already exists with the same configuration, skipping`},
+		{"repo update local", `Successfully got an update from the "local" chart repository`},
+	}
+	for _, tt := range tests {
+		out, err := executeCommand(tt.cmd)
+		if err != nil {
+			t.Fatalf("%s: %s", tt.cmd, err)
+		}
+		if !strings.Contains(out, tt.want) {
+			t.Errorf("%s: expected output to contain %q, got %q", tt.cmd, tt.want, out)
+		}
+	}
+
+	settings := cli.New()
+	f, err := repo.LoadFile(settings.RepositoryConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e := f.Get("local"); e == nil || e.URL != srv.URL {
+		t.Fatalf("expected repository local with URL %s in %s, got %v", srv.URL, settings.RepositoryConfig, e)
+	}
+	idx, err := repo.LoadIndexFile(filepath.Join(settings.RepositoryCache, helmpath.CacheIndexFile("local")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !idx.Has(ch.Name(), ch.Metadata.Version) {
+		t.Errorf("expected %s %s in the cached index", ch.Name(), ch.Metadata.Version)
+	}
+
+	if _, err := executeCommand("repo update missing"); err == nil {
+		t.Error("expected an error updating an unknown repository")
+	}
+}
+
+func executeCommand(cmd string) (string, error) {
+	args := strings.Fields(cmd)
+	buf := new(bytes.Buffer)
+	root, err := NewRootCmd(cli.New(), new(action.Configuration), buf, args, func(string, ...interface{}) {})
+	if err != nil {
+		return "", err
+	}
+	root.SetOut(buf)
+	root.SetErr(io.Discard)
+	root.SetArgs(args)
+	_, err = root.ExecuteC()
+	return buf.String(), err
+}
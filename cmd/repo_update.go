@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"helm.sh/helm/v4/cmd/helm/require"
+	"helm.sh/helm/v4/pkg/cli"
+	"helm.sh/helm/v4/pkg/getter"
+	"helm.sh/helm/v4/pkg/repo"
+)
+
+const updateDesc = `
+Update gets the latest information about charts from the respective chart repositories.
+Information is cached locally, where it is used by commands like 'helm search'.
+
+You can optionally specify a list of repositories you want to update.
+	$ helm repo update <repo_name> ...
+To update all the repositories, use 'helm repo update'.
+`
+
+var errNoRepositories = errors.New("no repositories found. You must add one before updating")
+
+type repoUpdateOptions struct {
+	update               func([]*repo.ChartRepository, io.Writer, bool) error
+	repoFile             string
+	repoCache            string
+	names                []string
+	failOnRepoUpdateFail bool
+}
+
+func newRepoUpdateCmd(settings *cli.EnvSettings, out io.Writer) *cobra.Command {
+	o := &repoUpdateOptions{update: updateCharts}
+
+	cmd := &cobra.Command{
+		Use:     "update [REPO1 [REPO2 ...]]",
+		Aliases: []string{"up"},
+		Short:   "update information of available charts locally from chart repositories",
+		Long:    updateDesc,
+		Args:    require.MinimumNArgs(0),
+		ValidArgsFunction: func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return compListRepos(settings, toComplete, args), cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(_ *cobra.Command, args []string) error {
+			o.repoFile = settings.RepositoryConfig
+			o.repoCache = settings.RepositoryCache
+			o.names = args
+			return o.run(settings, out)
+		},
+	}
+
+	f := cmd.Flags()
+
+	// Adding this flag for Helm 3 as stop gap functionality for https://github.com/helm/helm/issues/10016.
+	// This should be deprecated in Helm 4 by update to the behaviour of `helm repo update` command.
+	f.BoolVar(&o.failOnRepoUpdateFail, "fail-on-repo-update-fail", false, "update fails if any of the repository updates fail")
+
+	return cmd
+}
+
+func (o *repoUpdateOptions) run(settings *cli.EnvSettings, out io.Writer) error {
+	f, err := repo.LoadFile(o.repoFile)
+	switch {
+	case isNotExist(err):
+		return errNoRepositories
+	case err != nil:
+		return errors.Wrapf(err, "failed loading file: %s", o.repoFile)
+	case len(f.Repositories) == 0:
+		return errNoRepositories
+	}
+
+	var repos []*repo.ChartRepository
+	updateAllRepos := len(o.names) == 0
+
+	if !updateAllRepos {
+		// Fail early if the user specified an invalid repo to update
+		if err := checkRequestedRepos(o.names, f.Repositories); err != nil {
+			return err
+		}
+	}
+
+	for _, cfg := range f.Repositories {
+		if updateAllRepos || isRepoRequested(cfg.Name, o.names) {
+			r, err := repo.NewChartRepository(cfg, getter.All(settings))
+			if err != nil {
+				return err
+			}
+			if o.repoCache != "" {
+				r.CachePath = o.repoCache
+			}
+			repos = append(repos, r)
+		}
+	}
+
+	return o.update(repos, out, o.failOnRepoUpdateFail)
+}
+
+func updateCharts(repos []*repo.ChartRepository, out io.Writer, failOnRepoUpdateFail bool) error {
+	fmt.Fprintln(out, "Hang tight while we grab the latest from your chart repositories...")
+	var wg sync.WaitGroup
+	var repoFailList []string
+	for _, re := range repos {
+		wg.Add(1)
+		go func(re *repo.ChartRepository) {
+			defer wg.Done()
+			if _, err := re.DownloadIndexFile(); err != nil {
+				fmt.Fprintf(out, "...Unable to get an update from the %q chart repository (%s):\n\t%s\n", re.Config.Name, re.Config.URL, err)
+				repoFailList = append(repoFailList, re.Config.URL)
+			} else {
+				fmt.Fprintf(out, "...Successfully got an update from the %q chart repository\n", re.Config.Name)
+			}
+		}(re)
+	}
+	wg.Wait()
+
+	if len(repoFailList) > 0 && failOnRepoUpdateFail {
+		return fmt.Errorf("Failed to update the following repositories: %s",
+			repoFailList)
+	}
+
+	fmt.Fprintln(out, "Update Complete. ⎈Happy Helming!⎈")
+	return nil
+}
+
+func checkRequestedRepos(requestedRepos []string, validRepos []*repo.Entry) error {
+	for _, requestedRepo := range requestedRepos {
+		found := false
+		for _, repo := range validRepos {
+			if requestedRepo == repo.Name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return errors.Errorf("no repositories found matching '%s'.  Nothing will be updated", requestedRepo)
+		}
+	}
+	return nil
+}
+
+func isRepoRequested(repoName string, requestedRepos []string) bool {
+	return slices.Contains(requestedRepos, repoName)
+}
@@ -8,10 +8,10 @@ import (
 	"fmt"
 	installevent "github.com/huangxiaofeng10047/go-cli-example/cmd/event"
 	tlsutil "github.com/huangxiaofeng10047/go-cli-example/cmd/helm"
+	"github.com/huangxiaofeng10047/go-cli-example/cmd/i18n"
 	"helm.sh/helm/v4/pkg/cli"
 	"io"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/client-go/tools/clientcmd"
 	"log"
 	"net/http"
 	"os"
@@ -20,6 +20,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"helm.sh/helm/v4/pkg/action"
+	"helm.sh/helm/v4/pkg/kube"
 	"helm.sh/helm/v4/pkg/registry"
 	"helm.sh/helm/v4/pkg/repo"
 )
@@ -33,14 +34,11 @@ func Execute() {
 	//}
 }
 
-var (
-	testConfig = &installevent.TestConfig{
-		Schema: "http",
-		Host:   "127.0.0.1",
-		Port:   8080,
-	}
-	globalEvent *installevent.InstallEvent
-)
+var testConfig = &installevent.TestConfig{
+	Schema: "http",
+	Host:   "127.0.0.1",
+	Port:   8080,
+}
 var globalUsage = `The Kubernetes package manager
 Common actions for Helm:
 
@@ -66,6 +64,12 @@ Environment variables:
 | $HELM_REGISTRY_CONFIG              | set the path to the registry config file.                                                                  |
 | $HELM_REPOSITORY_CACHE             | set the path to the repository cache directory                                                             |
 | $HELM_REPOSITORY_CONFIG            | set the path to the repositories file.                                                                     |
+| $HELM_PPROF_CPU_PROFILE            | write a CPU profile to this file. See also --pprof-dir.                                                    |
+| $HELM_PPROF_MEM_PROFILE            | write a heap profile to this file when the command ends.                                                   |
+| $HELM_TEST_CASE_TOKEN              | set the bearer token of the test service. See also --test-case-token-file.                                 |
+| $HELM_TEST_CASE_PASSWORD           | set the basic auth password of the test service. See also --test-case-password-file.                       |
+| $HELM_TEST_CASE_SIGNING_KEY        | set the HMAC key that signs the requests to the test service. See also --test-case-signing-key-file.       |
+| $HELM_SCENARIO                     | run against the scenario fixture in this file instead of a cluster and test service.                       |
 | $KUBECONFIG                        | set an alternative Kubernetes configuration file (default "~/.kube/config")                                |
 | $HELM_KUBEAPISERVER                | set the Kubernetes API Server Endpoint for authentication                                                  |
 | $HELM_KUBECAFILE                   | set the Kubernetes certificate authority file.                                                             |
@@ -94,30 +98,55 @@ By default, the default directories depend on the Operating System. The defaults
 `
 
 func NewRootCmd(settings *cli.EnvSettings, actionConfig *action.Configuration, out io.Writer, args []string, debug action.DebugLog) (*cobra.Command, error) {
-	globalEvent = installevent.NewInstallEvent(testConfig)
 	cmd := &cobra.Command{
 		Use:          "helm",
 		Short:        "The Helm package manager for Kubernetes.",
 		Long:         globalUsage,
 		SilenceUsage: true,
-		PersistentPreRun: func(_ *cobra.Command, _ []string) {
-			globalEvent = installevent.NewInstallEvent(testConfig)
-			if err := startProfiling(); err != nil {
+		PersistentPreRun: func(cmd *cobra.Command, _ []string) {
+			if err := startProfiling(cmd.CommandPath()); err != nil {
 				log.Printf("Warning: Failed to start profiling: %v", err)
 			}
-		},
-		PersistentPostRun: func(_ *cobra.Command, _ []string) {
-			if err := stopProfiling(); err != nil {
-				log.Printf("Warning: Failed to stop profiling: %v", err)
+			if c, ok := actionConfig.KubeClient.(*kube.Client); ok && tracer != nil {
+				actionConfig.KubeClient = &tracingKubeClient{Client: c}
 			}
 		},
 	}
+	// Profiles are also written when the command fails, which PersistentPostRun
+	// would skip.
+	cobra.OnFinalize(func() {
+		if err := stopProfiling(); err != nil {
+			log.Printf("Warning: Failed to stop profiling: %v", err)
+		}
+	})
 	flags := cmd.PersistentFlags()
-	flags.StringVar(&testConfig.Schema, "test-case-schema", "http", "测试用例协议")
-	flags.StringVar(&testConfig.Host, "test-case-host", "127.0.0.1", "测试用例主机地址")
-	flags.IntVar(&testConfig.Port, "test-case-port", 8080, "测试用例端口")
+	flags.StringVar(&testConfig.Schema, "test-case-schema", "http", "")
+	flags.StringVar(&testConfig.Host, "test-case-host", "127.0.0.1", "")
+	flags.IntVar(&testConfig.Port, "test-case-port", 8080, "")
+	flags.StringVar(&testConfig.Endpoint, "test-endpoint", "", "")
+	flags.StringVar(&testConfig.APIVersion, "test-api-version", installevent.APIVersionAuto, "")
+	flags.StringVar(&testConfig.TriggerRoute, "test-trigger-route", "", "")
+	flags.StringVar(&testConfig.StatusRoute, "test-status-route", "", "")
+	flags.StringSliceVar(&testConfig.Suites, "test-suite", nil, "")
+	flags.StringVar(&testConfig.CAFile, "test-case-ca-file", "", "")
+	flags.StringVar(&testConfig.CertFile, "test-case-cert-file", "", "")
+	flags.StringVar(&testConfig.KeyFile, "test-case-key-file", "", "")
+	flags.BoolVar(&testConfig.InsecureSkipTLSVerify, "test-case-insecure-skip-tls-verify", false, "")
+	flags.StringVar(&testConfig.TokenFile, "test-case-token-file", "", "")
+	flags.StringVar(&testConfig.Username, "test-case-username", "", "")
+	flags.StringVar(&testConfig.PasswordFile, "test-case-password-file", "", "")
+	flags.StringVar(&testConfig.SigningKeyFile, "test-case-signing-key-file", "", "")
+	flags.StringVar(&testBackend, "test-backend", "", "")
+	var lang string
+	flags.StringVar(&lang, "lang", "", "")
+	flags.BoolVar(&noProgress, "no-progress", false, "")
+	var profileName, profilePath string
+	flags.StringVar(&profileName, "profile", "", "")
+	flags.StringVar(&profilePath, "profile-file", defaultProfileFile, "")
+	flags.StringVar(&pprofDir, "pprof-dir", "", "")
 	settings.AddFlags(flags)
 	addKlogFlags(flags)
+	addLoggingFlags(flags)
 
 	// Setup shell completion for the namespace flag
 	err := cmd.RegisterFlagCompletionFunc("namespace", func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
@@ -146,13 +175,7 @@ func NewRootCmd(settings *cli.EnvSettings, actionConfig *action.Configuration, o
 	err = cmd.RegisterFlagCompletionFunc("kube-context", func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
 		cobra.CompDebugln("About to get the different kube-contexts", settings.Debug)
 
-		loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
-		if len(settings.KubeConfig) > 0 {
-			loadingRules = &clientcmd.ClientConfigLoadingRules{ExplicitPath: settings.KubeConfig}
-		}
-		if config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
-			loadingRules,
-			&clientcmd.ConfigOverrides{}).RawConfig(); err == nil {
+		if config, err := loadKubeConfig(settings); err == nil {
 			comps := []string{}
 			for name, context := range config.Contexts {
 				comps = append(comps, fmt.Sprintf("%s\t%s", name, context.Cluster))
@@ -173,6 +196,62 @@ func NewRootCmd(settings *cli.EnvSettings, actionConfig *action.Configuration, o
 	flags.ParseErrorsWhitelist.UnknownFlags = true
 	flags.Parse(args)
 
+	// The language must be known before the subcommands are created, as their
+	// help texts are translated.
+	language := i18n.Detect()
+	if lang != "" {
+		l, ok := i18n.Parse(lang)
+		if !ok {
+			return nil, fmt.Errorf("%s", i18n.T("error.unsupportedLang", lang, "en, zh"))
+		}
+		language = l
+	}
+	i18n.SetLanguage(language)
+	for name, key := range map[string]string{
+		"lang":                               "flag.lang",
+		"no-progress":                        "flag.noProgress",
+		"test-case-schema":                   "flag.testCaseSchema",
+		"test-case-host":                     "flag.testCaseHost",
+		"test-case-port":                     "flag.testCasePort",
+		"test-endpoint":                      "flag.testEndpoint",
+		"test-api-version":                   "flag.testAPIVersion",
+		"test-trigger-route":                 "flag.testTriggerRoute",
+		"test-status-route":                  "flag.testStatusRoute",
+		"test-suite":                         "flag.testSuite",
+		"test-case-ca-file":                  "flag.testCaseCAFile",
+		"test-case-cert-file":                "flag.testCaseCertFile",
+		"test-case-key-file":                 "flag.testCaseKeyFile",
+		"test-case-insecure-skip-tls-verify": "flag.testCaseInsecure",
+		"test-case-token-file":               "flag.testCaseTokenFile",
+		"test-case-username":                 "flag.testCaseUsername",
+		"test-case-password-file":            "flag.testCasePasswordFile",
+		"test-case-signing-key-file":         "flag.testCaseSigningKeyFile",
+		"test-backend":                       "flag.testBackend",
+		"profile":                            "flag.profile",
+		"profile-file":                       "flag.profileFile",
+		"pprof-dir":                          "flag.pprofDir",
+		"log-level":                          "flag.logLevel",
+		"log-format":                         "flag.logFormat",
+	} {
+		flags.Lookup(name).Usage = i18n.T(key)
+	}
+
+	// Logs go to stderr, so that the output of '-o json' stays parsable.
+	if err := setupLogging(os.Stderr, flags, settings.Debug); err != nil {
+		return nil, err
+	}
+
+	// The profile must be applied before cobra.OnInitialize initializes the
+	// action configuration, as it may change the namespace and kube context.
+	if profileName != "" {
+		p, err := loadProfile(profilePath, profileName)
+		if err != nil {
+			return nil, err
+		}
+		p.applySettings(settings, flags)
+		activeProfile = p
+	}
+
 	registryClient, err := newDefaultRegistryClient(settings, false, "", "")
 	if err != nil {
 		return nil, err
@@ -181,16 +260,25 @@ func NewRootCmd(settings *cli.EnvSettings, actionConfig *action.Configuration, o
 
 	// Add subcommands
 
-	// 初始化全局 event
-	//globalEvent = installevent.NewInstallEvent(testConfig)
 	cmd.AddCommand(
 		// chart commands
 		newDependencyCmd(settings, actionConfig, out),
+		newRepoCmd(settings, out, debug),
+		newSearchCmd(settings, out, debug),
 		newInstallCmd(settings, actionConfig, out, debug),
 		newScaleCmd(settings, actionConfig, out, debug),
 		newUpgradeCmd(settings, actionConfig, out, debug),
 		newListCmd(settings, actionConfig, out, debug),
+		newOutdatedCmd(settings, actionConfig, out, debug),
+		newHistoryCmd(settings, actionConfig, out, debug),
+		newStatusCmd(settings, actionConfig, out),
+		newRecoverCmd(settings, actionConfig, out),
+		newStorageCmd(settings, actionConfig, out, debug),
+		newLintCmd(settings, out),
+		newImagesCmd(settings, actionConfig, out, debug),
+		newCheckAPIsCmd(settings, actionConfig, out, debug),
 		newTemplateCmd(settings, actionConfig, out, debug),
+		newPluginCmd(settings, out, debug),
 	)
 	// 使用 PersistentFlags 而不是 Flags
 
@@ -219,10 +307,6 @@ func init() {
 	//rootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
 }
 
-// GetGlobalEvent 返回全局事件实例
-func GetGlobalEvent() *installevent.InstallEvent {
-	return globalEvent
-}
 func newRegistryClient(
 	settings *cli.EnvSettings, certFile, keyFile, caFile string, insecureSkipTLSverify, plainHTTP bool, username, password string,
 ) (*registry.Client, error) {
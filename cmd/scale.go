@@ -3,18 +3,19 @@ package cmd
 import (
 	"context"
 	"fmt"
-	installevent "github.com/huangxiaofeng10047/go-cli-example/cmd/event"
+	"github.com/huangxiaofeng10047/go-cli-example/cmd/i18n"
 	"helm.sh/helm/v4/pkg/cli"
 	"io"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
-	"k8s.io/client-go/kubernetes"                 // 添加这行导入
 	_ "k8s.io/client-go/kubernetes/typed/core/v1" // 添加这行导入
 	"log"
+	"log/slog"
 	"os"
 	"os/signal"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -30,16 +31,6 @@ import (
 	"helm.sh/helm/v4/pkg/release"
 )
 
-const sacleDesc = `
-This command installs a chart archive.
-
-    扩容或者缩容pulsar服务
-    $ go-cli-example scale --replicase 0 -n pulsar-test
-
-To see the list of chart repositories, use 'helm repo list'. To search for
-charts in a repository, use 'helm search'.
-`
-
 func newScaleCmd(settings *cli.EnvSettings, cfg *action.Configuration, out io.Writer, debug action.DebugLog) *cobra.Command {
 	client := action.NewInstall(cfg)
 	valueOpts := &values.Options{}
@@ -49,14 +40,20 @@ func newScaleCmd(settings *cli.EnvSettings, cfg *action.Configuration, out io.Wr
 
 	cmd := &cobra.Command{
 		Use:   "scale [NAME] [CHART]",
-		Short: "scale statefulSet of the cluster",
-		Long:  sacleDesc,
+		Short: i18n.T("scale.short"),
+		Long:  i18n.T("scale.long"),
 		Args:  require.MinimumNArgs(1),
 		ValidArgsFunction: func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 			return compInstall(settings, args, toComplete, client)
 		},
-		RunE: func(_ *cobra.Command, args []string) error {
-			event := installevent.NewInstallEvent(testConfig)
+		RunE: func(_ *cobra.Command, args []string) (err error) {
+			prog := newProgress(out, outfmt)
+			defer func() { prog.Finish(err) }()
+			reportKubeProgress(cfg, prog)
+			event, err := newInstallEvent(settings, cfg, args[0], prog, debug)
+			if err != nil {
+				return err
+			}
 			ctx := context.Background()
 			registryClient, err := newRegistryClient(settings, client.CertFile, client.KeyFile, client.CaFile,
 				client.InsecureSkipTLSverify, client.PlainHTTP, client.Username, client.Password)
@@ -72,17 +69,12 @@ func newScaleCmd(settings *cli.EnvSettings, cfg *action.Configuration, out io.Wr
 			// 处理缩容逻辑
 			if replicas == 0 {
 				if len(args) == 0 {
-					return fmt.Errorf("请提供要匹配的 StatefulSet 前缀")
+					return errors.New(i18n.T("scale.missingPrefix"))
 				}
 				prefix := args[0]
-				fmt.Printf("要匹配的前缀: %s\n", prefix)    // 添加调试信息
-				fmt.Printf("目标命名空间: %s\n", namespace) // 添加调试信息
+				slog.Debug("Looking for StatefulSets", "prefix", prefix, "namespace", namespace)
 
-				config, err := cfg.RESTClientGetter.ToRESTConfig()
-				if err != nil {
-					return fmt.Errorf("failed to get REST config: %w", err)
-				}
-				clientset, err := kubernetes.NewForConfig(config)
+				clientset, err := kubeClientSet(cfg)
 				if err != nil {
 					return fmt.Errorf("failed to create clientset: %w", err)
 				}
@@ -101,25 +93,40 @@ func newScaleCmd(settings *cli.EnvSettings, cfg *action.Configuration, out io.Wr
 					}
 				}
 
-				fmt.Printf("找到 %d 个匹配的 StatefulSet\n", len(matchingStatefulSets)) // 添加调试信息
+				slog.Debug("Found matching StatefulSets", "count", len(matchingStatefulSets))
 
 				patch := []byte(fmt.Sprintf(`{"spec": {"replicas": %d}}`, replicas))
 				for _, ssMeta := range matchingStatefulSets {
-					fmt.Printf("正在缩容 StatefulSet: %s\n", ssMeta.Name) // 添加调试信息
-					_, err = clientset.AppsV1().StatefulSets(namespace).Patch(ctx, ssMeta.Name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+					slog.Debug("Scaling down StatefulSet", "name", ssMeta.Name)
+					_, err = clientset.AppsV1().StatefulSets(ssMeta.Namespace).Patch(ctx, ssMeta.Name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
 					if err != nil {
 						return fmt.Errorf("failed to scale down statefulSet %s: %w", ssMeta.Name, err)
 					}
-					fmt.Fprintf(out, "Successfully scaled down %s to %d replicas in namespace %s\n", ssMeta.Name, replicas, namespace)
+					fmt.Fprint(out, i18n.T("scale.scaledDown", ssMeta.Name, replicas, ssMeta.Namespace))
 				}
 				return nil
 			}
 
+			started := time.Now()
+			prog.Phase(i18n.T("progress.render"))
 			rel, err := runScale(settings, args, client, valueOpts, out, debug)
 			if err != nil {
 				return errors.Wrap(err, "scaled FAILED")
 			}
-			err = outfmt.Write(out, &statusPrinter{
+			prog.Stop()
+			recordDeploy := !isDryRunOption(client.DryRunOption)
+			if recordDeploy {
+				if err := recordDeployInfo(cfg, rel, started); err != nil {
+					debug("unable to record deploy info: %s", err)
+				}
+			}
+			report := newDeployReport(out, outfmt)
+			defer func() {
+				if ferr := report.flush(); err == nil {
+					err = ferr
+				}
+			}()
+			err = report.writeStatus(&statusPrinter{
 				release:      rel,
 				debug:        settings.Debug,
 				showMetadata: false,
@@ -134,22 +141,31 @@ func newScaleCmd(settings *cli.EnvSettings, cfg *action.Configuration, out io.Wr
 			}
 			taskID, err2 := event.FinishInstall(settings, cfg, args[0])
 			if err2 != nil {
-				return errors.Wrap(err, "scaled FAILED")
+				return errors.Wrap(err2, "scaled FAILED")
 			}
-			fmt.Fprintln(out, "Waiting for testcase finish...")
 
-			err = event.WaitTestCaseFinish(settings, ctx, out, taskID)
-			if err != nil {
-				return err
+			tests, err := event.WaitTestCaseFinish(settings, ctx, taskID)
+			result := testResultError
+			if err == nil {
+				result = tests.Result
+				err = report.writeTests(tests)
+			}
+			if err == nil {
+				err = tests.Err()
+			}
+			if recordDeploy {
+				if rerr := recordTestResult(cfg, rel, result); rerr != nil {
+					debug("unable to record test result: %s", rerr)
+				}
 			}
-			return nil
+			return err
 		},
 	}
 
 	addScaleFlags(settings, cmd, cmd.Flags(), client, valueOpts)
 	// 添加 --replicase 和 -n 标志
-	cmd.Flags().IntVar(&replicas, "replicase", 0, "Number of replicas to scale the statefulSet to")
-	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Namespace to scale the statefulSet in")
+	cmd.Flags().IntVar(&replicas, "replicase", 0, i18n.T("scale.flagReplicas"))
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", i18n.T("scale.flagNamespace"))
 	// hide-secret is not available in all places the install flags are used so
 	// it is added separately
 	f := cmd.Flags()
@@ -296,7 +312,7 @@ func runScale(settings *cli.EnvSettings, args []string, client *action.Install,
 	signal.Notify(cSignal, os.Interrupt, syscall.SIGTERM)
 	go func() {
 		<-cSignal
-		fmt.Fprintf(out, "Release %s has been cancelled.\n", args[0])
+		fmt.Fprint(out, i18n.T("release.cancelled", args[0]))
 		cancel()
 	}()
 
@@ -0,0 +1,27 @@
+package cmd
+
+import "testing"
+
+func TestScaleCmd(t *testing.T) {
+	tests := []cmdTestCase{
+		{
+			name:     "scale up and run the test cases",
+			cmd:      "scale pt testdata/testcharts/mini --replicase 3",
+			scenario: "scenario-scale.yaml",
+			golden:   "scale.golden",
+		},
+		{
+			name:     "scale up with json output",
+			cmd:      "scale pt testdata/testcharts/mini --replicase 3 -o json",
+			scenario: "scenario-scale.yaml",
+			golden:   "scale-json.golden",
+		},
+		{
+			name:     "scale down the statefulsets of a release",
+			cmd:      "scale pt -n pulsar",
+			scenario: "scenario-scale.yaml",
+			golden:   "scale-down.golden",
+		},
+	}
+	runTestCmd(t, tests)
+}
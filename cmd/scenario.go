@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	installevent "github.com/huangxiaofeng10047/go-cli-example/cmd/event"
+	"helm.sh/helm/v4/pkg/cli"
+	"io"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/yaml"
+
+	"helm.sh/helm/v4/pkg/action"
+	chartutil "helm.sh/helm/v4/pkg/chart/v2/util"
+	kubefake "helm.sh/helm/v4/pkg/kube/fake"
+	"helm.sh/helm/v4/pkg/release"
+	"helm.sh/helm/v4/pkg/storage"
+	"helm.sh/helm/v4/pkg/storage/driver"
+)
+
+// ScenarioEnvVar names the scenario fixture file loaded in place of a cluster.
+const ScenarioEnvVar = "HELM_SCENARIO"
+
+// scenario is the format of a scenario fixture. It seeds the release storage
+// like HELM_MEMORY_DRIVER_DATA does, and also the Kubernetes objects and the
+// test service responses that install, scale and upgrade read after deploying:
+//
+//	kubeVersion: v1.30.0
+//	pollInterval: 10ms
+//	releases:
+//	  - name: pulsar-mini
+//	    namespace: pulsar
+//	    version: 1
+//	    info: {status: deployed}
+//	    chart: {metadata: {name: pulsar, version: 3.9.0}}
+//	objects:
+//	  - apiVersion: v1
+//	    kind: Service
+//	    metadata: {name: pulsar-mini-proxy, namespace: pulsar}
+//	    spec: {type: ClusterIP, clusterIP: 10.0.0.10}
+//	  - apiVersion: v1
+//	    kind: Secret
+//	    metadata: {name: pulsar-mini-token-admin, namespace: pulsar}
+//	    stringData: {TOKEN: secret}
+//	testClient:
+//	  trigger: {code: 200, message: ok, data: task-1}
+//	  status:
+//	    - {code: 200, data: "任务正在执行中"}
+//	    - {code: 200, data: [{name: produce-consume, message: "{}"}]}
+type scenario struct {
+	// KubeVersion is the version reported to charts, defaults to the one
+	// Helm renders with when no cluster is available.
+	KubeVersion string `json:"kubeVersion,omitempty"`
+	// PollInterval replaces the intervals used to wait for pods and tests.
+	PollInterval string                 `json:"pollInterval,omitempty"`
+	Releases     []*release.Release     `json:"releases,omitempty"`
+	Objects      []runtime.RawExtension `json:"objects,omitempty"`
+	TestClient   *scenarioTestClient    `json:"testClient,omitempty"`
+}
+
+// scenarioTestClient holds the responses of the test service.
+type scenarioTestClient struct {
+	// Trigger is the response to starting the test cases.
+	Trigger json.RawMessage `json:"trigger,omitempty"`
+	// Status are the responses to polling the test task, in order. The last
+	// one is repeated.
+	Status []json.RawMessage `json:"status,omitempty"`
+	// Error fails every call to the test service.
+	Error string `json:"error,omitempty"`
+}
+
+// LoadScenario replaces the cluster used by cfg with the fixture in file:
+// releases go into memory storage, objects into a fake clientset, manifests
+// are applied to a fake kube client, and the test service is replaced by an
+// installevent.FakeTestClient. The REST config of cfg serves the objects too,
+// read-only, so the 'lookup' template function sees them. Commands run after
+// this never reach a cluster.
+func LoadScenario(settings *cli.EnvSettings, cfg *action.Configuration, file string) error {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return errors.Wrap(err, "unable to read scenario")
+	}
+	sc := &scenario{}
+	if err := yaml.UnmarshalStrict(b, sc); err != nil {
+		return errors.Wrapf(err, "unable to parse scenario %s", file)
+	}
+
+	mem := driver.NewMemory()
+	cfg.Releases = storage.Init(mem)
+	for _, rel := range sc.Releases {
+		if err := cfg.Releases.Create(rel); err != nil {
+			return errors.Wrapf(err, "unable to store release %s", rel.Name)
+		}
+	}
+	// Must reset namespace to the proper one
+	mem.SetNamespace(settings.Namespace())
+
+	cfg.KubeClient = &kubefake.PrintingKubeClient{Out: io.Discard}
+	cfg.Capabilities = chartutil.DefaultCapabilities.Copy()
+	if sc.KubeVersion != "" {
+		kv, err := chartutil.ParseKubeVersion(sc.KubeVersion)
+		if err != nil {
+			return errors.Wrapf(err, "invalid kubeVersion %q", sc.KubeVersion)
+		}
+		cfg.Capabilities.KubeVersion = *kv
+	}
+
+	objs, err := scenarioObjects(sc.Objects, settings.Namespace())
+	if err != nil {
+		return err
+	}
+	clientset := fake.NewClientset(objs...)
+	testConfig.ClientSet = clientset
+	cfg.RESTClientGetter = newScenarioRESTClientGetter(clientset.Tracker(), cfg.Capabilities.KubeVersion)
+
+	if sc.PollInterval != "" {
+		d, err := time.ParseDuration(sc.PollInterval)
+		if err != nil {
+			return errors.Wrapf(err, "invalid pollInterval %q", sc.PollInterval)
+		}
+		testConfig.PollInterval = d
+	}
+
+	client := &installevent.FakeTestClient{}
+	if tc := sc.TestClient; tc != nil {
+		client.TriggerResponse = tc.Trigger
+		for _, s := range tc.Status {
+			client.StatusResponses = append(client.StatusResponses, string(s))
+		}
+		if tc.Error != "" {
+			client.Err = errors.New(tc.Error)
+		}
+	}
+	testConfig.Client = client
+	return nil
+}
+
+// scenarioObjects decodes the objects of a scenario. Objects without a
+// namespace are put in the default one.
+func scenarioObjects(raw []runtime.RawExtension, namespace string) ([]runtime.Object, error) {
+	decoder := scheme.Codecs.UniversalDeserializer()
+	objs := make([]runtime.Object, 0, len(raw))
+	for i, r := range raw {
+		obj, gvk, err := decoder.Decode(r.Raw, nil, nil)
+		if err != nil {
+			return nil, errors.Wrapf(err, "scenario object %d", i+1)
+		}
+		m, err := meta.Accessor(obj)
+		if err != nil {
+			return nil, fmt.Errorf("scenario object %d (%s): %w", i+1, gvk.Kind, err)
+		}
+		if m.GetNamespace() == "" {
+			m.SetNamespace(namespace)
+		}
+		// The API server merges stringData into data, the fake clientset does not.
+		if s, ok := obj.(*corev1.Secret); ok && len(s.StringData) > 0 {
+			if s.Data == nil {
+				s.Data = map[string][]byte{}
+			}
+			for k, v := range s.StringData {
+				s.Data[k] = []byte(v)
+			}
+			s.StringData = nil
+		}
+		objs = append(objs, obj)
+	}
+	return objs, nil
+}
+
+// kubeClientSet returns the clientset of the loaded scenario, or the one of
+// cfg.
+func kubeClientSet(cfg *action.Configuration) (kubernetes.Interface, error) {
+	if testConfig.ClientSet != nil {
+		return testConfig.ClientSet, nil
+	}
+	return cfg.KubernetesClientSet()
+}
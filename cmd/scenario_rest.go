@@ -0,0 +1,268 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+	k8stesting "k8s.io/client-go/testing"
+
+	chartutil "helm.sh/helm/v4/pkg/chart/v2/util"
+)
+
+// clusterScopedKinds are the built-in kinds that do not live in a namespace.
+var clusterScopedKinds = map[schema.GroupKind]bool{
+	{Kind: "Namespace"}:        true,
+	{Kind: "Node"}:             true,
+	{Kind: "PersistentVolume"}: true,
+	{Kind: "ComponentStatus"}:  true,
+	{Group: "rbac.authorization.k8s.io", Kind: "ClusterRole"}:                         true,
+	{Group: "rbac.authorization.k8s.io", Kind: "ClusterRoleBinding"}:                  true,
+	{Group: "storage.k8s.io", Kind: "StorageClass"}:                                   true,
+	{Group: "storage.k8s.io", Kind: "CSIDriver"}:                                      true,
+	{Group: "storage.k8s.io", Kind: "CSINode"}:                                        true,
+	{Group: "storage.k8s.io", Kind: "VolumeAttachment"}:                               true,
+	{Group: "storage.k8s.io", Kind: "VolumeAttributesClass"}:                          true,
+	{Group: "scheduling.k8s.io", Kind: "PriorityClass"}:                               true,
+	{Group: "node.k8s.io", Kind: "RuntimeClass"}:                                      true,
+	{Group: "networking.k8s.io", Kind: "IngressClass"}:                                true,
+	{Group: "networking.k8s.io", Kind: "IPAddress"}:                                   true,
+	{Group: "networking.k8s.io", Kind: "ServiceCIDR"}:                                 true,
+	{Group: "certificates.k8s.io", Kind: "CertificateSigningRequest"}:                 true,
+	{Group: "certificates.k8s.io", Kind: "ClusterTrustBundle"}:                        true,
+	{Group: "admissionregistration.k8s.io", Kind: "MutatingWebhookConfiguration"}:     true,
+	{Group: "admissionregistration.k8s.io", Kind: "ValidatingWebhookConfiguration"}:   true,
+	{Group: "admissionregistration.k8s.io", Kind: "ValidatingAdmissionPolicy"}:        true,
+	{Group: "admissionregistration.k8s.io", Kind: "ValidatingAdmissionPolicyBinding"}: true,
+	{Group: "flowcontrol.apiserver.k8s.io", Kind: "FlowSchema"}:                       true,
+	{Group: "flowcontrol.apiserver.k8s.io", Kind: "PriorityLevelConfiguration"}:       true,
+	{Group: "resource.k8s.io", Kind: "DeviceClass"}:                                   true,
+	{Group: "resource.k8s.io", Kind: "ResourceSlice"}:                                 true,
+}
+
+// scenarioRESTClientGetter is the RESTClientGetter of a scenario. Requests
+// made with its REST config, such as the ones of the 'lookup' template
+// function, are answered in-process from the objects of the scenario, so
+// rendering a chart never reaches a cluster. The API is read-only.
+type scenarioRESTClientGetter struct {
+	config    *rest.Config
+	discovery discovery.CachedDiscoveryInterface
+}
+
+func newScenarioRESTClientGetter(tracker k8stesting.ObjectTracker, kubeVersion chartutil.KubeVersion) *scenarioRESTClientGetter {
+	api := &scenarioAPI{tracker: tracker, kubeVersion: kubeVersion}
+	api.resources = builtinResources()
+	config := &rest.Config{Host: "http://scenario.invalid", Transport: api}
+	return &scenarioRESTClientGetter{
+		config:    config,
+		discovery: memory.NewMemCacheClient(discovery.NewDiscoveryClientForConfigOrDie(config)),
+	}
+}
+
+func (g *scenarioRESTClientGetter) ToRESTConfig() (*rest.Config, error) {
+	return rest.CopyConfig(g.config), nil
+}
+
+func (g *scenarioRESTClientGetter) ToDiscoveryClient() (discovery.CachedDiscoveryInterface, error) {
+	return g.discovery, nil
+}
+
+func (g *scenarioRESTClientGetter) ToRESTMapper() (meta.RESTMapper, error) {
+	return restmapper.NewDeferredDiscoveryRESTMapper(g.discovery), nil
+}
+
+// scenarioAPI is a round tripper serving the discovery endpoints and GET
+// requests of the Kubernetes API from an object tracker.
+type scenarioAPI struct {
+	tracker     k8stesting.ObjectTracker
+	kubeVersion chartutil.KubeVersion
+	// resources are the resources of each group version, keyed by
+	// "group/version", or "v1" for the core group.
+	resources map[string]*metav1.APIResourceList
+}
+
+// builtinResources lists the resources of the kinds known to the client-go
+// scheme.
+func builtinResources() map[string]*metav1.APIResourceList {
+	lists := map[string]*metav1.APIResourceList{}
+	for gvk := range scheme.Scheme.AllKnownTypes() {
+		if gvk.Version == runtime.APIVersionInternal || strings.HasSuffix(gvk.Kind, "List") {
+			continue
+		}
+		obj, err := scheme.Scheme.New(gvk)
+		if err != nil {
+			continue
+		}
+		if _, err := meta.Accessor(obj); err != nil {
+			continue
+		}
+		gv := gvk.GroupVersion().String()
+		if lists[gv] == nil {
+			lists[gv] = &metav1.APIResourceList{GroupVersion: gv}
+		}
+		plural, singular := meta.UnsafeGuessKindToResource(gvk)
+		lists[gv].APIResources = append(lists[gv].APIResources, metav1.APIResource{
+			Name:         plural.Resource,
+			SingularName: singular.Resource,
+			Namespaced:   !clusterScopedKinds[gvk.GroupKind()],
+			Kind:         gvk.Kind,
+			Verbs:        metav1.Verbs{"get", "list"},
+		})
+	}
+	for _, l := range lists {
+		sort.Slice(l.APIResources, func(i, j int) bool { return l.APIResources[i].Name < l.APIResources[j].Name })
+	}
+	return lists
+}
+
+func (a *scenarioAPI) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		return a.status(req, apierrors.NewMethodNotSupported(schema.GroupResource{}, req.Method))
+	}
+	p := strings.Trim(req.URL.Path, "/")
+	switch {
+	case p == "version":
+		return a.respond(req, &version.Info{
+			Major:      a.kubeVersion.Major,
+			Minor:      a.kubeVersion.Minor,
+			GitVersion: a.kubeVersion.Version,
+		})
+	case p == "api":
+		return a.respond(req, &metav1.APIVersions{Versions: []string{"v1"}})
+	case p == "apis":
+		return a.respond(req, a.groups())
+	}
+
+	// api/v1/... or apis/GROUP/VERSION/...
+	parts := strings.Split(p, "/")
+	var gv schema.GroupVersion
+	switch {
+	case len(parts) >= 2 && parts[0] == "api":
+		gv, parts = schema.GroupVersion{Version: parts[1]}, parts[2:]
+	case len(parts) >= 3 && parts[0] == "apis":
+		gv, parts = schema.GroupVersion{Group: parts[1], Version: parts[2]}, parts[3:]
+	default:
+		return a.status(req, apierrors.NewNotFound(schema.GroupResource{}, p))
+	}
+	list, ok := a.resources[gv.String()]
+	if !ok {
+		return a.status(req, apierrors.NewNotFound(schema.GroupResource{Group: gv.Group}, gv.Version))
+	}
+	if len(parts) == 0 {
+		return a.respond(req, list)
+	}
+
+	var ns, name string
+	if len(parts) >= 3 && parts[0] == "namespaces" {
+		ns, parts = parts[1], parts[2:]
+	}
+	if len(parts) > 2 {
+		return a.status(req, apierrors.NewNotFound(schema.GroupResource{Group: gv.Group}, p))
+	}
+	if len(parts) == 2 {
+		name = parts[1]
+	}
+	var res *metav1.APIResource
+	for i := range list.APIResources {
+		if list.APIResources[i].Name == parts[0] {
+			res = &list.APIResources[i]
+		}
+	}
+	if res == nil {
+		return a.status(req, apierrors.NewNotFound(gv.WithResource(parts[0]).GroupResource(), name))
+	}
+	gvr, gvk := gv.WithResource(res.Name), gv.WithKind(res.Kind)
+
+	if name != "" {
+		obj, err := a.tracker.Get(gvr, ns, name)
+		if err != nil {
+			return a.status(req, err)
+		}
+		obj = obj.DeepCopyObject()
+		obj.GetObjectKind().SetGroupVersionKind(gvk)
+		return a.respond(req, obj)
+	}
+	obj, err := a.tracker.List(gvr, gvk, ns)
+	if err != nil {
+		return a.status(req, err)
+	}
+	if items, err := meta.ExtractList(obj); err == nil {
+		for _, item := range items {
+			item.GetObjectKind().SetGroupVersionKind(gvk)
+		}
+		_ = meta.SetList(obj, items)
+	}
+	obj.GetObjectKind().SetGroupVersionKind(gv.WithKind(res.Kind + "List"))
+	return a.respond(req, obj)
+}
+
+// groups returns the API groups of the resources.
+func (a *scenarioAPI) groups() *metav1.APIGroupList {
+	byGroup := map[string]*metav1.APIGroup{}
+	for key := range a.resources {
+		gv, _ := schema.ParseGroupVersion(key)
+		if gv.Group == "" {
+			continue
+		}
+		g := byGroup[gv.Group]
+		if g == nil {
+			g = &metav1.APIGroup{Name: gv.Group}
+			byGroup[gv.Group] = g
+		}
+		g.Versions = append(g.Versions, metav1.GroupVersionForDiscovery{GroupVersion: key, Version: gv.Version})
+	}
+	list := &metav1.APIGroupList{}
+	for _, g := range byGroup {
+		// Like the API server, list GA versions before beta and alpha ones and
+		// prefer the first, so that apps/v1 wins over apps/v1beta2.
+		sort.Slice(g.Versions, func(i, j int) bool {
+			return version.CompareKubeAwareVersionStrings(g.Versions[i].Version, g.Versions[j].Version) > 0
+		})
+		g.PreferredVersion = g.Versions[0]
+		list.Groups = append(list.Groups, *g)
+	}
+	sort.Slice(list.Groups, func(i, j int) bool { return list.Groups[i].Name < list.Groups[j].Name })
+	return list
+}
+
+func (a *scenarioAPI) respond(req *http.Request, v any) (*http.Response, error) {
+	return a.response(req, http.StatusOK, v)
+}
+
+// status answers with the Status of err, as the API server does.
+func (a *scenarioAPI) status(req *http.Request, err error) (*http.Response, error) {
+	s := apierrors.NewInternalError(err).ErrStatus
+	if se, ok := err.(apierrors.APIStatus); ok {
+		s = se.Status()
+	}
+	s.Kind, s.APIVersion = "Status", "v1"
+	return a.response(req, int(s.Code), &s)
+}
+
+func (a *scenarioAPI) response(req *http.Request, code int, v any) (*http.Response, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Response{
+		StatusCode: code,
+		Status:     http.StatusText(code),
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(bytes.NewReader(b)),
+		Request:    req,
+	}, nil
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"slices"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestScenarioAPIPreferredVersions(t *testing.T) {
+	a := &scenarioAPI{resources: builtinResources()}
+	preferred := map[string]string{}
+	for _, g := range a.groups().Groups {
+		preferred[g.Name] = g.PreferredVersion.Version
+	}
+
+	tests := []struct {
+		group string
+		want  string
+	}{
+		{"apps", "v1"},
+		{"batch", "v1"},
+		{"policy", "v1"},
+		{"autoscaling", "v2"},
+		{"networking.k8s.io", "v1"},
+	}
+	for _, tt := range tests {
+		if got := preferred[tt.group]; got != tt.want {
+			t.Errorf("preferred version of %s: expected %q, got %q", tt.group, tt.want, got)
+		}
+	}
+}
+
+func TestScenarioAPIVersionOrder(t *testing.T) {
+	a := &scenarioAPI{resources: map[string]*metav1.APIResourceList{
+		"apps/v1beta1": {}, "apps/v1": {}, "apps/v1beta2": {}, "apps/v2alpha1": {},
+	}}
+	groups := a.groups().Groups
+	if len(groups) != 1 {
+		t.Fatalf("expected 1 group, got %d", len(groups))
+	}
+	var got []string
+	for _, v := range groups[0].Versions {
+		got = append(got, v.Version)
+	}
+	if want := []string{"v1", "v1beta2", "v1beta1", "v2alpha1"}; !slices.Equal(got, want) {
+		t.Errorf("expected versions %v, got %v", want, got)
+	}
+}
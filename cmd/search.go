@@ -0,0 +1,25 @@
+package cmd
+
+import (
+	"helm.sh/helm/v4/pkg/cli"
+	"io"
+
+	"github.com/huangxiaofeng10047/go-cli-example/cmd/i18n"
+	"github.com/spf13/cobra"
+
+	"helm.sh/helm/v4/cmd/helm/require"
+	"helm.sh/helm/v4/pkg/action"
+)
+
+func newSearchCmd(settings *cli.EnvSettings, out io.Writer, debug action.DebugLog) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "search [keyword]",
+		Short: i18n.T("search.short"),
+		Long:  i18n.T("search.long"),
+		Args:  require.NoArgs,
+	}
+
+	cmd.AddCommand(newSearchRepoCmd(settings, out, debug))
+
+	return cmd
+}
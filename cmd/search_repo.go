@@ -8,43 +8,23 @@ import (
 	"helm.sh/helm/v4/pkg/cli"
 	"io"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 
 	"github.com/Masterminds/semver/v3"
 	"github.com/gosuri/uitable"
+	"github.com/huangxiaofeng10047/go-cli-example/cmd/i18n"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
 	"helm.sh/helm/v4/cmd/helm/search"
 	"helm.sh/helm/v4/pkg/cli/output"
 	"helm.sh/helm/v4/pkg/helmpath"
+	"helm.sh/helm/v4/pkg/registry"
 	"helm.sh/helm/v4/pkg/repo"
 )
 
-const searchRepoDesc = `
-Search reads through all of the repositories configured on the system, and
-looks for matches. Search of these repositories uses the metadata stored on
-the system.
-
-It will display the latest stable versions of the charts found. If you
-specify the --devel flag, the output will include pre-release versions.
-If you want to search using a version constraint, use --version.
-
-Examples:
-
-    # Search for stable release versions matching the keyword "nginx"
-    $ helm search repo nginx
-
-    # Search for release versions matching the keyword "nginx", including pre-release versions
-    $ helm search repo nginx --devel
-
-    # Search for the latest stable release for nginx-ingress with a major version of 1
-    $ helm search repo nginx-ingress --version ^1.0.0
-
-Repositories are managed with 'helm repo' commands.
-`
-
 // searchMaxScore suggests that any score higher than this is not considered a match.
 const searchMaxScore = 25
 
@@ -58,6 +38,16 @@ type searchRepoOptions struct {
 	repoCacheDir   string
 	outputFormat   output.Format
 	failOnNoResult bool
+
+	ociRefs     []string
+	chartDirs   []string
+	plainHTTP   bool
+	keywords    []string
+	annotations []string
+	kubeVersion string
+	appVersion  string
+	// sources maps the repository part of result names to their source.
+	sources map[string]string
 }
 
 func newSearchRepoCmd(settings *cli.EnvSettings, out io.Writer, debug action.DebugLog) *cobra.Command {
@@ -65,32 +55,39 @@ func newSearchRepoCmd(settings *cli.EnvSettings, out io.Writer, debug action.Deb
 
 	cmd := &cobra.Command{
 		Use:   "repo [keyword]",
-		Short: "search repositories for a keyword in charts",
-		Long:  searchRepoDesc,
+		Short: i18n.T("search.repoShort"),
+		Long:  i18n.T("search.repoLong"),
 		RunE: func(_ *cobra.Command, args []string) error {
 			o.repoFile = settings.RepositoryConfig
 			o.repoCacheDir = settings.RepositoryCache
-			return o.run(out, args, debug)
+			return o.run(settings, out, args, debug)
 		},
 	}
 
 	f := cmd.Flags()
-	f.BoolVarP(&o.regexp, "regexp", "r", false, "use regular expressions for searching repositories you have added")
-	f.BoolVarP(&o.versions, "versions", "l", false, "show the long listing, with each version of each chart on its own line, for repositories you have added")
-	f.BoolVar(&o.devel, "devel", false, "use development versions (alpha, beta, and release candidate releases), too. Equivalent to version '>0.0.0-0'. If --version is set, this is ignored")
-	f.StringVar(&o.version, "version", "", "search using semantic versioning constraints on repositories you have added")
-	f.UintVar(&o.maxColWidth, "max-col-width", 50, "maximum column width for output table")
-	f.BoolVar(&o.failOnNoResult, "fail-on-no-result", false, "search fails if no results are found")
+	f.BoolVarP(&o.regexp, "regexp", "r", false, i18n.T("search.flagRegexp"))
+	f.BoolVarP(&o.versions, "versions", "l", false, i18n.T("search.flagVersions"))
+	f.BoolVar(&o.devel, "devel", false, i18n.T("search.flagDevel"))
+	f.StringVar(&o.version, "version", "", i18n.T("search.flagVersion"))
+	f.UintVar(&o.maxColWidth, "max-col-width", 50, i18n.T("search.flagMaxColWidth"))
+	f.BoolVar(&o.failOnNoResult, "fail-on-no-result", false, i18n.T("search.flagFailOnNoResult"))
+	f.StringArrayVar(&o.ociRefs, "oci", nil, i18n.T("search.flagOCI"))
+	f.StringArrayVar(&o.chartDirs, "chart-dir", nil, i18n.T("search.flagChartDir"))
+	f.BoolVar(&o.plainHTTP, "plain-http", false, i18n.T("search.flagPlainHTTP"))
+	f.StringArrayVar(&o.keywords, "keyword", nil, i18n.T("search.flagKeyword"))
+	f.StringArrayVar(&o.annotations, "annotation", nil, i18n.T("search.flagAnnotation"))
+	f.StringVar(&o.kubeVersion, "kube-version", "", i18n.T("search.flagKubeVersion"))
+	f.StringVar(&o.appVersion, "app-version", "", i18n.T("search.flagAppVersion"))
 
 	bindOutputFlag(cmd, &o.outputFormat)
 
 	return cmd
 }
 
-func (o *searchRepoOptions) run(out io.Writer, args []string, debug action.DebugLog) error {
+func (o *searchRepoOptions) run(settings *cli.EnvSettings, out io.Writer, args []string, debug action.DebugLog) error {
 	o.setupSearchedVersion(debug)
 
-	index, err := o.buildIndex(debug)
+	index, err := o.buildIndex(settings, debug)
 	if err != nil {
 		return err
 	}
@@ -112,7 +109,7 @@ func (o *searchRepoOptions) run(out io.Writer, args []string, debug action.Debug
 		return err
 	}
 
-	return o.outputFormat.Write(out, &repoSearchWriter{data, o.maxColWidth, o.failOnNoResult})
+	return o.outputFormat.Write(out, &repoSearchWriter{data, o.maxColWidth, o.failOnNoResult, o.sources})
 }
 
 func (o *searchRepoOptions) setupSearchedVersion(debug action.DebugLog) {
@@ -138,7 +135,12 @@ func (o *searchRepoOptions) applyConstraint(res []*search.Result) ([]*search.Res
 
 	constraint, err := semver.NewConstraint(o.version)
 	if err != nil {
-		return res, errors.Wrap(err, "an invalid version/constraint format")
+		return res, errors.Wrap(err, i18n.T("search.badVersion"))
+	}
+
+	filter, err := o.chartFilter()
+	if err != nil {
+		return res, err
 	}
 
 	data := res[:0]
@@ -149,6 +151,9 @@ func (o *searchRepoOptions) applyConstraint(res []*search.Result) ([]*search.Res
 		if !o.versions && foundNames[r.Name] {
 			continue
 		}
+		if !filter(r.Chart.Metadata) {
+			continue
+		}
 		v, err := semver.NewVersion(r.Chart.Version)
 		if err != nil {
 			continue
@@ -162,16 +167,22 @@ func (o *searchRepoOptions) applyConstraint(res []*search.Result) ([]*search.Res
 	return data, nil
 }
 
-func (o *searchRepoOptions) buildIndex(debug action.DebugLog) (*search.Index, error) {
+func (o *searchRepoOptions) buildIndex(settings *cli.EnvSettings, debug action.DebugLog) (*search.Index, error) {
 	// Load the repositories.yaml
 	rf, err := repo.LoadFile(o.repoFile)
-	if isNotExist(err) || len(rf.Repositories) == 0 {
-		return nil, errors.New("no repositories configured")
+	if (isNotExist(err) || len(rf.Repositories) == 0) && len(o.ociRefs) == 0 && len(o.chartDirs) == 0 {
+		return nil, errors.New(i18n.T("search.noRepositories"))
 	}
 
+	all := o.versions || len(o.version) > 0
+	o.sources = map[string]string{}
 	i := search.NewIndex()
 	for _, re := range rf.Repositories {
 		n := re.Name
+		if registry.IsOCI(re.URL) {
+			o.addOCIChart(i, settings, n, re.URL, all, debug)
+			continue
+		}
 		f := filepath.Join(o.repoCacheDir, helmpath.CacheIndexFile(n))
 		ind, err := repo.LoadIndexFile(f)
 		if err != nil {
@@ -180,42 +191,82 @@ func (o *searchRepoOptions) buildIndex(debug action.DebugLog) (*search.Index, er
 			continue
 		}
 
-		i.AddRepo(n, ind, o.versions || len(o.version) > 0)
+		i.AddRepo(n, ind, all)
+		o.sources[n] = searchSourceRepo
+	}
+	for _, ref := range o.ociRefs {
+		if !registry.IsOCI(ref) {
+			return nil, errors.New(i18n.T("search.notOCI", ref))
+		}
+		// Result names are joined as paths, which would mangle the scheme.
+		rname := strings.TrimPrefix(ref[:strings.LastIndex(ref, "/")], "oci://")
+		o.addOCIChart(i, settings, rname, ref, all, debug)
+	}
+	for _, dir := range o.chartDirs {
+		ind, err := localChartIndex(dir)
+		if err != nil {
+			return nil, err
+		}
+		// Keyed like the result names, which the index joins with path.Join.
+		n := path.Clean(filepath.ToSlash(dir))
+		i.AddRepo(n, ind, all)
+		o.sources[n] = searchSourceLocal
 	}
 	return i, nil
 }
 
+// addOCIChart adds the versions of an OCI chart to the index, under the
+// repository name rname. Registries that cannot be reached are skipped, as
+// missing repository caches are.
+func (o *searchRepoOptions) addOCIChart(i *search.Index, settings *cli.EnvSettings, rname, ref string, all bool, debug action.DebugLog) {
+	ind, err := ociChartIndex(settings, ref, o.plainHTTP)
+	if err != nil {
+		debug("Unable to search %s: %s", ref, err)
+		return
+	}
+	i.AddRepo(rname, ind, all)
+	o.sources[rname] = searchSourceOCI
+}
+
 type repoChartElement struct {
 	Name        string `json:"name"`
 	Version     string `json:"version"`
 	AppVersion  string `json:"app_version"`
 	Description string `json:"description"`
+	Source      string `json:"source"`
 }
 
 type repoSearchWriter struct {
 	results        []*search.Result
 	columnWidth    uint
 	failOnNoResult bool
+	sources        map[string]string
+}
+
+// source returns where a result was found. Result names are the repository
+// name joined with the chart name.
+func (r *repoSearchWriter) source(res *search.Result) string {
+	return r.sources[path.Dir(res.Name)]
 }
 
 func (r *repoSearchWriter) WriteTable(out io.Writer) error {
 	if len(r.results) == 0 {
 		// Fail if no results found and --fail-on-no-result is enabled
 		if r.failOnNoResult {
-			return fmt.Errorf("no results found")
+			return errors.New(i18n.T("search.noResults"))
 		}
 
-		_, err := out.Write([]byte("No results found\n"))
+		_, err := fmt.Fprintln(out, i18n.T("search.none"))
 		if err != nil {
-			return fmt.Errorf("unable to write results: %s", err)
+			return fmt.Errorf("%s: %s", i18n.T("search.write"), err)
 		}
 		return nil
 	}
 	table := uitable.New()
 	table.MaxColWidth = r.columnWidth
-	table.AddRow("NAME", "CHART VERSION", "APP VERSION", "DESCRIPTION")
-	for _, r := range r.results {
-		table.AddRow(r.Name, r.Chart.Version, r.Chart.AppVersion, r.Chart.Description)
+	table.AddRow("NAME", "CHART VERSION", "APP VERSION", "SOURCE", "DESCRIPTION")
+	for _, res := range r.results {
+		table.AddRow(res.Name, res.Chart.Version, res.Chart.AppVersion, r.source(res), res.Chart.Description)
 	}
 	return output.EncodeTable(out, table)
 }
@@ -231,14 +282,14 @@ func (r *repoSearchWriter) WriteYAML(out io.Writer) error {
 func (r *repoSearchWriter) encodeByFormat(out io.Writer, format output.Format) error {
 	// Fail if no results found and --fail-on-no-result is enabled
 	if len(r.results) == 0 && r.failOnNoResult {
-		return fmt.Errorf("no results found")
+		return errors.New(i18n.T("search.noResults"))
 	}
 
 	// Initialize the array so no results returns an empty array instead of null
 	chartList := make([]repoChartElement, 0, len(r.results))
 
-	for _, r := range r.results {
-		chartList = append(chartList, repoChartElement{r.Name, r.Chart.Version, r.Chart.AppVersion, r.Chart.Description})
+	for _, res := range r.results {
+		chartList = append(chartList, repoChartElement{res.Name, res.Chart.Version, res.Chart.AppVersion, res.Chart.Description, r.source(res)})
 	}
 
 	switch format {
@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"helm.sh/helm/v4/pkg/cli"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/huangxiaofeng10047/go-cli-example/cmd/i18n"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+
+	chart "helm.sh/helm/v4/pkg/chart/v2"
+	"helm.sh/helm/v4/pkg/chart/v2/loader"
+	chartutil "helm.sh/helm/v4/pkg/chart/v2/util"
+	"helm.sh/helm/v4/pkg/registry"
+	"helm.sh/helm/v4/pkg/repo"
+)
+
+// Sources of search results.
+const (
+	searchSourceRepo  = "repo"
+	searchSourceOCI   = "oci"
+	searchSourceLocal = "local"
+)
+
+// ociChartIndex builds an index of the versions of an OCI chart reference
+// from its tags. The metadata of every version is read from the chart config
+// blob, so nothing but the manifests and configs is downloaded.
+func ociChartIndex(settings *cli.EnvSettings, ref string, plainHTTP bool) (*repo.IndexFile, error) {
+	ctx := context.Background()
+	r, err := newRemoteRepository(settings, strings.TrimPrefix(ref, "oci://"), plainHTTP)
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []string
+	if err := r.Tags(ctx, "", func(t []string) error {
+		tags = append(tags, t...)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	ind := repo.NewIndexFile()
+	for _, tag := range tags {
+		version := strings.ReplaceAll(tag, "_", "+")
+		if _, err := semver.NewVersion(version); err != nil {
+			continue
+		}
+		_, b, err := oras.FetchBytes(ctx, r, tag, oras.DefaultFetchBytesOptions)
+		if err != nil {
+			return nil, errors.Wrap(err, i18n.T("search.fetch", ref, tag))
+		}
+		manifest := ocispec.Manifest{}
+		if err := json.Unmarshal(b, &manifest); err != nil {
+			return nil, errors.Wrap(err, i18n.T("search.badManifest", ref, tag))
+		}
+		if manifest.Config.MediaType != registry.ConfigMediaType {
+			continue
+		}
+		config, err := content.FetchAll(ctx, r, manifest.Config)
+		if err != nil {
+			return nil, errors.Wrap(err, i18n.T("search.fetchMetadata", ref, tag))
+		}
+		md := &chart.Metadata{}
+		if err := json.Unmarshal(config, md); err != nil {
+			return nil, errors.Wrap(err, i18n.T("search.badMetadata", ref, tag))
+		}
+		ind.Entries[md.Name] = append(ind.Entries[md.Name], &repo.ChartVersion{
+			Metadata: md,
+			URLs:     []string{ref + ":" + tag},
+		})
+	}
+	ind.SortEntries()
+	return ind, nil
+}
+
+// localChartIndex builds an index of the chart directories and archives in
+// dir, including dir itself when it is a chart.
+func localChartIndex(dir string) (*repo.IndexFile, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	paths := []string{dir}
+	for _, e := range entries {
+		if e.IsDir() || strings.HasSuffix(e.Name(), ".tgz") {
+			paths = append(paths, filepath.Join(dir, e.Name()))
+		}
+	}
+
+	ind := repo.NewIndexFile()
+	for _, p := range paths {
+		var md *chart.Metadata
+		if strings.HasSuffix(p, ".tgz") {
+			ch, err := loader.LoadFile(p)
+			if err != nil {
+				continue
+			}
+			md = ch.Metadata
+		} else if md, err = chartutil.LoadChartfile(filepath.Join(p, chartutil.ChartfileName)); err != nil {
+			continue
+		}
+		ind.Entries[md.Name] = append(ind.Entries[md.Name], &repo.ChartVersion{
+			Metadata: md,
+			URLs:     []string{p},
+		})
+	}
+	ind.SortEntries()
+	return ind, nil
+}
+
+// chartFilter returns a function that reports whether chart metadata matches
+// the --keyword, --annotation, --kube-version and --app-version filters.
+func (o *searchRepoOptions) chartFilter() (func(*chart.Metadata) bool, error) {
+	var appVersion *semver.Constraints
+	if o.appVersion != "" {
+		c, err := semver.NewConstraint(o.appVersion)
+		if err != nil {
+			return nil, errors.Wrap(err, i18n.T("search.badAppVersion"))
+		}
+		appVersion = c
+	}
+	if o.kubeVersion != "" {
+		if _, err := chartutil.ParseKubeVersion(o.kubeVersion); err != nil {
+			return nil, errors.Wrap(err, i18n.T("error.badKubeVersion", o.kubeVersion))
+		}
+	}
+
+	return func(md *chart.Metadata) bool {
+		for _, k := range o.keywords {
+			found := false
+			for _, ck := range md.Keywords {
+				if strings.EqualFold(k, ck) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return false
+			}
+		}
+		for _, a := range o.annotations {
+			key, value, hasValue := strings.Cut(a, "=")
+			v, ok := md.Annotations[key]
+			if !ok || hasValue && v != value {
+				return false
+			}
+		}
+		if o.kubeVersion != "" && md.KubeVersion != "" && !chartutil.IsCompatibleRange(md.KubeVersion, o.kubeVersion) {
+			return false
+		}
+		if appVersion != nil {
+			v, err := semver.NewVersion(md.AppVersion)
+			if err != nil || !appVersion.Check(v) {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
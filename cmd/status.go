@@ -2,13 +2,18 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"helm.sh/helm/v4/pkg/cli"
 	"io"
 	"log"
+	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 	"time"
 
+	"github.com/huangxiaofeng10047/go-cli-example/cmd/i18n"
 	"github.com/spf13/cobra"
 
 	"k8s.io/kubectl/pkg/cmd/get"
@@ -21,27 +26,16 @@ import (
 )
 
 // NOTE: Keep the list of statuses up-to-date with pkg/release/status.go.
-var statusHelp = `
-This command shows the status of a named release.
-The status consists of:
-- last deployment time
-- k8s namespace in which the release lives
-- state of the release (can be: unknown, deployed, uninstalled, superseded, failed, uninstalling, pending-install, pending-upgrade or pending-rollback)
-- revision of the release
-- description of the release (can be completion message or error message)
-- list of resources that this release consists of
-- details on last test suite run, if applicable
-- additional notes provided by the chart
-`
-
 func newStatusCmd(settings *cli.EnvSettings, cfg *action.Configuration, out io.Writer) *cobra.Command {
 	client := action.NewStatus(cfg)
 	var outfmt output.Format
+	var watch bool
+	var watchInterval time.Duration
 
 	cmd := &cobra.Command{
 		Use:   "status RELEASE_NAME",
-		Short: "display the status of the named release",
-		Long:  statusHelp,
+		Short: i18n.T("status.short"),
+		Long:  i18n.T("status.long"),
 		Args:  require.ExactArgs(1),
 		ValidArgsFunction: func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 			if len(args) != 0 {
@@ -56,6 +50,26 @@ func newStatusCmd(settings *cli.EnvSettings, cfg *action.Configuration, out io.W
 			if outfmt == output.Table {
 				client.ShowResourcesTable = true
 			}
+			if watch {
+				ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+				defer stop()
+				w := newWatchRenderer(out, outfmt)
+				opts := watchOptions{
+					namespace:   settings.Namespace(),
+					releaseName: args[0],
+					pods:        true,
+					interval:    watchInterval,
+				}
+				return watchChanges(ctx, cfg, opts, func() error {
+					rel, err := client.Run(args[0])
+					if err != nil {
+						return err
+					}
+					rel.Chart = nil
+					return w.render(&statusPrinter{release: rel})
+				})
+			}
+
 			rel, err := client.Run(args[0])
 			if err != nil {
 				return err
@@ -76,6 +90,8 @@ func newStatusCmd(settings *cli.EnvSettings, cfg *action.Configuration, out io.W
 	f := cmd.Flags()
 
 	f.IntVar(&client.Version, "revision", 0, "if set, display the status of the named release with revision")
+	f.BoolVarP(&watch, "watch", "w", false, i18n.T("status.flagWatch"))
+	f.DurationVar(&watchInterval, "watch-interval", 2*time.Second, i18n.T("watch.flagInterval"))
 
 	err := cmd.RegisterFlagCompletionFunc("revision", func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		if len(args) == 1 {
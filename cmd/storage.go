@@ -0,0 +1,358 @@
+package cmd
+
+import (
+	"fmt"
+	"helm.sh/helm/v4/pkg/cli"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/huangxiaofeng10047/go-cli-example/cmd/i18n"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+
+	"helm.sh/helm/v4/cmd/helm/require"
+	"helm.sh/helm/v4/pkg/action"
+	"helm.sh/helm/v4/pkg/release"
+	"helm.sh/helm/v4/pkg/storage"
+	"helm.sh/helm/v4/pkg/storage/driver"
+)
+
+// storageDrivers are the values of $HELM_DRIVER.
+var storageDrivers = []string{"configmap", "configmaps", "secret", "secrets", "memory", "sql"}
+
+// exportedRelease is a release record as written by 'storage export'. The
+// labels are not part of the release JSON and are added next to it, where
+// readers of plain release lists ignore them.
+type exportedRelease struct {
+	*release.Release
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+func newStorageCmd(settings *cli.EnvSettings, cfg *action.Configuration, out io.Writer, debug action.DebugLog) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "storage export|import|migrate",
+		Short: i18n.T("storage.short"),
+		Long:  i18n.T("storage.long"),
+		Args:  require.NoArgs,
+	}
+
+	cmd.AddCommand(newStorageExportCmd(settings, cfg, out, debug))
+	cmd.AddCommand(newStorageImportCmd(settings, cfg, out, debug))
+	cmd.AddCommand(newStorageMigrateCmd(settings, out, debug))
+	return cmd
+}
+
+func newStorageExportCmd(settings *cli.EnvSettings, cfg *action.Configuration, out io.Writer, debug action.DebugLog) *cobra.Command {
+	var allNamespaces bool
+	var file string
+
+	cmd := &cobra.Command{
+		Use:   "export [RELEASE...]",
+		Short: i18n.T("storage.exportShort"),
+		Long:  i18n.T("storage.exportLong"),
+		RunE: func(_ *cobra.Command, args []string) error {
+			store := cfg.Releases
+			if allNamespaces {
+				var err error
+				if store, err = namespaceStorage(settings, cfg, debug)(""); err != nil {
+					return err
+				}
+			}
+			rels, err := selectReleases(store, args)
+			if err != nil {
+				return err
+			}
+			b, err := marshalReleases(rels)
+			if err != nil {
+				return err
+			}
+			if file == "" {
+				_, err := out.Write(b)
+				return err
+			}
+			if err := os.WriteFile(file, b, 0600); err != nil {
+				return err
+			}
+			fmt.Fprint(out, i18n.T("storage.exported", revisionCount(rels), file))
+			return nil
+		},
+	}
+
+	f := cmd.Flags()
+	f.BoolVarP(&allNamespaces, "all-namespaces", "A", false, i18n.T("storage.flagExportAll"))
+	f.StringVarP(&file, "file", "f", "", i18n.T("storage.flagFile"))
+	return cmd
+}
+
+func newStorageImportCmd(settings *cli.EnvSettings, cfg *action.Configuration, out io.Writer, debug action.DebugLog) *cobra.Command {
+	var driverName string
+	var overwrite, dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "import FILE",
+		Short: i18n.T("storage.importShort"),
+		Long:  i18n.T("storage.importLong"),
+		Args:  require.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			b, err := os.ReadFile(args[0])
+			if err != nil {
+				return err
+			}
+			rels, err := unmarshalReleases(b)
+			if err != nil {
+				return errors.Wrap(err, i18n.T("storage.parse", args[0]))
+			}
+
+			open := namespaceStorage(settings, cfg, debug)
+			if cmd.Flags().Changed("driver") {
+				open = cachedStorage(func(namespace string) (*storage.Storage, error) {
+					return openStorage(settings, driverName, namespace, debug)
+				})
+			}
+			return copyReleases(out, rels, open, overwrite, dryRun)
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVar(&driverName, "driver", os.Getenv("HELM_DRIVER"), i18n.T("storage.flagDriver", strings.Join(storageDrivers, ", ")))
+	f.BoolVar(&overwrite, "overwrite", false, i18n.T("storage.flagOverwrite"))
+	f.BoolVar(&dryRun, "dry-run", false, i18n.T("storage.flagImportDryRun"))
+	return cmd
+}
+
+func newStorageMigrateCmd(settings *cli.EnvSettings, out io.Writer, debug action.DebugLog) *cobra.Command {
+	var from, to string
+	var allNamespaces, overwrite, dryRun, deleteSource bool
+
+	cmd := &cobra.Command{
+		Use:   "migrate --from DRIVER --to DRIVER [RELEASE...]",
+		Short: i18n.T("storage.migrateShort"),
+		Long:  i18n.T("storage.migrateLong"),
+		RunE: func(_ *cobra.Command, args []string) error {
+			if from == "" || to == "" {
+				return errors.New(i18n.T("storage.noFromTo"))
+			}
+			if normalizeDriver(from) == normalizeDriver(to) {
+				return errors.New(i18n.T("storage.sameDriver", from))
+			}
+			namespace := settings.Namespace()
+			if allNamespaces {
+				namespace = ""
+			}
+			source, err := openStorage(settings, from, namespace, debug)
+			if err != nil {
+				return err
+			}
+			rels, err := selectReleases(source, args)
+			if err != nil {
+				return err
+			}
+
+			open := cachedStorage(func(namespace string) (*storage.Storage, error) {
+				return openStorage(settings, to, namespace, debug)
+			})
+			if err := copyReleases(out, rels, open, overwrite, dryRun); err != nil {
+				return err
+			}
+			if !deleteSource || dryRun {
+				return nil
+			}
+
+			openSource := cachedStorage(func(namespace string) (*storage.Storage, error) {
+				return openStorage(settings, from, namespace, debug)
+			})
+			for _, rel := range rels {
+				s, err := openSource(rel.Namespace)
+				if err != nil {
+					return err
+				}
+				if _, err := s.Delete(rel.Name, rel.Version); err != nil {
+					return errors.Wrap(err, i18n.T("storage.delete", rel.Name, rel.Version, from))
+				}
+			}
+			fmt.Fprint(out, i18n.T("storage.deleted", revisionCount(rels), from))
+			return nil
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVar(&from, "from", "", i18n.T("storage.flagFrom", strings.Join(storageDrivers, ", ")))
+	f.StringVar(&to, "to", "", i18n.T("storage.flagTo"))
+	f.BoolVarP(&allNamespaces, "all-namespaces", "A", false, i18n.T("storage.flagMigrateAll"))
+	f.BoolVar(&overwrite, "overwrite", false, i18n.T("storage.flagMigrateOverwrite"))
+	f.BoolVar(&dryRun, "dry-run", false, i18n.T("storage.flagMigrateDryRun"))
+	f.BoolVar(&deleteSource, "delete-source", false, i18n.T("storage.flagDeleteSource"))
+	return cmd
+}
+
+// openStorage returns the release storage of a driver for one namespace, or
+// for all namespaces when namespace is empty.
+func openStorage(settings *cli.EnvSettings, driverName, namespace string, debug action.DebugLog) (*storage.Storage, error) {
+	if !slices.Contains(storageDrivers, driverName) && driverName != "" {
+		return nil, errors.New(i18n.T("storage.unknownDriver", driverName, strings.Join(storageDrivers, ", ")))
+	}
+	cfg := new(action.Configuration)
+	if err := cfg.Init(settings.RESTClientGetter(), namespace, driverName, debug); err != nil {
+		return nil, errors.Wrap(err, i18n.T("storage.open", driverName))
+	}
+	return cfg.Releases, nil
+}
+
+// cachedStorage opens the storage of each namespace once.
+func cachedStorage(open func(namespace string) (*storage.Storage, error)) func(namespace string) (*storage.Storage, error) {
+	stores := map[string]*storage.Storage{}
+	return func(namespace string) (*storage.Storage, error) {
+		if s, ok := stores[namespace]; ok {
+			return s, nil
+		}
+		s, err := open(namespace)
+		if err != nil {
+			return nil, err
+		}
+		stores[namespace] = s
+		return s, nil
+	}
+}
+
+// namespaceStorage opens the storage of the current $HELM_DRIVER for each
+// namespace. Records of other namespaces can only be created and deleted
+// through a storage of their own namespace.
+func namespaceStorage(settings *cli.EnvSettings, cfg *action.Configuration, debug action.DebugLog) func(namespace string) (*storage.Storage, error) {
+	if mem := memoryDriver(cfg); mem != nil {
+		return func(namespace string) (*storage.Storage, error) {
+			mem.SetNamespace(namespace)
+			return cfg.Releases, nil
+		}
+	}
+	return cachedStorage(func(namespace string) (*storage.Storage, error) {
+		return openStorage(settings, os.Getenv("HELM_DRIVER"), namespace, debug)
+	})
+}
+
+// memoryDriver returns the configured memory driver, which only lives as long
+// as this process and is therefore used instead of opening a new one.
+func memoryDriver(cfg *action.Configuration) *driver.Memory {
+	if cfg.Releases == nil {
+		return nil
+	}
+	mem, _ := cfg.Releases.Driver.(*driver.Memory)
+	return mem
+}
+
+// normalizeDriver maps the aliases of a driver name to one name.
+func normalizeDriver(name string) string {
+	switch name {
+	case "", "secret", "secrets":
+		return "secret"
+	case "configmap", "configmaps":
+		return "configmap"
+	}
+	return name
+}
+
+// selectReleases returns all revisions of the named releases, or of every
+// release when names is empty, sorted by namespace, name and revision.
+func selectReleases(store *storage.Storage, names []string) ([]*release.Release, error) {
+	all, err := store.ListReleases()
+	if err != nil {
+		return nil, err
+	}
+	var rels []*release.Release
+	for _, rel := range all {
+		if len(names) == 0 || slices.Contains(names, rel.Name) {
+			rels = append(rels, rel)
+		}
+	}
+	for _, name := range names {
+		if !slices.ContainsFunc(rels, func(r *release.Release) bool { return r.Name == name }) {
+			return nil, errors.New(i18n.T("storage.notFound", name))
+		}
+	}
+	sort.Slice(rels, func(i, j int) bool {
+		a, b := rels[i], rels[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Version < b.Version
+	})
+	return rels, nil
+}
+
+// copyReleases stores rels with the storage open returns for their
+// namespace, skipping revisions that exist unless overwrite is set.
+func copyReleases(out io.Writer, rels []*release.Release, open func(namespace string) (*storage.Storage, error), overwrite, dryRun bool) error {
+	var copied, skipped []*release.Release
+	for _, rel := range rels {
+		s, err := open(rel.Namespace)
+		if err != nil {
+			return err
+		}
+
+		_, getErr := s.Get(rel.Name, rel.Version)
+		exists := getErr == nil
+		switch {
+		case exists && !overwrite:
+			skipped = append(skipped, rel)
+			continue
+		case dryRun:
+		case exists:
+			err = s.Update(rel)
+		default:
+			err = s.Create(rel)
+		}
+		if err != nil {
+			return errors.Wrap(err, i18n.T("storage.store", rel.Name, rel.Version))
+		}
+		copied = append(copied, rel)
+	}
+
+	key := "storage.imported"
+	if dryRun {
+		key = "storage.wouldImport"
+	}
+	fmt.Fprint(out, i18n.T(key, revisionCount(copied)))
+	if len(skipped) > 0 {
+		fmt.Fprint(out, i18n.T("storage.skipped", revisionCount(skipped)))
+	}
+	return nil
+}
+
+// revisionCount describes a set of records, e.g. "12 revision(s) of 3 release(s)".
+func revisionCount(rels []*release.Release) string {
+	names := map[string]bool{}
+	for _, rel := range rels {
+		names[rel.Namespace+"/"+rel.Name] = true
+	}
+	return i18n.T("storage.revisionCount", len(rels), len(names))
+}
+
+func marshalReleases(rels []*release.Release) ([]byte, error) {
+	exported := make([]exportedRelease, 0, len(rels))
+	for _, rel := range rels {
+		exported = append(exported, exportedRelease{Release: rel, Labels: rel.Labels})
+	}
+	return yaml.Marshal(exported)
+}
+
+func unmarshalReleases(b []byte) ([]*release.Release, error) {
+	var exported []exportedRelease
+	if err := yaml.Unmarshal(b, &exported); err != nil {
+		return nil, err
+	}
+	rels := make([]*release.Release, 0, len(exported))
+	for i, e := range exported {
+		if e.Release == nil || e.Name == "" {
+			return nil, errors.New(i18n.T("storage.notRelease", i+1))
+		}
+		e.Release.Labels = e.Labels
+		rels = append(rels, e.Release)
+	}
+	return rels, nil
+}
@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"bytes"
+	"maps"
+	"strings"
+	"testing"
+
+	"helm.sh/helm/v4/pkg/release"
+	"helm.sh/helm/v4/pkg/storage"
+	"helm.sh/helm/v4/pkg/storage/driver"
+)
+
+func storageTestReleases() []*release.Release {
+	first := release.Mock(&release.MockReleaseOptions{Name: "pulsar", Namespace: "pulsar", Version: 1, Status: release.StatusSuperseded})
+	second := release.Mock(&release.MockReleaseOptions{Name: "pulsar", Namespace: "pulsar", Version: 2, Status: release.StatusDeployed})
+	second.Labels = map[string]string{"team": "messaging"}
+	other := release.Mock(&release.MockReleaseOptions{Name: "kafka", Namespace: "streaming", Version: 1, Status: release.StatusFailed})
+	return []*release.Release{first, second, other}
+}
+
+func TestExportImportRoundTrip(t *testing.T) {
+	rels := storageTestReleases()
+	b, err := marshalReleases(rels)
+	if err != nil {
+		t.Fatal(err)
+	}
+	imported, err := unmarshalReleases(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(imported) != len(rels) {
+		t.Fatalf("expected %d releases, got %d", len(rels), len(imported))
+	}
+	for i, rel := range imported {
+		want := rels[i]
+		if rel.Name != want.Name || rel.Namespace != want.Namespace || rel.Version != want.Version || rel.Info.Status != want.Info.Status {
+			t.Errorf("expected %s/%s revision %d %s, got %s/%s revision %d %s",
+				want.Namespace, want.Name, want.Version, want.Info.Status, rel.Namespace, rel.Name, rel.Version, rel.Info.Status)
+		}
+		if !maps.Equal(rel.Labels, want.Labels) {
+			t.Errorf("%s revision %d: expected labels %v, got %v", rel.Name, rel.Version, want.Labels, rel.Labels)
+		}
+		if rel.Manifest != want.Manifest || rel.Chart.Metadata.Version != want.Chart.Metadata.Version {
+			t.Errorf("%s revision %d: manifest or chart differ after the round trip", rel.Name, rel.Version)
+		}
+	}
+
+	again, err := marshalReleases(imported)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(again, b) {
+		t.Errorf("expected the export of imported releases to be identical:\n--- first\n%s\n--- second\n%s", b, again)
+	}
+}
+
+func TestUnmarshalReleases(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    int
+		wantErr bool
+	}{
+		{"empty list", "[]\n", 0, false},
+		{"plain release list", "- name: pulsar\n  namespace: pulsar\n  version: 1\n", 1, false},
+		{"not a list", "name: pulsar\n", 0, true},
+		{"record without a name", "- version: 1\n", 0, true},
+	}
+	for _, tt := range tests {
+		rels, err := unmarshalReleases([]byte(tt.input))
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: expected error %t, got '%v'", tt.name, tt.wantErr, err)
+			continue
+		}
+		if len(rels) != tt.want {
+			t.Errorf("%s: expected %d release(s), got %d", tt.name, tt.want, len(rels))
+		}
+	}
+}
+
+func TestCopyReleases(t *testing.T) {
+	tests := []struct {
+		name      string
+		overwrite bool
+		dryRun    bool
+		// wantStatus is the status of pulsar revision 2 after the copy,
+		// which already exists as failed in the target.
+		wantStatus release.Status
+		wantCount  int
+		wantOutput string
+	}{
+		{"skip existing", false, false, release.StatusFailed, 3, "Skipped 1 revision(s) of 1 release(s)"},
+		{"overwrite existing", true, false, release.StatusDeployed, 3, "Imported 3 revision(s) of 2 release(s)"},
+		{"dry run", true, true, release.StatusFailed, 1, "Would import 3 revision(s) of 2 release(s)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mem := driver.NewMemory()
+			store := storage.Init(mem)
+			existing := release.Mock(&release.MockReleaseOptions{Name: "pulsar", Namespace: "pulsar", Version: 2, Status: release.StatusFailed})
+			mem.SetNamespace("pulsar")
+			if err := store.Create(existing); err != nil {
+				t.Fatal(err)
+			}
+			open := func(namespace string) (*storage.Storage, error) {
+				mem.SetNamespace(namespace)
+				return store, nil
+			}
+
+			var out strings.Builder
+			if err := copyReleases(&out, storageTestReleases(), open, tt.overwrite, tt.dryRun); err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(out.String(), tt.wantOutput) {
+				t.Errorf("expected output to contain %q, got %q", tt.wantOutput, out.String())
+			}
+
+			mem.SetNamespace("pulsar")
+			rel, err := store.Get("pulsar", 2)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if rel.Info.Status != tt.wantStatus {
+				t.Errorf("expected revision 2 to be %s, got %s", tt.wantStatus, rel.Info.Status)
+			}
+			mem.SetNamespace("")
+			all, err := store.ListReleases()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(all) != tt.wantCount {
+				t.Errorf("expected %d stored revision(s), got %d", tt.wantCount, len(all))
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"helm.sh/helm/v4/pkg/cli"
 	"io"
+	"log"
 	"os"
 	"path"
 	"path/filepath"
@@ -15,6 +16,7 @@ import (
 
 	"helm.sh/helm/v4/pkg/release"
 
+	"github.com/huangxiaofeng10047/go-cli-example/cmd/i18n"
 	"github.com/spf13/cobra"
 
 	"helm.sh/helm/v4/cmd/helm/require"
@@ -24,14 +26,6 @@ import (
 	releaseutil "helm.sh/helm/v4/pkg/release/util"
 )
 
-const templateDesc = `
-Render chart templates locally and display the output.
-
-Any values that would normally be looked up or retrieved in-cluster will be
-faked locally. Additionally, none of the server-side testing of chart validity
-(e.g. whether an API is supported) is done.
-`
-
 func newTemplateCmd(settings *cli.EnvSettings, cfg *action.Configuration, out io.Writer, debug action.DebugLog) *cobra.Command {
 	var validate bool
 	var includeCrds bool
@@ -41,11 +35,12 @@ func newTemplateCmd(settings *cli.EnvSettings, cfg *action.Configuration, out io
 	var kubeVersion string
 	var extraAPIs []string
 	var showFiles []string
+	var outputLayout string
 
 	cmd := &cobra.Command{
 		Use:   "template [NAME] [CHART]",
-		Short: "locally render templates",
-		Long:  templateDesc,
+		Short: i18n.T("template.short"),
+		Long:  i18n.T("template.long"),
 		Args:  require.MinimumNArgs(1),
 		ValidArgsFunction: func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 			return compInstall(settings, args, toComplete, client)
@@ -78,7 +73,19 @@ func newTemplateCmd(settings *cli.EnvSettings, cfg *action.Configuration, out io
 			client.ClientOnly = !validate
 			client.APIVersions = chartutil.VersionSet(extraAPIs)
 			client.IncludeCRDs = includeCrds
+
+			// Manifests are always rendered in memory and written here, so
+			// that every layout gets the same index.
+			outputDir := client.OutputDir
+			if outputDir != "" && !slices.Contains(outputLayouts, outputLayout) {
+				return fmt.Errorf("%s", i18n.T("template.badLayout", outputLayout, strings.Join(outputLayouts, ", ")))
+			}
+			client.OutputDir = ""
+
 			rel, err := runInstall(settings, args, client, valueOpts, out, debug)
+			if outputDir != "" && client.UseReleaseName {
+				outputDir = filepath.Join(outputDir, client.ReleaseName)
+			}
 
 			if err != nil && !settings.Debug {
 				if rel != nil {
@@ -93,32 +100,16 @@ func newTemplateCmd(settings *cli.EnvSettings, cfg *action.Configuration, out io
 				var manifests bytes.Buffer
 				fmt.Fprintln(&manifests, strings.TrimSpace(rel.Manifest))
 				if !client.DisableHooks {
-					fileWritten := make(map[string]bool)
 					for _, m := range rel.Hooks {
 						if skipTests && isTestHook(m) {
 							continue
 						}
-						if client.OutputDir == "" {
-							fmt.Fprintf(&manifests, "---\n# Source: %s\n%s\n", m.Path, m.Manifest)
-						} else {
-							newDir := client.OutputDir
-							if client.UseReleaseName {
-								newDir = filepath.Join(client.OutputDir, client.ReleaseName)
-							}
-							_, err := os.Stat(filepath.Join(newDir, m.Path))
-							if err == nil {
-								fileWritten[m.Path] = true
-							}
-
-							err = writeToFile(newDir, m.Path, m.Manifest, fileWritten[m.Path])
-							if err != nil {
-								return err
-							}
-						}
-
+						fmt.Fprintf(&manifests, "---\n# Source: %s\n%s\n", m.Path, m.Manifest)
 					}
 				}
 
+				policyErr := checkManifestPolicy(sortedManifests(manifests.String()))
+
 				// if we have a list of files to render, then check that each of the
 				// provided files exists in the chart.
 				if len(showFiles) > 0 {
@@ -163,12 +154,25 @@ func newTemplateCmd(settings *cli.EnvSettings, cfg *action.Configuration, out io
 							return fmt.Errorf("could not find template %s in chart", f)
 						}
 					}
-					for _, m := range manifestsToRender {
-						fmt.Fprintf(out, "---\n%s\n", m)
+					if outputDir != "" {
+						if err := writeOutputLayout(outputDir, outputLayout, manifestsToRender); err != nil {
+							return err
+						}
+					} else {
+						for _, m := range manifestsToRender {
+							fmt.Fprintf(out, "---\n%s\n", m)
+						}
+					}
+				} else if outputDir != "" {
+					if err := writeOutputLayout(outputDir, outputLayout, sortedManifests(manifests.String())); err != nil {
+						return err
 					}
 				} else {
 					fmt.Fprintf(out, "%s", manifests.String())
 				}
+				if err == nil {
+					err = policyErr
+				}
 			}
 
 			return err
@@ -179,6 +183,7 @@ func newTemplateCmd(settings *cli.EnvSettings, cfg *action.Configuration, out io
 	addInstallFlags(settings, cmd, f, client, valueOpts)
 	f.StringArrayVarP(&showFiles, "show-only", "s", []string{}, "only show manifests rendered from the given templates")
 	f.StringVar(&client.OutputDir, "output-dir", "", "writes the executed templates to files in output-dir instead of stdout")
+	f.StringVar(&outputLayout, "output-layout", layoutTemplate, i18n.T("template.flagOutputLayout", strings.Join(outputLayouts, ", ")))
 	f.BoolVar(&validate, "validate", false, "validate your manifests against the Kubernetes cluster you are currently pointing at. This is the same validation performed on an install")
 	f.BoolVar(&includeCrds, "include-crds", false, "include CRDs in the templated output")
 	f.BoolVar(&skipTests, "skip-tests", false, "skip tests from templated output")
@@ -188,6 +193,13 @@ func newTemplateCmd(settings *cli.EnvSettings, cfg *action.Configuration, out io
 	f.BoolVar(&client.UseReleaseName, "release-name", false, "use release name in the output-dir path.")
 	bindPostRenderFlag(cmd, &client.PostRenderer)
 
+	err := cmd.RegisterFlagCompletionFunc("output-layout", func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		return outputLayouts, cobra.ShellCompDirectiveNoFileComp
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	return cmd
 }
 
@@ -195,36 +207,8 @@ func isTestHook(h *release.Hook) bool {
 	return slices.Contains(h.Events, release.HookTest)
 }
 
-// The following functions (writeToFile, createOrOpenFile, and ensureDirectoryForFile)
-// are copied from the actions package. This is part of a change to correct a
-// bug introduced by #8156. As part of the todo to refactor renderResources
-// this duplicate code should be removed. It is added here so that the API
-// surface area is as minimally impacted as possible in fixing the issue.
-func writeToFile(outputDir string, name string, data string, appendData bool) error {
-	outfileName := strings.Join([]string{outputDir, name}, string(filepath.Separator))
-
-	err := ensureDirectoryForFile(outfileName)
-	if err != nil {
-		return err
-	}
-
-	f, err := createOrOpenFile(outfileName, appendData)
-	if err != nil {
-		return err
-	}
-
-	defer f.Close()
-
-	_, err = f.WriteString(fmt.Sprintf("---\n# Source: %s\n%s\n", name, data))
-
-	if err != nil {
-		return err
-	}
-
-	fmt.Printf("wrote %s\n", outfileName)
-	return nil
-}
-
+// The following functions (createOrOpenFile and ensureDirectoryForFile)
+// are copied from the actions package.
 func createOrOpenFile(filename string, appendData bool) (*os.File, error) {
 	if appendData {
 		return os.OpenFile(filename, os.O_APPEND|os.O_WRONLY, 0600)
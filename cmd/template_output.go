@@ -0,0 +1,212 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/huangxiaofeng10047/go-cli-example/cmd/i18n"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+
+	releaseutil "helm.sh/helm/v4/pkg/release/util"
+)
+
+// Layouts for 'template --output-dir'.
+const (
+	// layoutTemplate writes one file per chart template, like Helm does.
+	layoutTemplate = "template"
+	// layoutKind writes one file per Kubernetes kind.
+	layoutKind = "kind"
+	// layoutComponent writes one file per Pulsar component.
+	layoutComponent = "component"
+	// layoutObject writes one file per rendered object.
+	layoutObject = "object"
+)
+
+var outputLayouts = []string{layoutTemplate, layoutKind, layoutComponent, layoutObject}
+
+// outputIndexFile is written to the output directory next to the manifests.
+const outputIndexFile = "index.json"
+
+// noComponent is used for objects without a component label.
+const noComponent = "common"
+
+// renderedObject is a single manifest document produced by 'template'.
+type renderedObject struct {
+	source string
+	body   string
+
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Metadata   struct {
+		Name      string            `json:"name"`
+		Namespace string            `json:"namespace,omitempty"`
+		Labels    map[string]string `json:"labels,omitempty"`
+	} `json:"metadata"`
+}
+
+// component returns the Pulsar component the object belongs to.
+func (o *renderedObject) component() string {
+	for _, l := range []string{"component", "app.kubernetes.io/component"} {
+		if c := o.Metadata.Labels[l]; c != "" {
+			return c
+		}
+	}
+	return noComponent
+}
+
+// indexEntry describes one object in index.json.
+type indexEntry struct {
+	Group     string `json:"group,omitempty"`
+	Version   string `json:"version"`
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	Component string `json:"component,omitempty"`
+	Source    string `json:"source"`
+	File      string `json:"file"`
+	SHA256    string `json:"sha256"`
+}
+
+// parseRenderedManifests splits rendered manifests into objects, keeping the
+// order they were rendered in. Every document is expected to start with the
+// '# Source:' comment written by the renderer.
+func parseRenderedManifests(manifests []string) ([]*renderedObject, error) {
+	var objs []*renderedObject
+	for _, m := range manifests {
+		o := &renderedObject{body: m}
+		if strings.HasPrefix(m, "# Source: ") {
+			line, rest, _ := strings.Cut(m, "\n")
+			o.source = strings.TrimPrefix(line, "# Source: ")
+			o.body = strings.TrimSpace(rest)
+		}
+		if o.body == "" {
+			continue
+		}
+		if err := yaml.Unmarshal([]byte(o.body), o); err != nil {
+			return nil, errors.Wrap(err, i18n.T("template.parseManifest", o.source))
+		}
+		// Templates that render to comments only are not objects.
+		if o.Kind == "" {
+			continue
+		}
+		objs = append(objs, o)
+	}
+	return objs, nil
+}
+
+// sortedManifests returns the documents of a rendered manifest stream in order.
+func sortedManifests(manifests string) []string {
+	split := releaseutil.SplitManifests(manifests)
+	keys := make([]string, 0, len(split))
+	for k := range split {
+		keys = append(keys, k)
+	}
+	sort.Sort(releaseutil.BySplitManifestsOrder(keys))
+
+	docs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		docs = append(docs, split[k])
+	}
+	return docs
+}
+
+// layoutFileName returns the file, relative to the output directory, that
+// the object is written to.
+func layoutFileName(layout string, o *renderedObject) string {
+	kind := strings.ToLower(o.Kind)
+	switch layout {
+	case layoutKind:
+		return kind + ".yaml"
+	case layoutComponent:
+		return o.component() + ".yaml"
+	case layoutObject:
+		return filepath.Join(o.component(), kind+"-"+o.Metadata.Name+".yaml")
+	}
+	return o.source
+}
+
+// writeOutputLayout writes the rendered manifests to outputDir using the
+// given layout, followed by an index of every object.
+func writeOutputLayout(outputDir, layout string, manifests []string) error {
+	objs, err := parseRenderedManifests(manifests)
+	if err != nil {
+		return err
+	}
+
+	written := map[string]bool{}
+	index := make([]indexEntry, 0, len(objs))
+	for _, o := range objs {
+		name := layoutFileName(layout, o)
+		if err := writeObjectToFile(outputDir, name, o, written[name]); err != nil {
+			return err
+		}
+		written[name] = true
+
+		group, version := "", o.APIVersion
+		if i := strings.LastIndex(o.APIVersion, "/"); i >= 0 {
+			group, version = o.APIVersion[:i], o.APIVersion[i+1:]
+		}
+		sum := sha256.Sum256([]byte(o.body))
+		index = append(index, indexEntry{
+			Group:     group,
+			Version:   version,
+			Kind:      o.Kind,
+			Name:      o.Metadata.Name,
+			Namespace: o.Metadata.Namespace,
+			Component: o.component(),
+			Source:    o.source,
+			File:      filepath.ToSlash(name),
+			SHA256:    hex.EncodeToString(sum[:]),
+		})
+	}
+
+	sort.SliceStable(index, func(i, j int) bool {
+		if index[i].File != index[j].File {
+			return index[i].File < index[j].File
+		}
+		if index[i].Kind != index[j].Kind {
+			return index[i].Kind < index[j].Kind
+		}
+		return index[i].Name < index[j].Name
+	})
+	b, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return err
+	}
+	indexFile := filepath.Join(outputDir, outputIndexFile)
+	if err := os.WriteFile(indexFile, append(b, '\n'), 0644); err != nil {
+		return err
+	}
+	fmt.Println(i18n.T("template.wrote", indexFile))
+	return nil
+}
+
+// writeObjectToFile writes a single object. The source comment names the
+// template the object was rendered from, whatever file it ends up in.
+func writeObjectToFile(outputDir, name string, o *renderedObject, appendData bool) error {
+	outfileName := filepath.Join(outputDir, name)
+	if err := ensureDirectoryForFile(outfileName); err != nil {
+		return err
+	}
+
+	f, err := createOrOpenFile(outfileName, appendData)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err := fmt.Fprintf(f, "---\n# Source: %s\n%s\n", o.source, o.body); err != nil {
+		return err
+	}
+	if !appendData {
+		fmt.Println(i18n.T("template.wrote", outfileName))
+	}
+	return nil
+}
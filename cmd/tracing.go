@@ -0,0 +1,245 @@
+package cmd
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"runtime/trace"
+	"strconv"
+	"sync"
+	"time"
+
+	"helm.sh/helm/v4/pkg/kube"
+
+	"github.com/huangxiaofeng10047/go-cli-example/cmd/i18n"
+	"github.com/huangxiaofeng10047/go-cli-example/cmd/progress"
+)
+
+// Phases of an install or upgrade recorded as spans.
+const (
+	phaseChartLocate     = "chart locate"
+	phaseDependencyCheck = "dependency check"
+	phaseRender          = "render"
+	phaseApply           = "apply"
+	phaseWait            = "wait"
+	phaseTest            = "test"
+)
+
+// tracer records the spans of the running command. It is nil unless
+// --pprof-dir is set.
+var tracer *spanRecorder
+
+// spanRecorder collects spans of one trace, which are children of the span
+// of the command.
+type spanRecorder struct {
+	mu      sync.Mutex
+	traceID string
+	root    *span
+	spans   []*span
+}
+
+// span is a timed phase. The methods of a nil span do nothing, so phases can
+// be recorded without checking whether tracing is enabled.
+type span struct {
+	name     string
+	spanID   string
+	parentID string
+	start    time.Time
+	end      time.Time
+	attrs    map[string]string
+	err      error
+	region   *trace.Region
+}
+
+func newSpanRecorder(command string) *spanRecorder {
+	r := &spanRecorder{traceID: randomID(16)}
+	r.root = &span{name: command, spanID: randomID(8), start: time.Now()}
+	return r
+}
+
+// startSpan starts a phase of the running command. attrs are key value pairs.
+func startSpan(name string, attrs ...string) *span {
+	if tracer == nil {
+		return nil
+	}
+	s := &span{
+		name:     name,
+		spanID:   randomID(8),
+		parentID: tracer.root.spanID,
+		start:    time.Now(),
+		attrs:    map[string]string{},
+	}
+	for i := 0; i+1 < len(attrs); i += 2 {
+		s.attrs[attrs[i]] = attrs[i+1]
+	}
+	if trace.IsEnabled() {
+		s.region = trace.StartRegion(context.Background(), name)
+	}
+	tracer.mu.Lock()
+	tracer.spans = append(tracer.spans, s)
+	tracer.mu.Unlock()
+	return s
+}
+
+// finish ends the span. A non-nil err marks it as failed. Only the first call
+// has an effect.
+func (s *span) finish(err error) {
+	if s == nil {
+		return
+	}
+	tracer.mu.Lock()
+	defer tracer.mu.Unlock()
+	if !s.end.IsZero() {
+		return
+	}
+	s.end = time.Now()
+	s.err = err
+	if s.region != nil {
+		s.region.End()
+	}
+}
+
+// finishPhase ends the open spans of a phase.
+func finishPhase(name string) {
+	if tracer == nil {
+		return
+	}
+	tracer.mu.Lock()
+	var open []*span
+	for _, s := range tracer.spans {
+		if s.name == name && s.end.IsZero() {
+			open = append(open, s)
+		}
+	}
+	tracer.mu.Unlock()
+	for _, s := range open {
+		s.finish(nil)
+	}
+}
+
+// writeOTLP ends all spans and writes them to path in the OTLP/JSON format,
+// which trace viewers such as Jaeger can import.
+func (r *spanRecorder) writeOTLP(path string) error {
+	r.root.finish(nil)
+	for _, s := range r.spans {
+		s.finish(nil)
+	}
+
+	type attribute struct {
+		Key   string            `json:"key"`
+		Value map[string]string `json:"value"`
+	}
+	type status struct {
+		Code    int    `json:"code"`
+		Message string `json:"message,omitempty"`
+	}
+	type otlpSpan struct {
+		TraceID           string      `json:"traceId"`
+		SpanID            string      `json:"spanId"`
+		ParentSpanID      string      `json:"parentSpanId,omitempty"`
+		Name              string      `json:"name"`
+		Kind              int         `json:"kind"`
+		StartTimeUnixNano string      `json:"startTimeUnixNano"`
+		EndTimeUnixNano   string      `json:"endTimeUnixNano"`
+		Attributes        []attribute `json:"attributes,omitempty"`
+		Status            status      `json:"status"`
+	}
+
+	var spans []otlpSpan
+	for _, s := range append([]*span{r.root}, r.spans...) {
+		o := otlpSpan{
+			TraceID:           r.traceID,
+			SpanID:            s.spanID,
+			ParentSpanID:      s.parentID,
+			Name:              s.name,
+			Kind:              1, // SPAN_KIND_INTERNAL
+			StartTimeUnixNano: strconv.FormatInt(s.start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(s.end.UnixNano(), 10),
+		}
+		for k, v := range s.attrs {
+			o.Attributes = append(o.Attributes, attribute{Key: k, Value: map[string]string{"stringValue": v}})
+		}
+		if s.err != nil {
+			o.Status = status{Code: 2, Message: s.err.Error()}
+		}
+		spans = append(spans, o)
+	}
+
+	doc := map[string]interface{}{
+		"resourceSpans": []interface{}{map[string]interface{}{
+			"resource": map[string]interface{}{
+				"attributes": []attribute{{Key: "service.name", Value: map[string]string{"stringValue": "gce"}}},
+			},
+			"scopeSpans": []interface{}{map[string]interface{}{
+				"scope": map[string]string{"name": "github.com/huangxiaofeng10047/go-cli-example/cmd"},
+				"spans": spans,
+			}},
+		}},
+	}
+	b, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0644)
+}
+
+func randomID(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// tracingKubeClient records the requests that change the cluster as apply
+// spans and the waits for resources as wait spans. The first of them ends the
+// render phase. They also start the phases of progress, if any.
+type tracingKubeClient struct {
+	*kube.Client
+	progress *progress.Progress
+}
+
+func (c *tracingKubeClient) Create(resources kube.ResourceList) (*kube.Result, error) {
+	finishPhase(phaseRender)
+	c.progress.Phase(i18n.T("progress.apply"))
+	s := startSpan(phaseApply, "operation", "create", "resources", strconv.Itoa(len(resources)))
+	res, err := c.Client.Create(resources)
+	s.finish(err)
+	return res, err
+}
+
+func (c *tracingKubeClient) Update(original, target kube.ResourceList, force bool) (*kube.Result, error) {
+	finishPhase(phaseRender)
+	c.progress.Phase(i18n.T("progress.apply"))
+	s := startSpan(phaseApply, "operation", "update", "resources", strconv.Itoa(len(target)))
+	res, err := c.Client.Update(original, target, force)
+	s.finish(err)
+	return res, err
+}
+
+func (c *tracingKubeClient) Wait(resources kube.ResourceList, timeout time.Duration) error {
+	finishPhase(phaseRender)
+	c.progress.Phase(i18n.T("progress.wait"))
+	s := startSpan(phaseWait, "resources", strconv.Itoa(len(resources)))
+	err := c.Client.Wait(resources, timeout)
+	s.finish(err)
+	return err
+}
+
+func (c *tracingKubeClient) WaitWithJobs(resources kube.ResourceList, timeout time.Duration) error {
+	finishPhase(phaseRender)
+	c.progress.Phase(i18n.T("progress.wait"))
+	s := startSpan(phaseWait, "resources", strconv.Itoa(len(resources)), "jobs", "true")
+	err := c.Client.WaitWithJobs(resources, timeout)
+	s.finish(err)
+	return err
+}
+
+func (c *tracingKubeClient) WatchUntilReady(resources kube.ResourceList, timeout time.Duration) error {
+	finishPhase(phaseRender)
+	c.progress.Phase(i18n.T("progress.hooks"))
+	s := startSpan(phaseWait, "resources", strconv.Itoa(len(resources)), "hook", "true")
+	err := c.Client.WatchUntilReady(resources, timeout)
+	s.finish(err)
+	return err
+}
@@ -20,9 +20,11 @@ import (
 	"context"
 	"fmt"
 	installevent "github.com/huangxiaofeng10047/go-cli-example/cmd/event"
+	"github.com/huangxiaofeng10047/go-cli-example/cmd/i18n"
 	"helm.sh/helm/v4/pkg/cli"
 	"io"
 	"log"
+	"log/slog"
 	"os"
 	"os/signal"
 	"syscall"
@@ -86,7 +88,10 @@ func newUpgradeCmd(settings *cli.EnvSettings, cfg *action.Configuration, out io.
 	client := action.NewUpgrade(cfg)
 	valueOpts := &values.Options{}
 	var outfmt output.Format
+	var skipChecks bool
 	var createNamespace bool
+	var checkAPIsFirst bool
+	var checkAPIsKubeVersion string
 
 	cmd := &cobra.Command{
 		Use:   "upgrade [RELEASE] [CHART]",
@@ -102,9 +107,17 @@ func newUpgradeCmd(settings *cli.EnvSettings, cfg *action.Configuration, out io.
 			}
 			return noMoreArgsComp()
 		},
-		RunE: func(_ *cobra.Command, args []string) error {
+		RunE: func(_ *cobra.Command, args []string) (err error) {
 			client.Namespace = settings.Namespace()
-			event := installevent.NewInstallEvent(testConfig)
+			prog := newProgress(out, outfmt)
+			defer func() { prog.Finish(err) }()
+			reportKubeProgress(cfg, prog)
+			event, err := newInstallEvent(settings, cfg, args[0], prog, debug)
+			if err != nil {
+				return err
+			}
+			started := time.Now()
+			recordDeploy := !isDryRunOption(client.DryRunOption)
 
 			registryClient, err := newRegistryClient(settings, client.CertFile, client.KeyFile, client.CaFile,
 				client.InsecureSkipTLSverify, client.PlainHTTP, client.Username, client.Password)
@@ -161,10 +174,17 @@ func newUpgradeCmd(settings *cli.EnvSettings, cfg *action.Configuration, out io.
 						instClient.Replace = true
 					}
 
+					prog.Phase(i18n.T("progress.render"))
 					rel, err := runInstall(settings, args, instClient, valueOpts, out, debug)
 					if err != nil {
 						return err
 					}
+					prog.Stop()
+					if recordDeploy {
+						if err := recordDeployInfo(cfg, rel, started); err != nil {
+							debug("unable to record deploy info: %s", err)
+						}
+					}
 					return outfmt.Write(out, &statusPrinter{
 						release:      rel,
 						debug:        settings.Debug,
@@ -181,7 +201,9 @@ func newUpgradeCmd(settings *cli.EnvSettings, cfg *action.Configuration, out io.
 				client.Version = ">0.0.0-0"
 			}
 
+			sp := startSpan(phaseChartLocate, "chart", args[1])
 			chartPath, err := client.ChartPathOptions.LocateChart(args[1], settings)
+			sp.finish(err)
 			if err != nil {
 				return err
 			}
@@ -190,6 +212,7 @@ func newUpgradeCmd(settings *cli.EnvSettings, cfg *action.Configuration, out io.
 				return err
 			}
 
+			applyProfileValues(valueOpts, debug)
 			p := getter.All(settings)
 			vals, err := valueOpts.MergeValues(p)
 			if err != nil {
@@ -202,6 +225,8 @@ func newUpgradeCmd(settings *cli.EnvSettings, cfg *action.Configuration, out io.
 				return err
 			}
 			if req := ch.Metadata.Dependencies; req != nil {
+				sp := startSpan(phaseDependencyCheck)
+				defer sp.finish(nil)
 				if err := action.CheckDependencies(ch, req); err != nil {
 					err = errors.Wrap(err, "An error occurred while checking for chart dependencies. You may need to run `helm dependency build` to fetch missing dependencies")
 					if client.DependencyUpdate {
@@ -226,6 +251,19 @@ func newUpgradeCmd(settings *cli.EnvSettings, cfg *action.Configuration, out io.
 						return err
 					}
 				}
+				sp.finish(nil)
+			}
+
+			if err := checkStrictValues(ch, valueOpts, p, debug); err != nil {
+				return err
+			}
+			if err := checkChartPolicy(ch, vals, args[0], client.Namespace, true, client.PostRenderer, debug); err != nil {
+				return err
+			}
+			if checkAPIsFirst {
+				if err := preflightAPIs(cfg, ch, vals, args[0], client.Namespace, checkAPIsKubeVersion, client.PostRenderer, debug); err != nil {
+					return err
+				}
 			}
 
 			if ch.Metadata.Deprecated {
@@ -243,12 +281,35 @@ func newUpgradeCmd(settings *cli.EnvSettings, cfg *action.Configuration, out io.
 			signal.Notify(cSignal, os.Interrupt, syscall.SIGTERM)
 			go func() {
 				<-cSignal
-				fmt.Fprintf(out, "Release %s has been cancelled.\n", args[0])
+				if outfmt == output.Table {
+					fmt.Fprint(out, i18n.T("release.cancelled", args[0]))
+				} else {
+					slog.Warn("Release has been cancelled", "release", args[0])
+				}
 				cancel()
 			}()
 
+			render := startSpan(phaseRender, "release", args[0])
+			prog.Phase(i18n.T("progress.render"))
 			rel, err := client.RunWithContext(ctx, args[0], ch, vals)
-			err = outfmt.Write(out, &statusPrinter{
+			render.finish(err)
+			if err != nil {
+				return errors.Wrap(err, "UPGRADE FAILED")
+			}
+			prog.Stop()
+			if recordDeploy {
+				if err := recordDeployInfo(cfg, rel, started); err != nil {
+					debug("unable to record deploy info: %s", err)
+				}
+			}
+
+			report := newDeployReport(out, outfmt)
+			defer func() {
+				if ferr := report.flush(); err == nil {
+					err = ferr
+				}
+			}()
+			err = report.writeStatus(&statusPrinter{
 				release:      rel,
 				debug:        settings.Debug,
 				showMetadata: false,
@@ -262,21 +323,51 @@ func newUpgradeCmd(settings *cli.EnvSettings, cfg *action.Configuration, out io.
 				fmt.Fprintf(out, "Release %q has been upgraded. Happy Helming!\n", args[0])
 			}
 
+			sp = startSpan(phaseTest, "release", rel.Name)
 			err = event.QueryRunningPod(settings, ctx, cfg, out)
 			if err != nil {
+				sp.finish(err)
 				return err
 			}
+			var failedChecks bool
+			if !skipChecks {
+				checks, err := runPostDeployChecks(ctx, settings, cfg, event, rel, prog, debug)
+				if err != nil {
+					sp.finish(err)
+					return err
+				}
+				if err := report.writeChecks(checks); err != nil {
+					sp.finish(err)
+					return err
+				}
+				failedChecks = checksFailed(checks)
+			}
 			taskId, err2 := event.FinishInstall(settings, cfg, args[0])
 			if err2 != nil {
-				return errors.Wrap(err, "INSTALLATION FAILED")
+				sp.finish(err2)
+				return errors.Wrap(err2, "UPGRADE FAILED")
 			}
-			fmt.Fprintln(out, "Waiting for testcase finish...")
 
-			err = event.WaitTestCaseFinish(settings, ctx, out, taskId)
-			if err != nil {
-				return err
+			tests, err := event.WaitTestCaseFinish(settings, ctx, taskId)
+			sp.finish(err)
+			result := testResultError
+			if err == nil {
+				result = tests.Result
+				err = report.writeTests(tests)
+			}
+			if err == nil {
+				err = tests.Err()
+			}
+			if err == nil && failedChecks {
+				result = installevent.TestResultFailed
+				err = errors.New(i18n.T("plugin.checksFailed"))
+			}
+			if recordDeploy {
+				if rerr := recordTestResult(cfg, rel, result); rerr != nil {
+					debug("unable to record test result: %s", rerr)
+				}
 			}
-			return nil
+			return err
 		},
 	}
 
@@ -312,6 +403,11 @@ func newUpgradeCmd(settings *cli.EnvSettings, cfg *action.Configuration, out io.
 	f.BoolVar(&client.TakeOwnership, "take-ownership", false, "if set, upgrade will ignore the check for helm annotations and take ownership of the existing resources")
 	addChartPathOptionsFlags(f, &client.ChartPathOptions)
 	addValueOptionsFlags(f, valueOpts)
+	addStrictValuesFlag(f)
+	addPolicyFlags(f)
+	f.BoolVar(&checkAPIsFirst, "check-apis", false, i18n.T("checkAPIs.flagCheckAPIs"))
+	f.StringVar(&checkAPIsKubeVersion, "check-apis-kube-version", "", i18n.T("checkAPIs.flagCheckAPIsKubeVersion"))
+	f.BoolVar(&skipChecks, "no-checks", false, i18n.T("plugin.flagNoChecks"))
 	bindOutputFlag(cmd, &outfmt)
 	bindPostRenderFlag(cmd, &client.PostRenderer)
 
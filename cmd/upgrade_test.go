@@ -0,0 +1,34 @@
+package cmd
+
+import "testing"
+
+func TestUpgradeCmd(t *testing.T) {
+	tests := []cmdTestCase{
+		{
+			name:     "upgrade and run the test cases",
+			cmd:      "upgrade pt testdata/testcharts/mini --set replicas=3",
+			scenario: "scenario-upgrade.yaml",
+			golden:   "upgrade.golden",
+		},
+		{
+			name:     "upgrade with json output",
+			cmd:      "upgrade pt testdata/testcharts/mini -o json",
+			scenario: "scenario-upgrade.yaml",
+			golden:   "upgrade-json.golden",
+		},
+		{
+			name:     "install a missing release",
+			cmd:      "upgrade pt testdata/testcharts/mini --install",
+			scenario: "scenario-install.yaml",
+			golden:   "upgrade-install.golden",
+		},
+		{
+			name:      "upgrade a missing release",
+			cmd:       "upgrade pt testdata/testcharts/mini",
+			scenario:  "scenario-install.yaml",
+			golden:    "upgrade-missing.golden",
+			wantError: true,
+		},
+	}
+	runTestCmd(t, tests)
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"reflect"
+	"sort"
+)
+
+const (
+	valueAdded   = "added"
+	valueRemoved = "removed"
+	valueChanged = "changed"
+)
+
+// valueChange describes a single difference between two sets of values. Key
+// is the dotted path of the value, e.g. 'broker.replicaCount'.
+type valueChange struct {
+	Key    string      `json:"key"`
+	Change string      `json:"change"`
+	Old    interface{} `json:"old,omitempty"`
+	New    interface{} `json:"new,omitempty"`
+}
+
+// diffValues compares two values maps and returns the changes from old to
+// new, sorted by key. Nested maps are compared key by key, everything else
+// (including lists) is compared as a whole.
+func diffValues(old, new map[string]interface{}) []valueChange {
+	oldFlat := map[string]interface{}{}
+	newFlat := map[string]interface{}{}
+	flattenValues("", old, oldFlat)
+	flattenValues("", new, newFlat)
+
+	var changes []valueChange
+	for k, ov := range oldFlat {
+		nv, ok := newFlat[k]
+		switch {
+		case !ok:
+			changes = append(changes, valueChange{Key: k, Change: valueRemoved, Old: ov})
+		case !reflect.DeepEqual(ov, nv):
+			changes = append(changes, valueChange{Key: k, Change: valueChanged, Old: ov, New: nv})
+		}
+	}
+	for k, nv := range newFlat {
+		if _, ok := oldFlat[k]; !ok {
+			changes = append(changes, valueChange{Key: k, Change: valueAdded, New: nv})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Key < changes[j].Key
+	})
+	return changes
+}
+
+// flattenValues writes every leaf of vals into dst, keyed by its dotted path.
+// Empty maps are kept as leaves so that adding or removing them shows up.
+func flattenValues(prefix string, vals map[string]interface{}, dst map[string]interface{}) {
+	for k, v := range vals {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		if m, ok := v.(map[string]interface{}); ok && len(m) > 0 {
+			flattenValues(key, m, dst)
+			continue
+		}
+		dst[key] = v
+	}
+}
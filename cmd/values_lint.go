@@ -0,0 +1,508 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/huangxiaofeng10047/go-cli-example/cmd/i18n"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+
+	chart "helm.sh/helm/v4/pkg/chart/v2"
+	"helm.sh/helm/v4/pkg/cli/values"
+	"helm.sh/helm/v4/pkg/getter"
+)
+
+const (
+	severityError   = "error"
+	severityWarning = "warning"
+)
+
+// setValuesSource is used as the file name of findings in --set style values.
+const setValuesSource = "--set"
+
+// valuesFinding is a single problem found in user supplied values.
+type valuesFinding struct {
+	File     string `json:"file"`
+	Line     int    `json:"line,omitempty"`
+	Column   int    `json:"column,omitempty"`
+	Key      string `json:"key"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// position formats the location of the finding as FILE:LINE:COLUMN.
+func (f valuesFinding) position() string {
+	if f.Line == 0 {
+		return f.File
+	}
+	return fmt.Sprintf("%s:%d:%d", f.File, f.Line, f.Column)
+}
+
+// valuesSchema is the subset of JSON schema needed to check user values. It
+// is either read from values.schema.json or inferred from values.yaml.
+type valuesSchema struct {
+	types      []string
+	properties map[string]*valuesSchema
+	// additional applies to keys that are not listed in properties.
+	additional *valuesSchema
+	// closed reports keys that are not listed in properties as unknown.
+	closed     bool
+	items      *valuesSchema
+	deprecated string
+	// inferred schemas only report scalar type mismatches as warnings, as
+	// templates usually cope with e.g. a number where a string was expected.
+	inferred bool
+}
+
+// jsonSchema is the on-disk JSON schema format.
+type jsonSchema struct {
+	Type                 interface{}            `json:"type"`
+	Properties           map[string]*jsonSchema `json:"properties"`
+	AdditionalProperties json.RawMessage        `json:"additionalProperties"`
+	Items                json.RawMessage        `json:"items"`
+	Deprecated           bool                   `json:"deprecated"`
+	Description          string                 `json:"description"`
+}
+
+func (j *jsonSchema) toValuesSchema() *valuesSchema {
+	if j == nil {
+		return nil
+	}
+	s := &valuesSchema{}
+	switch t := j.Type.(type) {
+	case string:
+		s.types = []string{t}
+	case []interface{}:
+		for _, v := range t {
+			if str, ok := v.(string); ok {
+				s.types = append(s.types, str)
+			}
+		}
+	}
+	if len(j.Properties) > 0 {
+		s.properties = map[string]*valuesSchema{}
+		for k, v := range j.Properties {
+			s.properties[k] = v.toValuesSchema()
+		}
+	}
+	if len(j.AdditionalProperties) > 0 {
+		var closed bool
+		if err := json.Unmarshal(j.AdditionalProperties, &closed); err == nil {
+			s.closed = !closed
+		} else {
+			add := &jsonSchema{}
+			if err := json.Unmarshal(j.AdditionalProperties, add); err == nil {
+				s.additional = add.toValuesSchema()
+			}
+		}
+	}
+	if len(j.Items) > 0 {
+		items := &jsonSchema{}
+		if err := json.Unmarshal(j.Items, items); err == nil {
+			s.items = items.toValuesSchema()
+		}
+	}
+	if j.Deprecated {
+		s.deprecated = "deprecated"
+		if j.Description != "" {
+			s.deprecated = j.Description
+		}
+	}
+	return s
+}
+
+// chartValuesSchema returns the schema for the values of ch, including the
+// values of its subcharts. values.schema.json is used when present, otherwise
+// the schema is inferred from the default values.yaml.
+func chartValuesSchema(ch *chart.Chart) (*valuesSchema, error) {
+	var s *valuesSchema
+	if len(ch.Schema) > 0 {
+		js := &jsonSchema{}
+		if err := json.Unmarshal(ch.Schema, js); err != nil {
+			return nil, errors.Wrap(err, i18n.T("lint.parseSchema", ch.Name()))
+		}
+		s = js.toValuesSchema()
+	} else {
+		node, err := chartValuesNode(ch)
+		if err != nil {
+			return nil, err
+		}
+		s = inferValuesSchema(node)
+	}
+	if s.properties == nil {
+		s.properties = map[string]*valuesSchema{}
+	}
+
+	// 'global' is shared with every subchart and cannot be checked here.
+	s.properties["global"] = &valuesSchema{}
+
+	aliases := map[string]string{}
+	if ch.Metadata != nil {
+		for _, d := range ch.Metadata.Dependencies {
+			if d.Alias != "" {
+				aliases[d.Name] = d.Alias
+			}
+		}
+	}
+	for _, sub := range ch.Dependencies() {
+		subSchema, err := chartValuesSchema(sub)
+		if err != nil {
+			return nil, err
+		}
+		key := sub.Name()
+		if alias, ok := aliases[key]; ok {
+			key = alias
+		}
+		s.properties[key] = mergeValuesSchema(s.properties[key], subSchema)
+	}
+	return s, nil
+}
+
+// chartValuesNode parses the default values.yaml of ch, keeping its comments.
+func chartValuesNode(ch *chart.Chart) (*yaml.Node, error) {
+	for _, f := range ch.Raw {
+		if f.Name == "values.yaml" {
+			node := &yaml.Node{}
+			if err := yaml.Unmarshal(f.Data, node); err != nil {
+				return nil, errors.Wrap(err, i18n.T("lint.parseValues", ch.Name()))
+			}
+			return node, nil
+		}
+	}
+	// Charts loaded from somewhere other than disk have no raw files.
+	node := &yaml.Node{}
+	if err := node.Encode(ch.Values); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+// inferValuesSchema builds a schema from default values. Maps with default
+// keys are closed, empty maps, environment style maps and null values accept
+// anything. A key whose
+// comment mentions 'deprecated' is marked as deprecated.
+func inferValuesSchema(n *yaml.Node) *valuesSchema {
+	if n == nil {
+		return &valuesSchema{inferred: true}
+	}
+	switch n.Kind {
+	case yaml.DocumentNode:
+		if len(n.Content) == 0 {
+			return &valuesSchema{inferred: true, types: []string{"object"}}
+		}
+		return inferValuesSchema(n.Content[0])
+	case yaml.AliasNode:
+		return inferValuesSchema(n.Alias)
+	case yaml.MappingNode:
+		s := &valuesSchema{inferred: true, types: []string{"object"}}
+		if len(n.Content) == 0 {
+			return s
+		}
+		s.closed = !isEnvStyleMap(n)
+		s.properties = map[string]*valuesSchema{}
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			k, v := n.Content[i], n.Content[i+1]
+			child := inferValuesSchema(v)
+			if freeFormValueKeys[k.Value] {
+				child.closed = false
+			}
+			if note := deprecationNote(k); note != "" {
+				child.deprecated = note
+			}
+			s.properties[k.Value] = child
+		}
+		return s
+	case yaml.SequenceNode:
+		return &valuesSchema{inferred: true, types: []string{"array"}}
+	default:
+		t := nodeValueType(n)
+		switch t {
+		case "null":
+			return &valuesSchema{inferred: true}
+		case "integer":
+			t = "number"
+		}
+		return &valuesSchema{inferred: true, types: []string{t}}
+	}
+}
+
+// freeFormValueKeys are maps that are passed through to Kubernetes objects or
+// container configuration as they are, so any key is accepted in them.
+var freeFormValueKeys = map[string]bool{
+	"configData":     true,
+	"annotations":    true,
+	"labels":         true,
+	"nodeSelector":   true,
+	"podAnnotations": true,
+	"podLabels":      true,
+}
+
+// isEnvStyleMap reports whether all keys of a mapping start with an upper
+// case letter, like the 'configData' maps that are passed to containers as
+// environment variables. Such maps take arbitrary keys.
+func isEnvStyleMap(n *yaml.Node) bool {
+	for i := 0; i < len(n.Content); i += 2 {
+		k := n.Content[i].Value
+		if k == "" || k[0] < 'A' || k[0] > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// deprecationNote returns the comment line of the key that mentions
+// 'deprecated', if any.
+func deprecationNote(k *yaml.Node) string {
+	for _, c := range []string{k.HeadComment, k.LineComment} {
+		for _, line := range strings.Split(c, "\n") {
+			if strings.Contains(strings.ToLower(line), "deprecated") {
+				return strings.TrimSpace(strings.TrimLeft(line, "# "))
+			}
+		}
+	}
+	return ""
+}
+
+// mergeValuesSchema merges the properties of b into a. a may be nil.
+func mergeValuesSchema(a, b *valuesSchema) *valuesSchema {
+	if a == nil {
+		return b
+	}
+	if a.properties == nil {
+		a.properties = map[string]*valuesSchema{}
+	}
+	for k, v := range b.properties {
+		a.properties[k] = mergeValuesSchema(a.properties[k], v)
+	}
+	a.closed = a.closed && b.closed
+	if len(a.types) == 0 {
+		a.types = b.types
+	}
+	return a
+}
+
+// nodeValueType returns the JSON schema type of a YAML node.
+func nodeValueType(n *yaml.Node) string {
+	switch n.Kind {
+	case yaml.DocumentNode:
+		if len(n.Content) > 0 {
+			return nodeValueType(n.Content[0])
+		}
+		return "null"
+	case yaml.AliasNode:
+		return nodeValueType(n.Alias)
+	case yaml.MappingNode:
+		return "object"
+	case yaml.SequenceNode:
+		return "array"
+	}
+	switch n.ShortTag() {
+	case "!!null":
+		return "null"
+	case "!!bool":
+		return "boolean"
+	case "!!int":
+		return "integer"
+	case "!!float":
+		return "number"
+	}
+	return "string"
+}
+
+func typeAllowed(t string, allowed []string) bool {
+	for _, a := range allowed {
+		if a == t || (a == "number" && t == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+// valuesValidator walks user supplied values and collects findings.
+type valuesValidator struct {
+	file     string
+	findings []valuesFinding
+}
+
+func (v *valuesValidator) add(n *yaml.Node, key, severity, format string, args ...interface{}) {
+	f := valuesFinding{File: v.file, Key: key, Severity: severity, Message: fmt.Sprintf(format, args...)}
+	if v.file != setValuesSource {
+		f.Line, f.Column = n.Line, n.Column
+	}
+	v.findings = append(v.findings, f)
+}
+
+func (v *valuesValidator) walk(n *yaml.Node, s *valuesSchema, path string) {
+	if s == nil {
+		return
+	}
+	switch n.Kind {
+	case yaml.DocumentNode:
+		if len(n.Content) > 0 {
+			v.walk(n.Content[0], s, path)
+		}
+		return
+	case yaml.AliasNode:
+		v.walk(n.Alias, s, path)
+		return
+	}
+
+	t := nodeValueType(n)
+	// A null value removes the key and is always allowed.
+	if t == "null" {
+		return
+	}
+	if len(s.types) > 0 && !typeAllowed(t, s.types) {
+		severity := severityError
+		if s.inferred && t != "object" && t != "array" && !typeAllowed("object", s.types) && !typeAllowed("array", s.types) {
+			severity = severityWarning
+		}
+		v.add(n, path, severity, i18n.T("lint.typeMismatch"), strings.Join(s.types, i18n.T("lint.or")), t)
+		return
+	}
+
+	switch n.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			k, val := n.Content[i], n.Content[i+1]
+			key := k.Value
+			if path != "" {
+				key = path + "." + k.Value
+			}
+			child, ok := s.properties[k.Value]
+			if !ok {
+				switch {
+				case s.additional != nil:
+					child = s.additional
+				case s.closed:
+					msg := i18n.T("lint.unknownKey")
+					if suggestion := closestKey(k.Value, s.properties); suggestion != "" {
+						msg = i18n.T("lint.didYouMean", suggestion)
+					}
+					v.add(k, key, severityError, "%s", msg)
+					continue
+				default:
+					continue
+				}
+			}
+			if child != nil && child.deprecated != "" {
+				v.add(k, key, severityWarning, i18n.T("lint.deprecated"), child.deprecated)
+			}
+			v.walk(val, child, key)
+		}
+	case yaml.SequenceNode:
+		if s.items == nil {
+			return
+		}
+		for i, item := range n.Content {
+			v.walk(item, s.items, fmt.Sprintf("%s[%d]", path, i))
+		}
+	}
+}
+
+// closestKey returns the known key closest to key, if it is close enough to
+// likely be a typo.
+func closestKey(key string, known map[string]*valuesSchema) string {
+	best, bestDist := "", len(key)/3+1
+	for k := range known {
+		if d := levenshtein(strings.ToLower(key), strings.ToLower(k)); d < bestDist || (d == bestDist && best != "" && k < best) {
+			best, bestDist = k, d
+		}
+	}
+	return best
+}
+
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+// lintValues checks the values given by valueOpts against the schema of ch.
+// Local values files are checked one by one so findings carry their file and
+// line; --set style values are checked together.
+func lintValues(ch *chart.Chart, valueOpts *values.Options, p getter.Providers) ([]valuesFinding, error) {
+	schema, err := chartValuesSchema(ch)
+	if err != nil {
+		return nil, err
+	}
+
+	findings := []valuesFinding{}
+	for _, file := range valueOpts.ValueFiles {
+		if file == "-" || strings.Contains(file, "://") {
+			// stdin and remote files can only be read once; they are
+			// still checked by Helm's own schema validation.
+			continue
+		}
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return nil, errors.Wrap(err, i18n.T("lint.readValues", file))
+		}
+		node := &yaml.Node{}
+		if err := yaml.Unmarshal(b, node); err != nil {
+			return nil, errors.Wrap(err, i18n.T("lint.parseFile", file))
+		}
+		v := &valuesValidator{file: file}
+		v.walk(node, schema, "")
+		findings = append(findings, v.findings...)
+	}
+
+	setOpts := &values.Options{
+		Values:        valueOpts.Values,
+		StringValues:  valueOpts.StringValues,
+		FileValues:    valueOpts.FileValues,
+		JSONValues:    valueOpts.JSONValues,
+		LiteralValues: valueOpts.LiteralValues,
+	}
+	setVals, err := setOpts.MergeValues(p)
+	if err != nil {
+		return nil, err
+	}
+	if len(setVals) > 0 {
+		node := &yaml.Node{}
+		if err := node.Encode(setVals); err != nil {
+			return nil, err
+		}
+		v := &valuesValidator{file: setValuesSource}
+		v.walk(node, schema, "")
+		findings = append(findings, v.findings...)
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].File != findings[j].File {
+			return findings[i].File < findings[j].File
+		}
+		return findings[i].Line < findings[j].Line
+	})
+	return findings, nil
+}
+
+// countFindings returns the number of errors and warnings.
+func countFindings(findings []valuesFinding) (errs, warnings int) {
+	for _, f := range findings {
+		if f.Severity == severityError {
+			errs++
+		} else {
+			warnings++
+		}
+	}
+	return errs, warnings
+}
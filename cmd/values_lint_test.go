@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	chart "helm.sh/helm/v4/pkg/chart/v2"
+	"helm.sh/helm/v4/pkg/cli/values"
+	"helm.sh/helm/v4/pkg/getter"
+)
+
+const lintTestValues = `broker:
+  replicaCount: 3
+  # Deprecated: use broker.resources instead.
+  memory: 2Gi
+  resources: {}
+  configData:
+    PULSAR_MEM: "-Xmx1g"
+  tolerations: []
+zookeeper:
+  enabled: true
+auth:
+  token: null
+`
+
+const lintTestSchema = `{
+  "type": "object",
+  "properties": {
+    "image": {
+      "type": "object",
+      "additionalProperties": false,
+      "properties": {
+        "tag": {"type": "string"},
+        "pullPolicy": {"type": "string"}
+      }
+    },
+    "replicas": {"type": "integer"}
+  }
+}`
+
+func TestLintValues(t *testing.T) {
+	inferred := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "pulsar"},
+		Raw:      []*chart.File{{Name: "values.yaml", Data: []byte(lintTestValues)}},
+	}
+	withSchema := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "pulsar"},
+		Schema:   []byte(lintTestSchema),
+	}
+
+	tests := []struct {
+		name   string
+		chart  *chart.Chart
+		values string
+		set    []string
+		want   []string
+	}{
+		{
+			name:   "known keys",
+			chart:  inferred,
+			values: "broker:\n  replicaCount: 5\n  tolerations: [{key: dedicated}]\nauth:\n  token: {secretName: admin}\n",
+		},
+		{
+			name:   "typo of a known key",
+			chart:  inferred,
+			values: "broker:\n  replicaCont: 5\n",
+			want:   []string{"broker.replicaCont error"},
+		},
+		{
+			name:   "scalar type mismatch is a warning",
+			chart:  inferred,
+			values: "broker:\n  replicaCount: three\n",
+			want:   []string{"broker.replicaCount warning"},
+		},
+		{
+			name:   "map given for a scalar is an error",
+			chart:  inferred,
+			values: "zookeeper:\n  enabled: {value: true}\n",
+			want:   []string{"zookeeper.enabled error"},
+		},
+		{
+			name:   "deprecated key",
+			chart:  inferred,
+			values: "broker:\n  memory: 4Gi\n",
+			want:   []string{"broker.memory warning"},
+		},
+		{
+			name:   "free-form and global keys",
+			chart:  inferred,
+			values: "broker:\n  configData:\n    PULSAR_GC: -XX:+UseG1GC\n  resources:\n    requests: {cpu: 1}\nglobal:\n  anything: true\n",
+		},
+		{
+			name:  "set values",
+			chart: inferred,
+			set:   []string{"broker.replicas=3"},
+			want:  []string{"broker.replicas error"},
+		},
+		{
+			name:   "schema types are errors",
+			chart:  withSchema,
+			values: "replicas: \"3\"\nimage:\n  tag: 3.3.1\n  pullPolicies: Always\n",
+			want:   []string{"image.pullPolicies error", "replicas error"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := &values.Options{Values: tt.set}
+			if tt.values != "" {
+				file := filepath.Join(t.TempDir(), "values.yaml")
+				if err := os.WriteFile(file, []byte(tt.values), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				opts.ValueFiles = []string{file}
+			}
+			findings, err := lintValues(tt.chart, opts, getter.Providers{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, f := range findings {
+				got = append(got, f.Key+" "+f.Severity)
+			}
+			slices.Sort(got)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("expected findings %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestClosestKey(t *testing.T) {
+	known := map[string]*valuesSchema{"replicaCount": nil, "resources": nil, "tolerations": nil}
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"replicaCont", "replicaCount"},
+		{"ReplicaCount", "replicaCount"},
+		{"resource", "resources"},
+		{"affinity", ""},
+	}
+	for _, tt := range tests {
+		if got := closestKey(tt.key, known); got != tt.want {
+			t.Errorf("%s: expected %q, got %q", tt.key, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/huangxiaofeng10047/go-cli-example/cmd/i18n"
+	"golang.org/x/term"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+
+	"helm.sh/helm/v4/pkg/action"
+	"helm.sh/helm/v4/pkg/cli/output"
+)
+
+// clearScreen moves the cursor home and clears the terminal.
+const clearScreen = "\033[H\033[2J"
+
+// watchDebounce is how long to wait for more changes before re-rendering,
+// so that a burst of pod updates results in a single render.
+const watchDebounce = 300 * time.Millisecond
+
+// watchOptions describes what a watch should follow.
+type watchOptions struct {
+	// namespace to watch, empty for all namespaces.
+	namespace string
+	// releaseName restricts the watch to a single release, empty for all.
+	releaseName string
+	// pods also watches the pods that belong to releaseName.
+	pods bool
+	// interval is used to poll storage drivers that cannot be watched
+	// (memory and sql).
+	interval time.Duration
+}
+
+// watchChanges renders once and then again every time the release storage or
+// the pods of the release change, until ctx is done. Secret and configmap
+// storage is followed with informers; other drivers are polled.
+func watchChanges(ctx context.Context, cfg *action.Configuration, opts watchOptions, render func() error) error {
+	if err := render(); err != nil {
+		return err
+	}
+
+	changed := make(chan struct{}, 1)
+	notify := func() {
+		select {
+		case changed <- struct{}{}:
+		default:
+		}
+	}
+	handler := cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(interface{}) { notify() },
+		UpdateFunc: func(interface{}, interface{}) { notify() },
+		DeleteFunc: func(interface{}) { notify() },
+	}
+
+	// Only secret and configmap storage and pods need the cluster; memory and
+	// sql storage are polled without connecting to it.
+	driver := normalizeDriver(os.Getenv("HELM_DRIVER"))
+	poll := driver != "secret" && driver != "configmap"
+	pods := opts.pods && opts.releaseName != ""
+	if poll && !pods {
+		return pollChanges(ctx, opts.interval, nil, render)
+	}
+
+	clientset, err := cfg.KubernetesClientSet()
+	if err != nil {
+		return err
+	}
+
+	if !poll {
+		storageSelector := "owner=helm"
+		if opts.releaseName != "" {
+			storageSelector += ",name=" + opts.releaseName
+		}
+		storageFactory := informers.NewSharedInformerFactoryWithOptions(clientset, 0,
+			informers.WithNamespace(opts.namespace),
+			informers.WithTweakListOptions(func(o *metav1.ListOptions) {
+				o.LabelSelector = storageSelector
+			}))
+		informer := storageFactory.Core().V1().Secrets().Informer()
+		if driver == "configmap" {
+			informer = storageFactory.Core().V1().ConfigMaps().Informer()
+		}
+		if _, err := informer.AddEventHandler(handler); err != nil {
+			return err
+		}
+		storageFactory.Start(ctx.Done())
+	}
+
+	if pods {
+		podFactory := informers.NewSharedInformerFactoryWithOptions(clientset, 0, informers.WithNamespace(opts.namespace))
+		_, err := podFactory.Core().V1().Pods().Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+			FilterFunc: func(obj interface{}) bool {
+				if d, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+					obj = d.Obj
+				}
+				pod, ok := obj.(*corev1.Pod)
+				return ok && isReleasePod(pod, opts.releaseName)
+			},
+			Handler: handler,
+		})
+		if err != nil {
+			return err
+		}
+		podFactory.Start(ctx.Done())
+	}
+
+	interval := opts.interval
+	if !poll {
+		interval = 0
+	}
+	return pollChanges(ctx, interval, changed, render)
+}
+
+// pollChanges renders again every interval, if it is not zero, and every time
+// changed is signaled, until ctx is done.
+func pollChanges(ctx context.Context, interval time.Duration, changed <-chan struct{}, render func() error) error {
+	var tick <-chan time.Time
+	if interval > 0 {
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		tick = t.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-changed:
+		case <-tick:
+		}
+
+		// Let a burst of changes settle before rendering.
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(watchDebounce):
+		}
+
+		if err := render(); err != nil {
+			fmt.Fprintln(os.Stderr, i18n.T("watch.renderFailed", err))
+		}
+	}
+}
+
+// isReleasePod reports whether pod was created for the named release. Both
+// the Pulsar chart 'release' label and the recommended
+// 'app.kubernetes.io/instance' label are accepted.
+func isReleasePod(pod *corev1.Pod, releaseName string) bool {
+	return pod.Labels["release"] == releaseName || pod.Labels["app.kubernetes.io/instance"] == releaseName
+}
+
+// watchRenderer writes watch output. On a terminal the table is redrawn in
+// place; otherwise every change is written as one JSON line (or a YAML
+// document when -o yaml is used). Output identical to the previous render is
+// skipped.
+type watchRenderer struct {
+	out    io.Writer
+	outfmt output.Format
+	tty    bool
+	last   []byte
+}
+
+func newWatchRenderer(out io.Writer, outfmt output.Format) *watchRenderer {
+	return &watchRenderer{out: out, outfmt: outfmt, tty: isTerminal(out)}
+}
+
+func (w *watchRenderer) render(writer output.Writer) error {
+	format := w.outfmt
+	if format == output.Table && !w.tty {
+		format = output.JSON
+	}
+
+	buf := new(bytes.Buffer)
+	if err := format.Write(buf, writer); err != nil {
+		return err
+	}
+	if bytes.Equal(buf.Bytes(), w.last) {
+		return nil
+	}
+	w.last = buf.Bytes()
+
+	switch format {
+	case output.Table:
+		fmt.Fprint(w.out, clearScreen)
+	case output.YAML:
+		fmt.Fprintln(w.out, "---")
+	}
+	_, err := w.out.Write(buf.Bytes())
+	return err
+}
+
+// isTerminal reports whether out is an interactive terminal.
+func isTerminal(out io.Writer) bool {
+	f, ok := out.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"helm.sh/helm/v4/pkg/action"
+)
+
+// TestWatchChangesPollsWithoutCluster checks that memory storage is polled
+// without a connection to the cluster, which the configuration lacks.
+func TestWatchChangesPollsWithoutCluster(t *testing.T) {
+	t.Setenv("HELM_DRIVER", "memory")
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	renders := 0
+	err := watchChanges(ctx, new(action.Configuration), watchOptions{interval: time.Millisecond}, func() error {
+		if renders++; renders == 2 {
+			cancel()
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if renders < 2 {
+		t.Errorf("expected the storage to be polled, got %d render(s)", renders)
+	}
+}
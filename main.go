@@ -14,20 +14,16 @@ import (
 	"helm.sh/helm/v4/pkg/storage/driver"
 	"io"
 	"log"
+	"log/slog"
 	"os"
 	"sigs.k8s.io/yaml"
 	"strings"
-	"time"
 )
 
 var settings = cli.New()
 
 func debug(format string, v ...interface{}) {
-	if settings.Debug {
-		timeNow := time.Now().String()
-		format = fmt.Sprintf("%s [debug] %s\n", timeNow, format)
-		log.Output(2, fmt.Sprintf(format, v...))
-	}
+	slog.Debug(fmt.Sprintf(format, v...))
 }
 func warning(format string, v ...interface{}) {
 	format = fmt.Sprintf("WARNING: %s\n", format)
@@ -51,7 +47,11 @@ func main() {
 		if err := actionConfig.Init(settings.RESTClientGetter(), settings.Namespace(), helmDriver, debug); err != nil {
 			log.Fatal(err)
 		}
-		if helmDriver == "memory" {
+		if scenario := os.Getenv(mycmd.ScenarioEnvVar); scenario != "" {
+			if err := mycmd.LoadScenario(settings, actionConfig, scenario); err != nil {
+				log.Fatal(err)
+			}
+		} else if helmDriver == "memory" {
 			loadReleasesInMemory(actionConfig)
 		}
 		actionConfig.SetHookOutputFunc(hookOutputWriter)